@@ -11,6 +11,7 @@ import (
 	"github.com/alir32a/jupiter/internal/service"
 	"github.com/alir32a/jupiter/pkg/jwt"
 	"github.com/alir32a/jupiter/pkg/ocserv"
+	"github.com/alir32a/jupiter/pkg/tg"
 	clog "github.com/charmbracelet/log"
 	ejwt "github.com/labstack/echo-jwt"
 	"github.com/labstack/echo/v4"
@@ -19,6 +20,7 @@ import (
 	"gorm.io/gorm"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 )
@@ -38,13 +40,17 @@ func main() {
 		logger.Fatal(err)
 	}
 
+	if !slices.Contains(model.PackageConsumptionOrders, cfg.Package.ConsumptionOrder) {
+		logger.Fatal("invalid package consumption order", "order", cfg.Package.ConsumptionOrder)
+	}
+
 	if err := runManager(cfg, logger); err != nil {
 		logger.Fatal(err)
 	}
 }
 
 func runManager(cfg *config.Config, logger *clog.Logger) error {
-	ocservClient := ocserv.NewClient(cfg.OCCTL.PasswordFile)
+	ocservClient := ocserv.NewClient(cfg.OCCTL.PasswordFile, cfg.OCCTL.ConfigPerUserDir)
 
 	db := setupDB(cfg, logger)
 
@@ -52,11 +58,55 @@ func runManager(cfg *config.Config, logger *clog.Logger) error {
 	connectionRepo := repository.NewConnectionRepository(db)
 	packageRepo := repository.NewPackageRepository(db)
 	adminRepo := repository.NewAdminRepository(db)
+	referralRepo := repository.NewReferralRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	planRepo := repository.NewPlanRepository(db)
+	conversationRepo := repository.NewConversationRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	broadcastRepo := repository.NewBroadcastRepository(db)
+	messageTemplateRepo := repository.NewMessageTemplateRepository(db)
+	credentialRepo := repository.NewCredentialRepository(db)
+	callbackRepo := repository.NewCallbackRepository(db)
+	supportRepo := repository.NewSupportRepository(db)
+	botRepo := repository.NewBotRepository(db)
+	resellerRepo := repository.NewResellerRepository(db)
+	transactor := repository.NewTransactor(db)
+
+	tgBot := tg.NewBot(cfg.MainBot.Token)
+	tgBots := tg.NewBots()
+	tgBots.Add(model.MainBotID, tgBot)
+
+	messageTemplateSvc := service.NewMessageTemplateService(messageTemplateRepo, auditLogRepo, logger)
+	notificationSvc := service.NewNotificationService(cfg.Notification, logger, notificationRepo, userRepo, packageRepo,
+		tgBots, messageTemplateSvc)
+	userSvc := service.NewUserService(cfg, logger, ocservClient, userRepo, packageRepo, resellerRepo,
+		notificationSvc)
+	connectionSvc := service.NewConnectionService(cfg.Package, logger, ocservClient, connectionRepo, packageRepo, userRepo,
+		notificationSvc)
+	referralSvc := service.NewReferralService(cfg.Referral, logger, referralRepo, userRepo, packageRepo,
+		transactor)
+	packageSvc := service.NewPackageService(cfg.Package, logger, packageRepo, userRepo, planRepo, auditLogRepo, resellerRepo,
+		referralSvc, connectionSvc, transactor)
+	adminSvc := service.NewAdminService(cfg.MainBot, adminRepo, logger)
+	auditLogSvc := service.NewAuditLogService(auditLogRepo, logger)
+	planSvc := service.NewPlanService(planRepo, logger)
+	conversationSvc := service.NewConversationService(conversationRepo, logger)
+	broadcastSvc := service.NewBroadcastService(cfg.Broadcast, logger, broadcastRepo, tgBot)
+	credentialSvc := service.NewCredentialService(logger, credentialRepo, tgBots)
+	profileSvc := service.NewProfileService(cfg.VPN, logger)
+	callbackSvc := service.NewCallbackService(callbackRepo, logger)
+	supportSvc := service.NewSupportService(cfg.MainBot, logger, supportRepo, userRepo, tgBots, messageTemplateSvc)
+
+	newBot := func(botCfg *config.MainBotConfig, client *tg.Bot) *bot.MainBot {
+		return bot.NewMainBot(botCfg, logger, client, userSvc, connectionSvc, packageSvc, referralSvc,
+			adminSvc, conversationSvc, messageTemplateSvc, credentialSvc,
+			profileSvc, callbackSvc, supportSvc, planSvc, cfg.TrialPackage, tgBot)
+	}
 
-	userSvc := service.NewUserService(cfg, logger, ocservClient, userRepo, packageRepo)
-	connectionSvc := service.NewConnectionService(logger, ocservClient, connectionRepo, packageRepo, userRepo)
-	packageSvc := service.NewPackageService(logger, packageRepo, userRepo)
-	adminSvc := service.NewAdminService(adminRepo, logger)
+	botManager := bot.NewManager(cfg.MainBot, logger, tgBots, newBot)
+	botSvc := service.NewBotService(logger, botRepo, adminRepo, auditLogRepo, botManager)
+	resellerSvc := service.NewResellerService(logger, resellerRepo, adminRepo, botRepo, auditLogRepo, userSvc,
+		packageSvc, transactor)
 
 	server := handler.NewHTTPServer(cfg.HTTPServerConfig, logger)
 
@@ -99,23 +149,92 @@ func runManager(cfg *config.Config, logger *clog.Logger) error {
 	packagesCtrl := handler.NewPackageHandler(packageSvc, logger)
 	packagesCtrl.SetRoutes(auth)
 
+	plansCtrl := handler.NewPlanHandler(planSvc, logger)
+	plansCtrl.SetRoutes(auth)
+
 	usersCtrl := handler.NewUserHandler(userSvc, logger)
 	usersCtrl.SetRoutes(auth)
 
+	auditLogsCtrl := handler.NewAuditLogHandler(auditLogSvc, logger)
+	auditLogsCtrl.SetRoutes(auth)
+
+	broadcastsCtrl := handler.NewBroadcastHandler(broadcastSvc, logger)
+	broadcastsCtrl.SetRoutes(auth)
+
+	messageTemplatesCtrl := handler.NewMessageTemplateHandler(messageTemplateSvc, logger)
+	messageTemplatesCtrl.SetRoutes(auth)
+
+	botMetricsCtrl := handler.NewBotMetricsHandler(tgBot)
+	botMetricsCtrl.SetRoutes(auth)
+
+	settingsCtrl := handler.NewSettingHandler(cfg)
+	settingsCtrl.SetRoutes(auth)
+
+	supportCtrl := handler.NewSupportHandler(supportSvc, logger)
+	supportCtrl.SetRoutes(auth)
+
+	botsCtrl := handler.NewBotHandler(botSvc, logger)
+	botsCtrl.SetRoutes(auth)
+
+	resellersCtrl := handler.NewResellerHandler(resellerSvc, logger)
+	resellersCtrl.SetRoutes(auth)
+	resellersCtrl.SetAPIRoutes(server.Group("/api/v1/reseller"))
+
+	mainBot := newBot(cfg.MainBot, tgBot)
+
+	if cfg.MainBot.Mode == bot.ModeWebhook {
+		botWebhookCtrl := handler.NewBotWebhookHandler(mainBot, botManager, cfg.MainBot.WebhookSecret, logger)
+		botWebhookCtrl.SetRoutes(noAuth)
+	}
+
 	go func() {
 		if err := server.Run(); err != nil {
 			logger.Fatal(err)
 		}
 	}()
 
-	mainBot := bot.NewMainBot(cfg.MainBot, logger, userSvc, connectionSvc, packageSvc)
-
 	go func() {
 		if err := mainBot.Run(); err != nil {
 			logger.Fatal(err.Error())
 		}
 	}()
 
+	if err := botSvc.StartBots(context.Background()); err != nil {
+		logger.Error(err.Error())
+	}
+
+	if err := broadcastSvc.ResumeBroadcasts(context.Background()); err != nil {
+		logger.Error(err.Error())
+	}
+
+	go func() {
+		for range time.Tick(cfg.Notification.CheckInterval) {
+			if err := notificationSvc.NotifyExpiringPackages(context.Background()); err != nil {
+				logger.Error(err.Error())
+			}
+		}
+	}()
+
+	go func() {
+		for range time.Tick(time.Minute) {
+			if err := credentialSvc.DeleteDueMessages(context.Background()); err != nil {
+				logger.Error(err.Error())
+			}
+
+			if err := credentialSvc.DeleteExpiredReveals(context.Background()); err != nil {
+				logger.Error(err.Error())
+			}
+
+			if err := callbackSvc.DeleteExpiredPayloads(context.Background()); err != nil {
+				logger.Error(err.Error())
+			}
+
+			if err := userSvc.DeleteExpiredLinkCodes(context.Background()); err != nil {
+				logger.Error(err.Error())
+			}
+		}
+	}()
+
 	ticker := time.Tick(cfg.Manager.UpdateInterval * time.Second)
 
 	var failureCount int
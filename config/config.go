@@ -14,6 +14,11 @@ type Config struct {
 	MainBot          *MainBotConfig
 	OCCTL            *OCCTLConfig
 	TrialPackage     *TrialPackageConfig
+	Referral         *ReferralConfig
+	Package          *PackageConfig
+	Notification     *NotificationConfig
+	Broadcast        *BroadcastConfig
+	VPN              *VPNConfig
 }
 
 type DBConfig struct {
@@ -42,12 +47,40 @@ type HTTPServerConfig struct {
 }
 
 type MainBotConfig struct {
+	// ID is the id of the bot in the bot table, it's 0 for the main bot and set for the bots of the resellers,
+	// which run with a copy of the main bot's config.
+	ID       int    `ignored:"true"`
 	Token    string `envconfig:"MAIN_BOT_TOKEN"`
-	OCCTLCfg OCCTLConfig
+	Username string `envconfig:"MAIN_BOT_USERNAME"`
+	// Mode is either polling or webhook, the bot falls back to polling if the webhook can't be set.
+	Mode               string `envconfig:"MAIN_BOT_MODE" default:"polling"`
+	WebhookUrl         string `envconfig:"MAIN_BOT_WEBHOOK_URL"`
+	WebhookSecret      string `envconfig:"MAIN_BOT_WEBHOOK_SECRET"`
+	Workers            int    `envconfig:"MAIN_BOT_WORKERS" default:"8"`
+	RateLimitPerMinute int    `envconfig:"MAIN_BOT_RATE_LIMIT_PER_MINUTE" default:"30"`
+	// AdminIDs are the telegram user ids allowed to use the admin commands, admins can also link their
+	// telegram account from the panel.
+	AdminIDs []int `envconfig:"MAIN_BOT_ADMIN_IDS"`
+	// ConversationTimeout is how long multi-step conversations wait for the user's reply.
+	ConversationTimeout time.Duration `envconfig:"MAIN_BOT_CONVERSATION_TIMEOUT" default:"10m"`
+	// CredentialMessageTTL is how long the messages about the credentials stay in the chat, passwords can
+	// be revealed until then.
+	CredentialMessageTTL time.Duration `envconfig:"MAIN_BOT_CREDENTIAL_MESSAGE_TTL" default:"1h"`
+	// MenuTTL is how long the buttons of the menus keep working.
+	MenuTTL time.Duration `envconfig:"MAIN_BOT_MENU_TTL" default:"24h"`
+	// LinkCodeTTL is how long the codes for linking an account to an existing user can be used.
+	LinkCodeTTL time.Duration `envconfig:"MAIN_BOT_LINK_CODE_TTL" default:"15m"`
+	// MaxAccounts is how many accounts a telegram user can have, including their own user.
+	MaxAccounts int `envconfig:"MAIN_BOT_MAX_ACCOUNTS" default:"5"`
+	// SupportChatID is the chat, usually a group of the admins, support tickets are sent to. admins answer a
+	// ticket by replying to its message there, tickets are only kept for the panel if it's not set.
+	SupportChatID int `envconfig:"MAIN_BOT_SUPPORT_CHAT_ID"`
+	OCCTLCfg      OCCTLConfig
 }
 
 type OCCTLConfig struct {
-	PasswordFile string `envconfig:"OCCTL_PASSWORD_FILE"`
+	PasswordFile     string `envconfig:"OCCTL_PASSWORD_FILE"`
+	ConfigPerUserDir string `envconfig:"OCCTL_CONFIG_PER_USER_DIR" default:"/etc/ocserv/config-per-user"`
 }
 
 type TrialPackageConfig struct {
@@ -55,6 +88,62 @@ type TrialPackageConfig struct {
 	TrafficLimit     float64 `envconfig:"TRIAL_PACKAGE_TRAFFIC_LIMIT" default:"5"`
 	MaxConnections   int     `envconfig:"TRIAL_PACKAGE_MAX_CONNECTIONS" default:"2"`
 	ExpirationInDays int     `envconfig:"TRIAL_PACKAGE_EXPIRATION" default:"7"`
+	// RequiredChannel is the channel users must join before they get the trial, either its username
+	// (e.g. @jupiter) or its id. the main bot must be an administrator of the channel to see its members.
+	RequiredChannel string `envconfig:"TRIAL_PACKAGE_REQUIRED_CHANNEL"`
+	// Captcha makes users answer a simple question before they get the trial.
+	Captcha bool `envconfig:"TRIAL_PACKAGE_CAPTCHA" default:"false"`
+	// CaptchaAttempts is the number of wrong answers after which the user loses the trial.
+	CaptchaAttempts int `envconfig:"TRIAL_PACKAGE_CAPTCHA_ATTEMPTS" default:"3"`
+}
+
+// Gated reports whether users have to pass a check before they get the trial.
+func (t TrialPackageConfig) Gated() bool {
+	return t.Activated && (t.RequiredChannel != "" || t.Captcha)
+}
+
+type ReferralConfig struct {
+	Activated                  bool    `envconfig:"REFERRAL_ACTIVATED" default:"false"`
+	ReferrerTrafficBonus       float64 `envconfig:"REFERRAL_REFERRER_TRAFFIC_BONUS" default:"0"`
+	ReferrerDaysBonus          int     `envconfig:"REFERRAL_REFERRER_DAYS_BONUS" default:"0"`
+	ReferrerWalletCredit       int     `envconfig:"REFERRAL_REFERRER_WALLET_CREDIT" default:"0"`
+	RefereeTrafficBonus        float64 `envconfig:"REFERRAL_REFEREE_TRAFFIC_BONUS" default:"0"`
+	RefereeDaysBonus           int     `envconfig:"REFERRAL_REFEREE_DAYS_BONUS" default:"0"`
+	RefereeWalletCredit        int     `envconfig:"REFERRAL_REFEREE_WALLET_CREDIT" default:"0"`
+	BonusPackageMaxConnections int     `envconfig:"REFERRAL_BONUS_PACKAGE_MAX_CONNECTIONS" default:"1"`
+	BonusPackageExpiration     int     `envconfig:"REFERRAL_BONUS_PACKAGE_EXPIRATION" default:"30"`
+}
+
+type PackageConfig struct {
+	// ConsumptionOrder is the order reserved packages get activated and charged in, one of fifo,
+	// smallest_first, expiring_soonest_first and trial_last.
+	ConsumptionOrder string `envconfig:"PACKAGE_CONSUMPTION_ORDER" default:"fifo"`
+}
+
+type NotificationConfig struct {
+	Activated bool `envconfig:"NOTIFICATION_ACTIVATED" default:"true"`
+	// UsageThresholds are the percentages of the package traffic users get notified at.
+	UsageThresholds []int         `envconfig:"NOTIFICATION_USAGE_THRESHOLDS" default:"80,95,100"`
+	ExpiryReminder  time.Duration `envconfig:"NOTIFICATION_EXPIRY_REMINDER" default:"72h"`
+	CheckInterval   time.Duration `envconfig:"NOTIFICATION_CHECK_INTERVAL" default:"1h"`
+}
+
+type BroadcastConfig struct {
+	// MessagesPerSecond is kept below telegram's limit of 30 messages per second.
+	MessagesPerSecond int `envconfig:"BROADCAST_MESSAGES_PER_SECOND" default:"25"`
+	MaxAttempts       int `envconfig:"BROADCAST_MAX_ATTEMPTS" default:"3"`
+	// RetryDelay is the delay before the first retry of a failed delivery, it doubles on every retry.
+	RetryDelay time.Duration `envconfig:"BROADCAST_RETRY_DELAY" default:"30s"`
+}
+
+// VPNConfig is the ocserv server users connect to, it's used in the connection profiles the bot sends.
+type VPNConfig struct {
+	Host        string `envconfig:"VPN_HOST"`
+	Port        int    `envconfig:"VPN_PORT" default:"443"`
+	ProfileName string `envconfig:"VPN_PROFILE_NAME" default:"jupiter"`
+	// CertPin is the pin of the server certificate as openconnect prints it (e.g. pin-sha256:...), it's
+	// only needed if the certificate is self-signed.
+	CertPin string `envconfig:"VPN_CERT_PIN"`
 }
 
 func GetConfig() (*Config, error) {
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS wallet_balance bigint not null default 0;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS bonus_days int not null default 0;
+
+CREATE TABLE IF NOT EXISTS "referral_invite" (
+  external_id varchar(256) primary key,
+  referral_code varchar(32) not null references "user"(referral_code),
+  created_at timestamptz not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS "referral_reward" (
+  id bigserial primary key,
+  referrer_id bigint not null,
+  referee_id bigint unique not null,
+  referrer_traffic bigint not null default 0,
+  referrer_days int not null default 0,
+  referrer_credit bigint not null default 0,
+  referee_traffic bigint not null default 0,
+  referee_days int not null default 0,
+  referee_credit bigint not null default 0,
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX "referral_reward_referrer_id" on "referral_reward" (referrer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "referral_reward";
+DROP TABLE IF EXISTS "referral_invite";
+ALTER TABLE "user" DROP COLUMN IF EXISTS bonus_days;
+ALTER TABLE "user" DROP COLUMN IF EXISTS wallet_balance;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "package" ADD COLUMN IF NOT EXISTS canceled_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS "audit_log" (
+  id bigserial primary key,
+  actor varchar(64) not null,
+  action varchar(64) not null,
+  resource varchar(64) not null,
+  resource_id bigint not null,
+  details text not null default '',
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX "audit_log_resource" on "audit_log" (resource, resource_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "audit_log";
+ALTER TABLE "package" DROP COLUMN IF EXISTS canceled_at;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "package" ADD COLUMN IF NOT EXISTS package_type varchar(16) not null default 'standard';
+ALTER TABLE "package" ADD COLUMN IF NOT EXISTS activated_at timestamptz;
+
+UPDATE "package" SET activated_at = created_at WHERE expire_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "package" DROP COLUMN IF EXISTS activated_at;
+ALTER TABLE "package" DROP COLUMN IF EXISTS package_type;
+-- +goose StatementEnd
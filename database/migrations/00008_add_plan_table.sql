@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "plan" (
+  id bigserial primary key,
+  name varchar(64) not null,
+  package_type varchar(16) not null default 'standard',
+  traffic_limit bigint not null default 0,
+  max_connections int not null,
+  expiration_in_days int not null default 0,
+  fair_use_cap bigint not null default 0,
+  throttle_rate bigint not null default 0,
+  is_active boolean not null default true,
+  created_at timestamptz not null default now()
+);
+
+ALTER TABLE "package" ADD COLUMN IF NOT EXISTS plan_id bigint references "plan"(id);
+ALTER TABLE "package" ADD COLUMN IF NOT EXISTS fair_use_cap bigint not null default 0;
+ALTER TABLE "package" ADD COLUMN IF NOT EXISTS throttle_rate bigint not null default 0;
+
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS throttled_at timestamptz;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "user" DROP COLUMN IF EXISTS throttled_at;
+
+ALTER TABLE "package" DROP COLUMN IF EXISTS throttle_rate;
+ALTER TABLE "package" DROP COLUMN IF EXISTS fair_use_cap;
+ALTER TABLE "package" DROP COLUMN IF EXISTS plan_id;
+
+DROP TABLE IF EXISTS "plan";
+-- +goose StatementEnd
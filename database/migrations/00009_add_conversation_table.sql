@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "conversation" (
+  chat_id bigint primary key,
+  name varchar(64) not null,
+  step varchar(64) not null,
+  data jsonb not null default '{}',
+  expire_at timestamptz not null,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "conversation";
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS telegram_id varchar(256) unique;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "admin" DROP COLUMN IF EXISTS telegram_id;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS notifications_enabled boolean not null default true;
+
+CREATE TABLE IF NOT EXISTS "notification" (
+  id bigserial primary key,
+  user_id bigint not null,
+  kind varchar(32) not null,
+  reference varchar(64) not null,
+  created_at timestamptz not null default now(),
+  unique (user_id, kind, reference)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "notification";
+ALTER TABLE "user" DROP COLUMN IF EXISTS notifications_enabled;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "broadcast" (
+  id bigserial primary key,
+  text text not null,
+  parse_mode varchar(16) not null default '',
+  button_text varchar(64) not null default '',
+  button_url varchar(256) not null default '',
+  filter varchar(16) not null,
+  status varchar(16) not null,
+  total int not null default 0,
+  sent int not null default 0,
+  failed int not null default 0,
+  created_by varchar(64) not null,
+  created_at timestamptz not null default now(),
+  finished_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS "broadcast_delivery" (
+  id bigserial primary key,
+  broadcast_id bigint not null references "broadcast"(id),
+  user_id bigint not null,
+  chat_id varchar(256) not null,
+  status varchar(16) not null default 'pending',
+  attempts int not null default 0,
+  error text not null default '',
+  next_attempt_at timestamptz not null default now(),
+  updated_at timestamptz not null default now(),
+  unique (broadcast_id, user_id)
+);
+
+CREATE INDEX "broadcast_delivery_broadcast_id_status" on "broadcast_delivery" (broadcast_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "broadcast_delivery";
+DROP TABLE IF EXISTS "broadcast";
+-- +goose StatementEnd
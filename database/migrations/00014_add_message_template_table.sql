@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "message_template" (
+  id bigserial primary key,
+  key varchar(64) not null,
+  language varchar(8) not null,
+  parse_mode varchar(16) not null default '',
+  body text not null,
+  updated_by varchar(64) not null,
+  updated_at timestamptz not null default now(),
+  unique (key, language)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "message_template";
+-- +goose StatementEnd
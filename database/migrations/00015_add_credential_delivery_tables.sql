@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "scheduled_message_deletion" (
+  id bigserial primary key,
+  chat_id bigint not null,
+  message_id bigint not null,
+  delete_at timestamptz not null,
+  attempts int not null default 0,
+  unique (chat_id, message_id)
+);
+
+CREATE INDEX "scheduled_message_deletion_delete_at" on "scheduled_message_deletion" (delete_at);
+
+CREATE TABLE IF NOT EXISTS "credential_reveal" (
+  id bigserial primary key,
+  token_hash varchar(64) not null unique,
+  chat_id bigint not null,
+  username varchar(256) not null,
+  sealed_password text not null,
+  expire_at timestamptz not null,
+  created_at timestamptz not null default now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "credential_reveal";
+DROP TABLE IF EXISTS "scheduled_message_deletion";
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "callback_payload" (
+  key varchar(32) primary key,
+  chat_id bigint not null,
+  data jsonb not null default '{}',
+  expire_at timestamptz not null,
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX "callback_payload_expire_at" on "callback_payload" (expire_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "callback_payload";
+-- +goose StatementEnd
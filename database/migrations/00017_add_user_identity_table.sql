@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "user_identity" (
+  id bigserial primary key,
+  user_id bigint not null,
+  provider varchar(64) not null,
+  external_id varchar(256) not null,
+  created_at timestamptz not null default now(),
+  unique (provider, external_id)
+);
+
+CREATE INDEX "user_identity_user_id" on "user_identity" (user_id);
+
+INSERT INTO "user_identity" (user_id, provider, external_id)
+SELECT id, user_type, external_id FROM "user"
+WHERE external_id IS NOT NULL AND external_id <> '' AND deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS "link_code" (
+  code_hash varchar(64) primary key,
+  user_id bigint not null,
+  expire_at timestamptz not null,
+  created_at timestamptz not null default now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "link_code";
+DROP TABLE IF EXISTS "user_identity";
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS owner_id bigint;
+
+CREATE INDEX "user_owner_id" on "user" (owner_id);
+
+ALTER TABLE "user_identity" ADD COLUMN IF NOT EXISTS active_user_id bigint;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "user_identity" DROP COLUMN IF EXISTS active_user_id;
+DROP INDEX IF EXISTS "user_owner_id";
+ALTER TABLE "user" DROP COLUMN IF EXISTS owner_id;
+-- +goose StatementEnd
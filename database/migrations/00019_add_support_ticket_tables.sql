@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "support_ticket" (
+  id bigserial primary key,
+  user_id bigint,
+  chat_id varchar(256) not null,
+  status varchar(16) not null default 'open',
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now(),
+  closed_at timestamptz
+);
+
+CREATE INDEX "support_ticket_chat_id_status" on "support_ticket" (chat_id, status);
+
+CREATE TABLE IF NOT EXISTS "support_message" (
+  id bigserial primary key,
+  ticket_id bigint not null references "support_ticket"(id),
+  sender varchar(16) not null,
+  admin_username varchar(64) not null default '',
+  text text not null,
+  forwarded_message_id bigint,
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX "support_message_ticket_id" on "support_message" (ticket_id);
+CREATE INDEX "support_message_forwarded_message_id" on "support_message" (forwarded_message_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "support_message";
+DROP TABLE IF EXISTS "support_ticket";
+-- +goose StatementEnd
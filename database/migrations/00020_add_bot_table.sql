@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "bot" (
+  id bigserial primary key,
+  admin_id bigint not null references "admin"(id),
+  token varchar(256) unique not null,
+  username varchar(64) not null,
+  webhook_secret varchar(64) not null,
+  is_active boolean not null default true,
+  created_at timestamptz not null default now()
+);
+
+-- bot_id is 0 for the main bot, bots are deactivated instead of being deleted.
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS bot_id bigint not null default 0;
+ALTER TABLE "plan" ADD COLUMN IF NOT EXISTS bot_id bigint not null default 0;
+ALTER TABLE "plan" ADD COLUMN IF NOT EXISTS price bigint not null default 0;
+ALTER TABLE "support_ticket" ADD COLUMN IF NOT EXISTS bot_id bigint not null default 0;
+
+CREATE INDEX "user_bot_id" on "user" (bot_id);
+CREATE INDEX "plan_bot_id" on "plan" (bot_id);
+
+-- private chats have the same id in every bot, so the state of the chats is kept per bot.
+ALTER TABLE "conversation" ADD COLUMN IF NOT EXISTS bot_id bigint not null default 0;
+ALTER TABLE "conversation" DROP CONSTRAINT IF EXISTS conversation_pkey;
+ALTER TABLE "conversation" ADD PRIMARY KEY (bot_id, chat_id);
+
+ALTER TABLE "scheduled_message_deletion" ADD COLUMN IF NOT EXISTS bot_id bigint not null default 0;
+ALTER TABLE "scheduled_message_deletion" DROP CONSTRAINT IF EXISTS scheduled_message_deletion_chat_id_message_id_key;
+ALTER TABLE "scheduled_message_deletion" ADD CONSTRAINT scheduled_message_deletion_bot_id_chat_id_message_id_key
+  UNIQUE (bot_id, chat_id, message_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "scheduled_message_deletion" DROP CONSTRAINT IF EXISTS scheduled_message_deletion_bot_id_chat_id_message_id_key;
+DELETE FROM "scheduled_message_deletion" WHERE bot_id <> 0;
+ALTER TABLE "scheduled_message_deletion" ADD CONSTRAINT scheduled_message_deletion_chat_id_message_id_key
+  UNIQUE (chat_id, message_id);
+ALTER TABLE "scheduled_message_deletion" DROP COLUMN IF EXISTS bot_id;
+
+ALTER TABLE "conversation" DROP CONSTRAINT IF EXISTS conversation_pkey;
+DELETE FROM "conversation" WHERE bot_id <> 0;
+ALTER TABLE "conversation" ADD PRIMARY KEY (chat_id);
+ALTER TABLE "conversation" DROP COLUMN IF EXISTS bot_id;
+
+ALTER TABLE "support_ticket" DROP COLUMN IF EXISTS bot_id;
+ALTER TABLE "plan" DROP COLUMN IF EXISTS price;
+ALTER TABLE "plan" DROP COLUMN IF EXISTS bot_id;
+ALTER TABLE "user" DROP COLUMN IF EXISTS bot_id;
+
+DROP TABLE IF EXISTS "bot";
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- resellers are admins that can only manage their own users, within the quota they've bought.
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS role varchar(16) not null default 'admin';
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS user_quota integer not null default 0;
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS used_users integer not null default 0;
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS traffic_quota bigint not null default 0;
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS used_traffic bigint not null default 0;
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS commission_rate integer not null default 0;
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS api_token_hash varchar(64) unique;
+
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS reseller_id bigint references "admin"(id);
+
+CREATE INDEX "user_reseller_id" on "user" (reseller_id);
+
+-- commissions are earned on the packages the resellers sell, payouts are recorded with negative amounts.
+CREATE TABLE IF NOT EXISTS "reseller_commission" (
+  id bigserial primary key,
+  reseller_id bigint not null references "admin"(id),
+  user_id bigint,
+  plan_id bigint references "plan"(id),
+  amount bigint not null,
+  note varchar(256) not null default '',
+  created_at timestamptz not null default now()
+);
+
+CREATE INDEX "reseller_commission_reseller_id" on "reseller_commission" (reseller_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "reseller_commission";
+
+ALTER TABLE "user" DROP COLUMN IF EXISTS reseller_id;
+
+ALTER TABLE "admin" DROP COLUMN IF EXISTS api_token_hash;
+ALTER TABLE "admin" DROP COLUMN IF EXISTS commission_rate;
+ALTER TABLE "admin" DROP COLUMN IF EXISTS used_traffic;
+ALTER TABLE "admin" DROP COLUMN IF EXISTS traffic_quota;
+ALTER TABLE "admin" DROP COLUMN IF EXISTS used_users;
+ALTER TABLE "admin" DROP COLUMN IF EXISTS user_quota;
+ALTER TABLE "admin" DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS trial_pending boolean not null default false;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS captcha_failures int not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "user" DROP COLUMN IF EXISTS captcha_failures;
+ALTER TABLE "user" DROP COLUMN IF EXISTS trial_pending;
+-- +goose StatementEnd
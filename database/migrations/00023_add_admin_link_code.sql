@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- admins link their telegram account by sending the bot a one time code they get from the panel.
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS link_code_hash varchar(64) unique;
+ALTER TABLE "admin" ADD COLUMN IF NOT EXISTS link_code_expire_at timestamptz;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "admin" DROP COLUMN IF EXISTS link_code_expire_at;
+ALTER TABLE "admin" DROP COLUMN IF EXISTS link_code_hash;
+-- +goose StatementEnd
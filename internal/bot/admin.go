@@ -0,0 +1,321 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"github.com/alir32a/jupiter/pkg/util"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	QueryResourceAdminBan        = "admin_ban"
+	QueryResourceAdminUnban      = "admin_unban"
+	QueryResourceAdminAddPackage = "admin_addpackage"
+	QueryResourceAdminKick       = "admin_kick"
+
+	adminActionParams = "params"
+)
+
+var (
+	AdminBotCommands = map[string]string{
+		"/admin":      "show admin commands",
+		"/users":      "/users <username>: show a user and their packages",
+		"/ban":        "/ban <username>: ban a user",
+		"/unban":      "/unban <username>: unban a user",
+		"/addpackage": "/addpackage <username> <traffic in GB> <days> <max connections>: add a package to a user",
+		"/kick":       "/kick <connection id>: disconnect a connection",
+		"/stats":      "show system stats",
+		"/tickets":    "show open support tickets",
+		"/reply":      "/reply <ticket id> <text>: answer a support ticket",
+		"/close":      "/close <ticket id>: close a support ticket",
+		"/linkadmin":  "/linkadmin <code>: link your telegram account to your admin, the code is given in the panel",
+	}
+
+	// adminActionParamsCount is the number of params each admin action is run with.
+	adminActionParamsCount = map[string]int{
+		QueryResourceAdminBan:        1,
+		QueryResourceAdminUnban:      1,
+		QueryResourceAdminAddPackage: 4,
+		QueryResourceAdminKick:       1,
+	}
+)
+
+// authorizeAdmin returns the name admin actions are recorded with, the telegram account should either be
+// in the configured admin ids or be linked to an active admin.
+func (b MainBot) authorizeAdmin(from tg.From) (string, error) {
+	if slices.Contains(b.cfg.AdminIDs, from.ID) {
+		return fmt.Sprintf("telegram:%d", from.ID), nil
+	}
+
+	admin, err := b.adminSvc.GetTelegramAdmin(context.Background(), strconv.Itoa(from.ID))
+	if err != nil {
+		return "", err
+	}
+
+	return admin.Username, nil
+}
+
+// LinkAdmin links the telegram account to the admin the code was created for, admins get their code from
+// the panel, so only the owner of both accounts can link them.
+func (b MainBot) LinkAdmin(msg tg.Message, code string) error {
+	if code == "" {
+		return b.reply(msg.From.ID, AdminBotCommands["/linkadmin"])
+	}
+
+	admin, err := b.adminSvc.LinkTelegram(context.Background(), strconv.Itoa(msg.From.ID), code)
+	if err != nil {
+		return b.reply(msg.From.ID, err.Error())
+	}
+
+	return b.reply(msg.From.ID, fmt.Sprintf("your telegram account is linked to %s, send /admin to see the "+
+		"admin commands", admin.Username))
+}
+
+func (b MainBot) handleAdminCommand(msg tg.Message, command, args string) error {
+	actor, err := b.authorizeAdmin(msg.From)
+	if err != nil {
+		if errors.Is(err, errorext.ErrNotAdmin) {
+			return b.SendUnknownMessage(msg.From)
+		}
+
+		return err
+	}
+
+	params := strings.Fields(args)
+
+	switch command {
+	case "/admin":
+		return b.AdminHelp(msg)
+	case "/users":
+		return b.AdminGetUser(msg, params)
+	case "/ban":
+		return b.confirmAdminAction(msg, params, QueryResourceAdminBan, "ban %s?")
+	case "/unban":
+		return b.confirmAdminAction(msg, params, QueryResourceAdminUnban, "unban %s?")
+	case "/addpackage":
+		return b.confirmAdminAction(msg, params, QueryResourceAdminAddPackage,
+			"add a package to %s with %s GB traffic, %s days and %s max connections?")
+	case "/kick":
+		return b.confirmAdminAction(msg, params, QueryResourceAdminKick, "disconnect connection %s?")
+	case "/stats":
+		return b.AdminGetStats(msg)
+	case "/tickets":
+		return b.AdminGetTickets(msg)
+	case "/reply":
+		return b.AdminReplyTicket(msg, actor, args)
+	case "/close":
+		return b.AdminCloseTicket(msg, params)
+	default:
+		return b.SendUnknownMessage(msg.From)
+	}
+}
+
+func (b MainBot) AdminHelp(msg tg.Message) error {
+	reply := "admin commands:\n"
+	for _, name := range []string{"/users", "/ban", "/unban", "/addpackage", "/kick", "/stats", "/tickets",
+		"/reply", "/close"} {
+		reply += fmt.Sprintf("- %s\n", AdminBotCommands[name])
+	}
+
+	return b.reply(msg.From.ID, reply)
+}
+
+func (b MainBot) AdminGetUser(msg tg.Message, params []string) error {
+	if len(params) != 1 {
+		return b.reply(msg.From.ID, AdminBotCommands["/users"])
+	}
+
+	ctx := context.Background()
+
+	user, err := b.userSvc.GetUserByUsername(ctx, params[0])
+	if err != nil {
+		return b.reply(msg.From.ID, err.Error())
+	}
+
+	reply := fmt.Sprintf("ID: %d\nUsername: %s\nType: %s\nWallet Balance: %d\nBanned: %t\nCreated At: %s\n",
+		user.ID, user.Username, user.UserType, user.WalletBalance, user.BannedAt != nil,
+		user.CreatedAt.Format(TimeFormat))
+
+	packages, err := b.packageSvc.GetUserActiveAndReservedPackages(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if packages.ActivePackage.ID != 0 {
+		activePack := packages.ActivePackage
+		reply += fmt.Sprintf("\nActive Package: #%d\nTraffic Limit: %s\nTotal Traffic Usage: %s\nMax Connections: %d\n"+
+			"Expire At: %s\n", activePack.ID, formatTrafficLimit(locale.DefaultLanguage, activePack),
+			util.ToHumanReadableBytes(activePack.DownloadTrafficUsage+activePack.UploadTrafficUsage),
+			activePack.MaxConnections, formatExpireAt(locale.DefaultLanguage, activePack))
+	}
+
+	reply += fmt.Sprintf("Reserved Packages: %d\n", len(packages.ReservedPackages))
+
+	conns, err := b.connectionSvc.GetUserActiveConnections(ctx, user.Username)
+	if err != nil {
+		return err
+	}
+
+	for _, conn := range conns {
+		reply += fmt.Sprintf("Connection #%d: %s (%s)\n", conn.ID, conn.RemoteIP, conn.Hostname)
+	}
+
+	return b.reply(msg.From.ID, reply)
+}
+
+func (b MainBot) AdminGetStats(msg tg.Message) error {
+	ctx := context.Background()
+
+	status, err := b.connectionSvc.GetSystemStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	usersStat, err := b.userSvc.GetUsersStat(ctx)
+	if err != nil {
+		return err
+	}
+
+	reply := fmt.Sprintf("Total Users: %d\nActive Users: %d\nBanned Users: %d\nOnline Users: %d\n"+
+		"Active Connections: %d\nTotal Download Usage: %s\nTotal Upload Usage: %s", usersStat.TotalUsers, usersStat.TotalActiveUsers, usersStat.TotalBannedUsers, status.OnlineUsers,
+		status.TotalActiveConnections, util.ToHumanReadableBytes(status.TotalDownloadUsage),
+		util.ToHumanReadableBytes(status.TotalUploadUsage))
+
+	return b.reply(msg.From.ID, reply)
+}
+
+// confirmAdminAction asks the admin to confirm the action with an inline keyboard, the params are stored
+// with the callback service, since they don't always fit in the callback data, and the action runs in
+// handleAdminQuery once confirmed.
+func (b MainBot) confirmAdminAction(msg tg.Message, params []string, resource, prompt string) error {
+	if len(params) != adminActionParamsCount[resource] {
+		return b.reply(msg.From.ID, AdminBotCommands["/"+strings.TrimPrefix(resource, "admin_")])
+	}
+
+	keys, err := b.callbackSvc.StorePayloads(context.Background(), msg.From.ID, []map[string]string{
+		{adminActionParams: strings.Join(params, " ")},
+	}, b.cfg.MenuTTL)
+	if err != nil {
+		return err
+	}
+
+	confirmQuery, err := NewQuery(QueryActionConfirm).SetResource(resource).SetParam(keys[0]).Marshal()
+	if err != nil {
+		return err
+	}
+
+	cancelQuery, err := NewQuery(QueryActionCancel).SetResource(resource).Marshal()
+	if err != nil {
+		return err
+	}
+
+	promptArgs := make([]any, 0, len(params))
+	for _, param := range params {
+		promptArgs = append(promptArgs, param)
+	}
+
+	_, err = b.bot.SendMessage(tg.SendMessageRequest{
+		ChatID: msg.From.ID,
+		Text:   fmt.Sprintf(prompt, promptArgs...),
+		ReplyMarkup: tg.NewInlineKeyboard(
+			tg.InlineKeyboardButton{Text: "Confirm", CallbackData: confirmQuery},
+			tg.InlineKeyboardButton{Text: "Cancel", CallbackData: cancelQuery},
+		),
+	})
+
+	return err
+}
+
+func (b MainBot) handleAdminQuery(callbackQuery tg.CallbackQuery, query Query) error {
+	actor, err := b.authorizeAdmin(callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	result := "canceled"
+	if query.Action == QueryActionConfirm {
+		result = "done"
+
+		payload, err := b.callbackSvc.GetPayload(context.Background(), callbackQuery.Message.Chat.ID, query.Param)
+		if err == nil {
+			err = b.runAdminAction(actor, query.Resource, strings.Fields(payload[adminActionParams]))
+		}
+
+		if err != nil {
+			result = err.Error()
+		}
+	}
+
+	if err := b.bot.AnswerCallbackQuery(callbackQuery.ID, result); err != nil {
+		b.logger.Error(err.Error())
+	}
+
+	return b.reply(callbackQuery.From.ID, result)
+}
+
+func (b MainBot) runAdminAction(actor, resource string, params []string) error {
+	ctx := context.Background()
+
+	count, ok := adminActionParamsCount[resource]
+	if !ok {
+		return fmt.Errorf("unknown admin action %s", resource)
+	}
+
+	if len(params) != count {
+		return fmt.Errorf("%s needs %d params, got %d", resource, count, len(params))
+	}
+
+	switch resource {
+	case QueryResourceAdminBan, QueryResourceAdminUnban:
+		user, err := b.userSvc.GetUserByUsername(ctx, params[0])
+		if err != nil {
+			return err
+		}
+
+		if resource == QueryResourceAdminBan {
+			return b.userSvc.BanUser(ctx, user.ID)
+		}
+
+		return b.userSvc.UnbanUser(ctx, user.ID)
+	case QueryResourceAdminAddPackage:
+		values := make([]int, 0, 3)
+		for _, param := range params[1:] {
+			v, err := strconv.Atoi(param)
+			if err != nil {
+				return fmt.Errorf("%s is not a number", param)
+			}
+
+			values = append(values, v)
+		}
+
+		return b.packageSvc.CreatePackage(ctx, model.CreatePackageRequest{
+			Username:         params[0],
+			Traffic:          values[0],
+			ExpirationInDays: values[1],
+			MaxConnections:   values[2],
+			Actor:            actor,
+		})
+	case QueryResourceAdminKick:
+		id, err := strconv.Atoi(params[0])
+		if err != nil {
+			return fmt.Errorf("%s is not a valid connection id", params[0])
+		}
+
+		return b.connectionSvc.DisconnectID(ctx, id)
+	default:
+		return fmt.Errorf("unknown admin action %s", resource)
+	}
+}
+
+func (b MainBot) reply(chatID int, text string) error {
+	_, err := b.bot.SendMessage(tg.SendMessageRequest{ChatID: chatID, Text: text})
+
+	return err
+}
@@ -2,10 +2,20 @@ package bot
 
 var (
 	MainBotCommands = map[string]string{
-		"/start":       "show help message",
-		"/status":      "show your active package status",
-		"/create":      "create a user and show credentials, and activate a trial package if trial is activated by administrators",
-		"/password":    "change your account password",
-		"/connections": "show active connections",
+		"/start":         "show help message",
+		"/status":        "show your active package status",
+		"/create":        "create a user and show credentials, and activate a trial package if trial is activated by administrators",
+		"/link":          "link this telegram account to a user created by the administrators",
+		"/password":      "change your account password",
+		"/setpassword":   "choose your own account password",
+		"/connect":       "show how to connect to the server",
+		"/connections":   "show active connections",
+		"/accounts":      "switch between your accounts or create another one",
+		"/plans":         "show the plans and their prices",
+		"/referrals":     "show your referral link and rewards",
+		"/notifications": "turn notifications on or off",
+		"/language":      "change the language of the bot",
+		"/support":       "ask the administrators for help",
+		"/cancel":        "cancel the current operation",
 	}
 )
@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"strconv"
+)
+
+// Connect sends the QR code of the server with the instructions as its caption, followed by the client profile.
+func (b MainBot) Connect(msg tg.Message) error {
+	lang := b.language(msg.From)
+
+	user, err := b.userSvc.GetActiveAccount(context.Background(), strconv.Itoa(msg.From.ID))
+	if err != nil {
+		return b.reply(msg.Chat.ID, locale.Error(lang, err))
+	}
+
+	profile, err := b.profileSvc.GetConnectionProfile(user.Username)
+	if err != nil {
+		return b.reply(msg.Chat.ID, locale.Error(lang, err))
+	}
+
+	caption, err := b.newMessage(msg.Chat.ID, lang, locale.MsgConnect, tg.TemplateData{
+		"Address":  profile.Address,
+		"Username": user.Username,
+		"Command":  profile.Command,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = b.bot.SendPhoto(tg.SendPhotoRequest{
+		ChatID:    msg.Chat.ID,
+		Photo:     tg.InputFile{Name: "qrcode.png", Data: profile.QRCode},
+		Caption:   caption.Text,
+		ParseMode: caption.ParseMode,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = b.bot.SendDocument(tg.SendDocumentRequest{
+		ChatID:   msg.Chat.ID,
+		Document: tg.InputFile{Name: fmt.Sprintf("%s.xml", profile.Name), Data: profile.ProfileXML},
+		Caption:  locale.T(lang, locale.LabelProfileCaption),
+	})
+
+	return err
+}
@@ -0,0 +1,271 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"strings"
+	"time"
+)
+
+const (
+	QueryResourceConversation = "conversation"
+
+	// EndConversation is returned from a step handler to end the conversation.
+	EndConversation = ""
+
+	CancelCommand = "/cancel"
+)
+
+type ConversationService interface {
+	SaveConversation(ctx context.Context, req model.SaveConversationRequest) error
+	GetConversation(ctx context.Context, botID, chatID int) (*model.ConversationEntity, error)
+	EndConversation(ctx context.Context, botID, chatID int) error
+}
+
+// ConversationState is passed to the steps of a conversation, Data is persisted between the steps.
+type ConversationState struct {
+	ChatID int
+	From   tg.From
+	Data   map[string]string
+	// MessageID is the id of the message the step is handling, it's not set for prompts.
+	MessageID int
+}
+
+type ConversationStep struct {
+	// Prompt returns the message that asks the user for the input of the step.
+	Prompt func(state ConversationState) (string, error)
+	// Handle processes the input of the user and returns the name of the next step.
+	Handle func(state ConversationState, input string) (string, error)
+}
+
+type Conversation struct {
+	Name      string
+	FirstStep string
+	Steps     map[string]ConversationStep
+	// Timeout is how long the conversation waits for the user at each step, the engine's default is
+	// used if it's not set.
+	Timeout time.Duration
+}
+
+// InputError is returned from a step handler when the input is not valid, the user gets the error
+// and is asked for the input again.
+type InputError struct {
+	Message string
+}
+
+func (e InputError) Error() string {
+	return e.Message
+}
+
+func NewInputError(msg string) error {
+	return InputError{Message: msg}
+}
+
+// ConversationEngine runs multi-step conversations, the state of each chat is persisted so
+// conversations survive restarts.
+type ConversationEngine struct {
+	svc ConversationService
+	bot *tg.Bot
+	// botID is the id the state of the chats is kept under, the same chat can be in a conversation with
+	// each of the bots.
+	botID          int
+	defaultTimeout time.Duration
+	conversations  map[string]Conversation
+	// Language returns the language of the chat the engine's own messages are sent in.
+	Language func(chatID int) string
+}
+
+func NewConversationEngine(svc ConversationService, bot *tg.Bot, botID int, defaultTimeout time.Duration) *ConversationEngine {
+	return &ConversationEngine{
+		svc:            svc,
+		bot:            bot,
+		botID:          botID,
+		defaultTimeout: defaultTimeout,
+		conversations:  make(map[string]Conversation),
+	}
+}
+
+func (c *ConversationEngine) Register(conversation Conversation) {
+	c.conversations[conversation.Name] = conversation
+}
+
+// Start starts the conversation in the chat, it replaces any other conversation the chat is in.
+func (c *ConversationEngine) Start(name string, chatID int, from tg.From, data map[string]string) error {
+	conversation, ok := c.conversations[name]
+	if !ok {
+		return fmt.Errorf("conversation %s is not registered", name)
+	}
+
+	if data == nil {
+		data = make(map[string]string)
+	}
+
+	return c.moveTo(conversation, conversation.FirstStep, ConversationState{
+		ChatID: chatID,
+		From:   from,
+		Data:   data,
+	})
+}
+
+// Handle passes the message to the conversation the chat is in, and reports whether the message has been
+// handled. Commands other than /cancel are never handled and end the conversation.
+func (c *ConversationEngine) Handle(msg tg.Message) (bool, error) {
+	ctx := context.Background()
+
+	if msg.Type == tg.MessageTypeCommand {
+		if strings.HasPrefix(msg.Text, CancelCommand) {
+			return false, nil
+		}
+
+		return false, c.svc.EndConversation(ctx, c.botID, msg.Chat.ID)
+	}
+
+	state, err := c.svc.GetConversation(ctx, c.botID, msg.Chat.ID)
+	if err != nil || state == nil {
+		return false, err
+	}
+
+	conversation, ok := c.conversations[state.Name]
+	if !ok {
+		return false, c.svc.EndConversation(ctx, c.botID, msg.Chat.ID)
+	}
+
+	step, ok := conversation.Steps[state.Step]
+	if !ok {
+		return false, c.svc.EndConversation(ctx, c.botID, msg.Chat.ID)
+	}
+
+	if state.IsExpired() {
+		if err := c.svc.EndConversation(ctx, c.botID, msg.Chat.ID); err != nil {
+			return true, err
+		}
+
+		return true, c.send(msg.Chat.ID, c.text(msg.Chat.ID, locale.LabelConversationTimedOut), nil)
+	}
+
+	convState := ConversationState{
+		ChatID:    msg.Chat.ID,
+		From:      msg.From,
+		Data:      state.Data,
+		MessageID: msg.MessageID,
+	}
+
+	next, err := step.Handle(convState, strings.TrimSpace(msg.Text))
+	if err != nil {
+		var inputErr InputError
+		if errors.As(err, &inputErr) {
+			if err := c.send(msg.Chat.ID, inputErr.Message, nil); err != nil {
+				return true, err
+			}
+
+			return true, c.prompt(step, convState)
+		}
+
+		return true, errors.Join(err, c.svc.EndConversation(ctx, c.botID, msg.Chat.ID))
+	}
+
+	return true, c.moveTo(conversation, next, convState)
+}
+
+// Cancel ends the conversation the chat is in.
+func (c *ConversationEngine) Cancel(chatID int) error {
+	ctx := context.Background()
+
+	state, err := c.svc.GetConversation(ctx, c.botID, chatID)
+	if err != nil {
+		return err
+	}
+
+	if state == nil {
+		return c.send(chatID, c.text(chatID, locale.LabelNothingToCancel), nil)
+	}
+
+	if err := c.svc.EndConversation(ctx, c.botID, chatID); err != nil {
+		return err
+	}
+
+	return c.send(chatID, c.text(chatID, locale.LabelCanceled), nil)
+}
+
+// HandleQuery handles the cancel button of the conversation prompts.
+func (c *ConversationEngine) HandleQuery(callbackQuery tg.CallbackQuery, query Query) error {
+	if query.Action != QueryActionCancel {
+		return fmt.Errorf("unknown conversation action %s", query.Action)
+	}
+
+	if err := c.bot.AnswerCallbackQuery(callbackQuery.ID, ""); err != nil {
+		return err
+	}
+
+	return c.Cancel(callbackQuery.Message.Chat.ID)
+}
+
+func (c *ConversationEngine) moveTo(conversation Conversation, stepName string, state ConversationState) error {
+	ctx := context.Background()
+
+	if stepName == EndConversation {
+		return c.svc.EndConversation(ctx, c.botID, state.ChatID)
+	}
+
+	step, ok := conversation.Steps[stepName]
+	if !ok {
+		return errors.Join(
+			fmt.Errorf("conversation %s has no step %s", conversation.Name, stepName),
+			c.svc.EndConversation(ctx, c.botID, state.ChatID))
+	}
+
+	timeout := conversation.Timeout
+	if timeout <= 0 {
+		timeout = c.defaultTimeout
+	}
+
+	err := c.svc.SaveConversation(ctx, model.SaveConversationRequest{
+		BotID:    c.botID,
+		ChatID:   state.ChatID,
+		Name:     conversation.Name,
+		Step:     stepName,
+		Data:     state.Data,
+		ExpireAt: time.Now().Add(timeout),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.prompt(step, state)
+}
+
+func (c *ConversationEngine) prompt(step ConversationStep, state ConversationState) error {
+	text, err := step.Prompt(state)
+	if err != nil {
+		return err
+	}
+
+	cancelQuery, err := NewQuery(QueryActionCancel).SetResource(QueryResourceConversation).Marshal()
+	if err != nil {
+		return err
+	}
+
+	return c.send(state.ChatID, text, tg.NewInlineKeyboard(tg.InlineKeyboardButton{
+		Text:         c.text(state.ChatID, locale.LabelCancel),
+		CallbackData: cancelQuery,
+	}))
+}
+
+func (c *ConversationEngine) text(chatID int, key string) string {
+	lang := locale.DefaultLanguage
+	if c.Language != nil {
+		lang = c.Language(chatID)
+	}
+
+	return locale.T(lang, key)
+}
+
+func (c *ConversationEngine) send(chatID int, text string, keyboard *tg.InlineKeyboard) error {
+	_, err := c.bot.SendMessage(tg.SendMessageRequest{ChatID: chatID, Text: text, ReplyMarkup: keyboard})
+
+	return err
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/pkg/password"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"net/http"
+	"strconv"
+)
+
+const (
+	QueryActionReveal = "reveal"
+
+	ConversationSetPassword = "set_password"
+	stepPassword            = "password"
+)
+
+// sendCredentials sends the username in a protected message with a button that reveals the password once,
+// the message is deleted when the reveal expires.
+func (b MainBot) sendCredentials(chatID int, lang, key, username, pass string) error {
+	ctx := context.Background()
+
+	token, err := b.credentialSvc.CreateReveal(ctx, chatID, username, pass, b.cfg.CredentialMessageTTL)
+	if err != nil {
+		return err
+	}
+
+	req, err := b.newMessage(chatID, lang, key, tg.TemplateData{
+		"Username":  username,
+		"ExpiresIn": locale.T(lang, locale.LabelMinutes, int(b.cfg.CredentialMessageTTL.Minutes())),
+	})
+	if err != nil {
+		return err
+	}
+
+	query, err := NewQuery(QueryActionReveal).SetResource(QueryResourceCredential).SetParam(token).Marshal()
+	if err != nil {
+		return err
+	}
+
+	req.ProtectContent = true
+	req.ReplyMarkup = tg.NewInlineKeyboard(tg.InlineKeyboardButton{
+		Text:         locale.T(lang, locale.LabelRevealPassword),
+		CallbackData: query,
+	})
+
+	sent, err := b.bot.SendMessage(req)
+	if err != nil {
+		return err
+	}
+
+	return b.credentialSvc.ScheduleDeletion(ctx, b.cfg.ID, chatID, sent.MessageID, b.cfg.CredentialMessageTTL)
+}
+
+// handleCredentialQuery shows the password in an alert, alerts aren't kept in the chat and the password
+// can't be revealed again.
+func (b MainBot) handleCredentialQuery(callbackQuery tg.CallbackQuery, query Query) error {
+	if query.Action != QueryActionReveal {
+		return fmt.Errorf("unknown credential action %s", query.Action)
+	}
+
+	lang := b.language(callbackQuery.From)
+
+	creds, err := b.credentialSvc.RevealPassword(context.Background(), callbackQuery.Message.Chat.ID, query.Param)
+	if err != nil {
+		return b.bot.ShowAlert(callbackQuery.ID, locale.Error(lang, err))
+	}
+
+	return b.bot.ShowAlert(callbackQuery.ID, locale.T(lang, locale.LabelRevealedPassword, creds.Username, creds.Password))
+}
+
+func (b MainBot) SetPassword(msg tg.Message) error {
+	_, err := b.userSvc.GetActiveAccount(context.Background(), strconv.Itoa(msg.From.ID))
+	if err != nil {
+		return b.reply(msg.Chat.ID, locale.Error(b.language(msg.From), err))
+	}
+
+	return b.conversations.Start(ConversationSetPassword, msg.Chat.ID, msg.From, nil)
+}
+
+// setPasswordConversation asks the user for a password, the messages with the password are deleted as soon
+// as they're received whether the password is accepted or not.
+func (b MainBot) setPasswordConversation() Conversation {
+	return Conversation{
+		Name:      ConversationSetPassword,
+		FirstStep: stepPassword,
+		Steps: map[string]ConversationStep{
+			stepPassword: {
+				Prompt: func(state ConversationState) (string, error) {
+					return locale.T(b.language(state.From), locale.LabelEnterPassword, password.MinLength), nil
+				},
+				Handle: func(state ConversationState, input string) (string, error) {
+					if err := b.bot.DeleteMessage(state.ChatID, state.MessageID); err != nil {
+						b.logger.Warn(err.Error(), "chat_id", state.ChatID, "message_id", state.MessageID)
+					}
+
+					lang := b.language(state.From)
+
+					err := b.userSvc.SetPassword(context.Background(), strconv.Itoa(state.From.ID), input)
+					if err != nil {
+						var extErr *errorext.Error
+						if errors.As(err, &extErr) && extErr.Status() == http.StatusBadRequest {
+							return "", NewInputError(locale.Error(lang, err))
+						}
+
+						return "", err
+					}
+
+					return EndConversation, b.replyMessage(state.ChatID, lang, locale.MsgPasswordChanged, nil)
+				},
+			},
+		},
+	}
+}
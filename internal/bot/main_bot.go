@@ -2,55 +2,163 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
 	"github.com/alir32a/jupiter/internal/model"
 	"github.com/alir32a/jupiter/pkg/tg"
 	"github.com/alir32a/jupiter/pkg/util"
 	"github.com/charmbracelet/log"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const (
 	TimeFormat = "2006-01-02T15:04:05 -07:00"
+
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+
+	QueryResourceLanguage   = "language"
+	QueryResourceCredential = "credential"
 )
 
 type UserService interface {
 	CreateUser(ctx context.Context, req model.CreateUserRequest) (model.CreateUserResponse, error)
 	ChangePassword(ctx context.Context, username string) (string, error)
+	GetUserByUsername(ctx context.Context, username string) (model.UserEntity, error)
+	BanUser(ctx context.Context, userID int) error
+	UnbanUser(ctx context.Context, userID int) error
+	GetUsersStat(ctx context.Context) (model.GetUsersStatResponse, error)
+	SetNotificationsEnabled(ctx context.Context, externalID string, enabled bool) error
+	SetLanguage(ctx context.Context, externalID, language string) error
+	SetPassword(ctx context.Context, externalID, password string) error
+	GetUserByExternalID(ctx context.Context, externalID string) (model.UserEntity, error)
+	LinkTelegramAccount(ctx context.Context, botID int, externalID, code string) (model.UserEntity, error)
+	GetActiveAccount(ctx context.Context, externalID string) (model.UserEntity, error)
+	GetAccounts(ctx context.Context, externalID string) (model.UserAccounts, error)
+	GetAccount(ctx context.Context, externalID string, accountID int) (model.UserEntity, error)
+	SwitchAccount(ctx context.Context, externalID string, accountID int) (model.UserEntity, error)
+	CreateAccount(ctx context.Context, externalID string) (model.CreateUserResponse, error)
+	GrantTrial(ctx context.Context, id int) error
+	AddCaptchaFailure(ctx context.Context, id int) (model.UserEntity, error)
 }
 
 type ConnectionService interface {
 	GetActiveConnections(ctx context.Context, req model.GetActiveConnectionsRequest) (model.GetActiveConnectionsResponse, error)
 	GetUserActiveConnections(ctx context.Context, username string) ([]model.ConnectionEntity, error)
+	GetSystemStatus(ctx context.Context) (model.GetSystemStatusResponse, error)
+	DisconnectID(ctx context.Context, id int) error
+	DisconnectUserConnection(ctx context.Context, username string, id int) error
+	DisconnectAllUserConnections(ctx context.Context, username string) error
 }
 
 type PackageService interface {
 	GetUserActivePackages(ctx context.Context, username string) (model.GetUserPackages, error)
+	GetUserActiveAndReservedPackages(ctx context.Context, userID int) (model.GetUserPackages, error)
+	CreatePackage(ctx context.Context, req model.CreatePackageRequest) error
+}
+
+type AdminService interface {
+	GetTelegramAdmin(ctx context.Context, telegramID string) (model.AdminEntity, error)
+	LinkTelegram(ctx context.Context, telegramID, code string) (model.AdminEntity, error)
+}
+
+type MessageTemplateService interface {
+	Render(ctx context.Context, lang, key string, data tg.TemplateData) (model.RenderedMessage, error)
+}
+
+type CredentialService interface {
+	ScheduleDeletion(ctx context.Context, botID, chatID, messageID int, after time.Duration) error
+	CreateReveal(ctx context.Context, chatID int, username, password string, ttl time.Duration) (string, error)
+	RevealPassword(ctx context.Context, chatID int, token string) (model.RevealedCredentials, error)
+}
+
+type ProfileService interface {
+	GetConnectionProfile(username string) (model.ConnectionProfile, error)
+}
+
+type ReferralService interface {
+	SaveInvite(ctx context.Context, externalID, referralCode string) error
+	GetInviteReferral(ctx context.Context, externalID string) (*string, error)
+	GetReferralStats(ctx context.Context, username string) (model.ReferralStats, error)
 }
 
 type MainBot struct {
 	userSvc        UserService
 	connectionSvc  ConnectionService
 	packageSvc     PackageService
+	referralSvc    ReferralService
+	adminSvc       AdminService
+	templateSvc    MessageTemplateService
+	credentialSvc  CredentialService
+	profileSvc     ProfileService
+	callbackSvc    CallbackService
+	supportSvc     SupportService
+	planSvc        PlanService
+	members        ChatMemberGetter
+	conversations  *ConversationEngine
 	bot            *tg.Bot
 	cfg            *config.MainBotConfig
+	trialCfg       *config.TrialPackageConfig
 	logger         *log.Logger
 	queryCommander *QueryCommander
+	dispatcher     *tg.Dispatcher
 }
 
-func NewMainBot(cfg *config.MainBotConfig, logger *log.Logger, userSvc UserService, connectionSvc ConnectionService,
-	packageSvc PackageService) *MainBot {
-	return &MainBot{
+func NewMainBot(cfg *config.MainBotConfig, logger *log.Logger, bot *tg.Bot, userSvc UserService,
+	connectionSvc ConnectionService, packageSvc PackageService, referralSvc ReferralService, adminSvc AdminService,
+	conversationSvc ConversationService, templateSvc MessageTemplateService, credentialSvc CredentialService,
+	profileSvc ProfileService, callbackSvc CallbackService, supportSvc SupportService, planSvc PlanService,
+	trialCfg *config.TrialPackageConfig, members ChatMemberGetter) *MainBot {
+	mainBot := &MainBot{
 		userSvc:        userSvc,
 		connectionSvc:  connectionSvc,
 		packageSvc:     packageSvc,
-		bot:            tg.NewBot(cfg.Token),
+		referralSvc:    referralSvc,
+		adminSvc:       adminSvc,
+		templateSvc:    templateSvc,
+		credentialSvc:  credentialSvc,
+		profileSvc:     profileSvc,
+		callbackSvc:    callbackSvc,
+		supportSvc:     supportSvc,
+		planSvc:        planSvc,
+		members:        members,
+		conversations:  NewConversationEngine(conversationSvc, bot, cfg.ID, cfg.ConversationTimeout),
+		bot:            bot,
 		cfg:            cfg,
+		trialCfg:       trialCfg,
 		logger:         logger,
 		queryCommander: NewQueryCommander(),
 	}
+
+	mainBot.conversations.Language = mainBot.chatLanguage
+	mainBot.conversations.Register(mainBot.setPasswordConversation())
+	mainBot.conversations.Register(mainBot.supportConversation())
+	mainBot.conversations.Register(mainBot.captchaConversation())
+
+	mainBot.queryCommander.Register(QueryResourceConversation, mainBot.conversations.HandleQuery)
+	mainBot.queryCommander.Register(QueryResourceLanguage, mainBot.handleLanguageQuery)
+	mainBot.queryCommander.Register(QueryResourceCredential, mainBot.handleCredentialQuery)
+	mainBot.queryCommander.Register(QueryResourceMenu, mainBot.handleMenuQuery)
+
+	for _, resource := range []string{QueryResourceAdminBan, QueryResourceAdminUnban, QueryResourceAdminAddPackage,
+		QueryResourceAdminKick} {
+		mainBot.queryCommander.Register(resource, mainBot.handleAdminQuery)
+	}
+
+	mainBot.dispatcher = tg.NewDispatcher(cfg.Workers, mainBot.handleUpdate,
+		tg.LoggingMiddleware(logger),
+		tg.AuthMiddleware(isAuthorized),
+		tg.RateLimitMiddleware(cfg.RateLimitPerMinute, time.Minute),
+		mainBot.botAuthMiddleware,
+	)
+	mainBot.dispatcher.ErrorHandler = mainBot.handleUpdateError
+
+	return mainBot
 }
 
 func (m MainBot) Run() error {
@@ -58,29 +166,98 @@ func (m MainBot) Run() error {
 		return err
 	}
 
+	m.dispatcher.Start()
+
+	if m.cfg.Mode == ModeWebhook {
+		if m.cfg.WebhookSecret == "" {
+			return errors.New("webhook secret is required in webhook mode")
+		}
+
+		err := m.bot.SetWebhook(m.cfg.WebhookUrl, m.cfg.WebhookSecret)
+		if err == nil {
+			m.logger.Info("main bot is receiving updates from webhook ...")
+
+			return nil
+		}
+
+		m.logger.Error("couldn't set the webhook, falling back to polling", "err", err)
+	}
+
+	if err := m.bot.DeleteWebhook(); err != nil {
+		return err
+	}
+
 	m.logger.Info("starting main bot ...")
 
-	m.bot.Run(func(updates []tg.Update) error {
-		for _, update := range updates {
-			if update.CallbackQuery != nil {
-				if err := m.queryCommander.Handle(*update.CallbackQuery); err != nil {
-					m.logger.Error(err.Error())
-				}
+	m.bot.Run(m.HandleUpdates)
 
-				continue
-			}
+	return nil
+}
+
+// HandleUpdates passes the updates received either from polling or the webhook to the dispatcher.
+func (m MainBot) HandleUpdates(updates []tg.Update) error {
+	m.dispatcher.Dispatch(updates...)
 
-			if err := m.parseCommand(update.Message); err != nil {
-				m.logger.Error(err.Error())
+	return nil
+}
+
+// Stop stops polling and waits for the received updates to be handled, updates received afterwards are dropped.
+func (m MainBot) Stop() {
+	m.bot.Stop()
+	m.dispatcher.Stop()
+}
+
+func (m MainBot) handleUpdate(update tg.Update) error {
+	if update.CallbackQuery != nil {
+		return m.queryCommander.Handle(*update.CallbackQuery)
+	}
+
+	return m.parseCommand(update.Message)
+}
+
+// botAuthMiddleware rejects the updates of the users that belong to another bot, users can only use the bot
+// they were created in and telegram accounts without a user can use any bot.
+func (m MainBot) botAuthMiddleware(next tg.HandlerFunc) tg.HandlerFunc {
+	return func(update tg.Update) error {
+		if m.isSupportChat(update.Message) {
+			return next(update)
+		}
 
+		user, err := m.userSvc.GetUserByExternalID(context.Background(), strconv.Itoa(update.Sender().ID))
+		if err != nil {
+			if !errorext.IsNotFound(err) {
 				return err
 			}
+
+			return next(update)
 		}
 
-		return nil
-	})
+		if user.BotID != m.cfg.ID {
+			return m.rejectUpdate(update)
+		}
 
-	return nil
+		return next(update)
+	}
+}
+
+func (m MainBot) rejectUpdate(update tg.Update) error {
+	text := locale.Error(m.language(update.Sender()), errorext.ErrUserOfAnotherBot)
+
+	if update.CallbackQuery != nil {
+		return m.bot.ShowAlert(update.CallbackQuery.ID, text)
+	}
+
+	return m.reply(update.Sender().ID, text)
+}
+
+func (m MainBot) handleUpdateError(update tg.Update, err error) {
+	if errors.Is(err, tg.ErrUnauthorized) || errors.Is(err, tg.ErrRateLimited) {
+		m.logger.Warn(err.Error(), "update_id", update.UpdateID, "chat_id", update.ChatID())
+
+		return
+	}
+
+	m.logger.Error(err.Error(), "update_id", update.UpdateID, "chat_id", update.ChatID())
 }
 
 func (m MainBot) CheckCommands() error {
@@ -112,213 +289,330 @@ func (m MainBot) CheckCommands() error {
 }
 
 func (b MainBot) parseCommand(msg tg.Message) error {
+	if handled, err := b.conversations.Handle(msg); handled || err != nil {
+		return err
+	}
+
 	if msg.Type != tg.MessageTypeCommand {
-		return b.SendUnknownMessage(msg.From.ID)
+		if b.isSupportChat(msg) {
+			return b.handleSupportChatMessage(msg)
+		}
+
+		return b.SendUnknownMessage(msg.From)
 	}
 
-	switch msg.Text {
+	command, args, _ := strings.Cut(msg.Text, " ")
+	// commands sent in groups are addressed to the bot by its username.
+	command = strings.TrimSuffix(command, "@"+b.cfg.Username)
+
+	switch command {
 	case "/start":
-		return b.Start(msg)
+		return b.Start(msg, strings.TrimSpace(args))
 	case "/create":
 		return b.CreateUser(msg)
+	case "/link":
+		return b.LinkAccount(msg, strings.TrimSpace(args))
 	case "/status":
 		return b.GetStatus(msg)
 	case "/password":
 		return b.ChangePassword(msg)
+	case "/setpassword":
+		return b.SetPassword(msg)
+	case "/connect":
+		return b.Connect(msg)
 	case "/connections":
 		return b.GetActiveConnections(msg)
+	case "/accounts":
+		return b.GetAccounts(msg)
+	case "/plans":
+		return b.GetPlans(msg)
+	case "/referrals":
+		return b.GetReferrals(msg)
+	case "/notifications":
+		return b.SetNotifications(msg, strings.TrimSpace(args))
+	case "/language":
+		return b.SetLanguage(msg, strings.TrimSpace(args))
+	case "/support":
+		return b.Support(msg, strings.TrimSpace(args))
+	case CancelCommand:
+		return b.conversations.Cancel(msg.Chat.ID)
+	case "/linkadmin":
+		if b.cfg.ID != model.MainBotID {
+			return b.SendUnknownMessage(msg.From)
+		}
+
+		return b.LinkAdmin(msg, strings.TrimSpace(args))
+	case "/admin", "/users", "/ban", "/unban", "/addpackage", "/kick", "/stats", "/tickets",
+		"/reply", "/close":
+		// admin commands act on every user, so the bots of the resellers don't have them.
+		if b.cfg.ID != model.MainBotID {
+			return b.SendUnknownMessage(msg.From)
+		}
+
+		return b.handleAdminCommand(msg, command, strings.TrimSpace(args))
 	default:
-		return b.SendUnknownMessage(msg.From.ID)
+		return b.SendUnknownMessage(msg.From)
 	}
 }
 
-func (b MainBot) Start(msg tg.Message) error {
-	reply := `
-	hey there 👋
-	this is jupiter bot, you can manage your openconnect vpn account here.
-    currently, you can't buy packages from this bot (yet).
-	you can create user (you can create only one user per telegram account),
-    see your active package (remaining traffic, expire time etc.),
-	change your account password and see active connections
-    let's f**ck sansoorchi.✊
-
-	supported commands:
-    - /start: show this message
-    - /status: show your active package status
-    - /create: create a user and show credentials, and activate a trial package if trial is activated by administrators
-    - /password: change your account password
-    - /connections: show active connections
-`
+func (b MainBot) Start(msg tg.Message, referralCode string) error {
+	lang := b.language(msg.From)
 
-	_, err := b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: reply})
-	if err != nil {
-		return err
+	if referralCode != "" {
+		if err := b.referralSvc.SaveInvite(context.Background(), strconv.Itoa(msg.From.ID), referralCode); err != nil {
+			if err := b.reply(msg.From.ID, locale.Error(lang, err)); err != nil {
+				return err
+			}
+		}
 	}
 
-	return nil
+	return b.replyMessage(msg.From.ID, lang, locale.MsgHelp, nil)
 }
 
-func (b MainBot) CreateUser(msg tg.Message) error {
-	username := msg.From.Username
+// createUser creates the user of the telegram account and sends the credentials, it returns false if the user
+// couldn't be created, the reason is already sent to the user.
+func (b MainBot) createUser(from tg.From, deferTrial bool) (model.UserEntity, bool, error) {
+	username := from.Username
 	if username == "" {
-		username = strconv.Itoa(msg.From.ID)
+		username = strconv.Itoa(from.ID)
+	}
+
+	lang := b.language(from)
+
+	referral, err := b.referralSvc.GetInviteReferral(context.Background(), strconv.Itoa(from.ID))
+	if err != nil {
+		return model.UserEntity{}, false, err
 	}
 
 	user, err := b.userSvc.CreateUser(context.Background(), model.CreateUserRequest{
 		Username:   username,
-		ExternalID: strconv.Itoa(msg.From.ID),
+		ExternalID: strconv.Itoa(from.ID),
 		UserType:   model.UserTypeTelegram,
+		Referral:   referral,
+		Language:   lang,
+		BotID:      b.cfg.ID,
+		DeferTrial: deferTrial,
 	})
 	if err != nil {
-		return err
+		return model.UserEntity{}, false, b.reply(from.ID, locale.Error(lang, err))
 	}
 
-	reply := fmt.Sprintf(
-		`here is your username and password, this message will be deleted in an hour, 
-         you can change your password anytime using /passaword
-         Userame: %s
-         Password: %s`, user.Username, user.Password)
-	resp, err := b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: reply, ProtectContent: true})
+	return user.UserEntity, true, b.sendCredentials(from.ID, lang, locale.MsgCredentials, user.Username, user.Password)
+}
+
+func (b MainBot) GetStatus(msg tg.Message) error {
+	return b.openMenu(msg, Screen{Name: ScreenStatus})
+}
+
+func (b MainBot) GetActiveConnections(msg tg.Message) error {
+	return b.openMenu(msg, Screen{Name: ScreenConnections})
+}
+
+func (b MainBot) GetAccounts(msg tg.Message) error {
+	return b.openMenu(msg, Screen{Name: ScreenAccounts})
+}
+
+func (b MainBot) ChangePassword(msg tg.Message) error {
+	lang := b.language(msg.From)
+
+	user, err := b.userSvc.GetActiveAccount(context.Background(), strconv.Itoa(msg.From.ID))
 	if err != nil {
-		return err
+		return b.reply(msg.From.ID, locale.Error(lang, err))
 	}
 
-	if len(resp) > 0 {
-		time.AfterFunc(1*time.Hour, func() {
-			if err := b.bot.DeleteMessage(msg.From.ID, resp[0].MessageID); err != nil {
-				b.logger.Error(err.Error())
-			}
-		})
+	newPassword, err := b.userSvc.ChangePassword(context.Background(), user.Username)
+	if err != nil {
+		return b.reply(msg.From.ID, locale.Error(lang, err))
 	}
 
-	return nil
+	return b.sendCredentials(msg.From.ID, lang, locale.MsgNewPassword, user.Username, newPassword)
 }
 
-func (b MainBot) GetStatus(msg tg.Message) error {
-	packages, err := b.packageSvc.GetUserActivePackages(context.Background(), msg.From.Username)
+// LinkAccount links the telegram account to a user created from the panel, using the code the administrators
+// have given to the user.
+func (b MainBot) LinkAccount(msg tg.Message, code string) error {
+	lang := b.language(msg.From)
+
+	if code == "" {
+		return b.reply(msg.From.ID, locale.T(lang, locale.LabelLinkUsage))
+	}
+
+	user, err := b.userSvc.LinkTelegramAccount(context.Background(), b.cfg.ID, strconv.Itoa(msg.From.ID), code)
 	if err != nil {
-		_, err := b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: err.Error()})
-		if err != nil {
-			return err
-		}
+		return b.reply(msg.From.ID, locale.Error(lang, err))
+	}
+
+	return b.replyMessage(msg.From.ID, lang, locale.MsgAccountLinked, tg.TemplateData{"Username": user.Username})
+}
 
-		return nil
+func (b MainBot) GetReferrals(msg tg.Message) error {
+	lang := b.language(msg.From)
+
+	user, err := b.userSvc.GetUserByExternalID(context.Background(), strconv.Itoa(msg.From.ID))
+	if err != nil {
+		return b.reply(msg.From.ID, locale.Error(lang, err))
 	}
 
-	if packages.ActivePackage.ID != 0 && len(packages.ReservedPackages) <= 0 {
-		_, err := b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: "oops, you don't have any active package"})
-		if err != nil {
-			return err
-		}
+	stats, err := b.referralSvc.GetReferralStats(context.Background(), user.Username)
+	if err != nil {
+		return b.reply(msg.From.ID, locale.Error(lang, err))
 	}
 
-	activePack := packages.ActivePackage
-	reply := fmt.Sprintf(`
-	Active Package:
-	Traffic Limit: %s
-	Download Traffic Usage: %s
-	Upload Traffic Usage: %s
-	Total Traffic Usage: %s
-	Max Connections: %d
-	Expire At: %s
-	`, util.ToHumanReadableBytes(activePack.TrafficLimit), util.ToHumanReadableBytes(activePack.DownloadTrafficUsage),
-		util.ToHumanReadableBytes(activePack.UploadTrafficUsage),
-		util.ToHumanReadableBytes(activePack.DownloadTrafficUsage+activePack.UploadTrafficUsage), activePack.MaxConnections,
-		activePack.ExpireAt.Format(TimeFormat))
-
-	if len(packages.ReservedPackages) > 0 {
-		reply += "Reserved Packages\n"
-
-		for i, pack := range packages.ReservedPackages {
-			reply += fmt.Sprintf(`
-		# %d
-		Traffic Limit: %s
-		Max Connections: %d
-		Expiration: %d Days
-		`, i+1, util.ToHumanReadableBytes(pack.TrafficLimit), pack.MaxConnections, pack.ExpirationInDays)
-		}
+	return b.replyMessage(msg.From.ID, lang, locale.MsgReferrals, tg.TemplateData{
+		"ReferralCode":  stats.ReferralCode,
+		"ReferralLink":  fmt.Sprintf("https://t.me/%s?start=%s", b.cfg.Username, stats.ReferralCode),
+		"InvitedUsers":  stats.InvitedUsers,
+		"RewardedUsers": stats.RewardedUsers,
+		"EarnedTraffic": util.ToHumanReadableBytes(stats.TotalTraffic),
+		"EarnedDays":    stats.TotalDays,
+		"EarnedCredit":  stats.TotalCredit,
+		"WalletBalance": stats.WalletBalance,
+	})
+}
+
+func (b MainBot) SetNotifications(msg tg.Message, arg string) error {
+	lang := b.language(msg.From)
+
+	if arg != "on" && arg != "off" {
+		return b.replyMessage(msg.From.ID, lang, locale.MsgNotificationsUsage, nil)
 	}
 
-	_, err = b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: reply})
+	err := b.userSvc.SetNotificationsEnabled(context.Background(), strconv.Itoa(msg.From.ID), arg == "on")
 	if err != nil {
-		return err
+		return b.reply(msg.From.ID, locale.Error(lang, err))
 	}
 
-	return nil
+	if arg == "on" {
+		return b.replyMessage(msg.From.ID, lang, locale.MsgNotificationsOn, nil)
+	}
+
+	return b.replyMessage(msg.From.ID, lang, locale.MsgNotificationsOff, nil)
 }
 
-func (b MainBot) GetActiveConnections(msg tg.Message) error {
-	conns, err := b.connectionSvc.GetUserActiveConnections(context.Background(), msg.From.Username)
-	if err != nil || len(conns) <= 0 {
-		_, err := b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: "there is no active connections"})
+// SetLanguage changes the language of the user if it's given, otherwise asks the user to choose one.
+func (b MainBot) SetLanguage(msg tg.Message, arg string) error {
+	if arg != "" {
+		return b.changeLanguage(msg.From.ID, arg)
+	}
+
+	buttons := make([]tg.InlineKeyboardButton, 0, len(locale.Languages))
+	for _, lang := range locale.Languages {
+		query, err := NewQuery(QueryActionSelect).SetResource(QueryResourceLanguage).SetParam(lang).Marshal()
 		if err != nil {
 			return err
 		}
-	}
 
-	var reply string
-	for i, conn := range conns {
-		reply += fmt.Sprintf(
-			`
-			# %d
-			IP: %s
-			Location: %s
-			UserAgent: %s
-			Device: %s
-			Download Traffic Usage: %s
-			Upload Traffic Usage: %s
-			Connected At: %s
-			`, i+1, conn.RemoteIP, conn.Location, conn.UserAgent, conn.Hostname, util.ToHumanReadableBytes(conn.DownloadTrafficUsage),
-			util.ToHumanReadableBytes(conn.UploadTrafficUsage), conn.ConnectedAt.Format(TimeFormat))
+		buttons = append(buttons, tg.InlineKeyboardButton{
+			Text:         locale.LanguageNames[lang],
+			CallbackData: query,
+		})
 	}
 
-	_, err = b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: reply})
+	req, err := b.newMessage(msg.From.ID, b.language(msg.From), locale.MsgChooseLanguage, nil)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	req.ReplyMarkup = tg.NewInlineKeyboard(buttons...)
+
+	_, err = b.bot.SendMessage(req)
+
+	return err
 }
 
-func (b MainBot) ChangePassword(msg tg.Message) error {
-	newPassword, err := b.userSvc.ChangePassword(context.Background(), msg.From.Username)
+func (b MainBot) handleLanguageQuery(callbackQuery tg.CallbackQuery, query Query) error {
+	if err := b.bot.AnswerCallbackQuery(callbackQuery.ID, ""); err != nil {
+		return err
+	}
+
+	return b.changeLanguage(callbackQuery.From.ID, query.Param)
+}
+
+func (b MainBot) changeLanguage(userID int, lang string) error {
+	err := b.userSvc.SetLanguage(context.Background(), strconv.Itoa(userID), lang)
 	if err != nil {
-		_, err := b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: err.Error()})
-		if err != nil {
-			return err
-		}
+		return b.reply(userID, locale.Error(b.chatLanguage(userID), err))
 	}
 
-	reply := fmt.Sprintf(
-		`here is your username and your new password, this message will be deleted in an hour,
-         Userame: %s
-         Password: %s`, msg.From.Username, newPassword)
-	resp, err := b.bot.SendMessage(tg.SendMessageRequest{ChatID: msg.From.ID, Text: reply, ProtectContent: true})
+	return b.replyMessage(userID, lang, locale.MsgLanguageChanged, nil)
+}
+
+// language returns the language the user has chosen, or the language of their telegram client if they
+// haven't chosen any.
+func (b MainBot) language(from tg.From) string {
+	user, err := b.userSvc.GetUserByExternalID(context.Background(), strconv.Itoa(from.ID))
+	if err == nil && locale.IsSupported(user.Language) {
+		return user.Language
+	}
+
+	return locale.Detect(from.LanguageCode)
+}
+
+// chatLanguage returns the language of a private chat, which has the same id as the user.
+func (b MainBot) chatLanguage(chatID int) string {
+	return b.language(tg.From{ID: chatID})
+}
+
+func (b MainBot) SendUnknownMessage(from tg.From) error {
+	req, err := b.newMessage(from.ID, b.language(from), locale.MsgUnknownCommand, nil)
 	if err != nil {
 		return err
 	}
 
-	if len(resp) > 0 {
-		time.AfterFunc(1*time.Hour, func() {
-			if err := b.bot.DeleteMessage(msg.From.ID, resp[0].MessageID); err != nil {
-				b.logger.Error(err.Error())
-			}
-		})
+	req.ProtectContent = true
+
+	_, err = b.bot.SendMessage(req)
+
+	return err
+}
+
+// newMessage renders the message in the language, the request can be customized before it's sent.
+func (b MainBot) newMessage(chatID int, lang, key string, data tg.TemplateData) (tg.SendMessageRequest, error) {
+	msg, err := b.templateSvc.Render(context.Background(), lang, key, data)
+	if err != nil {
+		return tg.SendMessageRequest{}, err
 	}
 
-	return nil
+	return tg.SendMessageRequest{ChatID: chatID, Text: msg.Text, ParseMode: msg.ParseMode}, nil
 }
 
-func (b MainBot) SendUnknownMessage(id int) error {
-	_, err := b.bot.SendMessage(tg.SendMessageRequest{
-		ChatID:         id,
-		Text:           "huh? use /start if you don't know how to use me",
-		ProtectContent: true})
+func (b MainBot) replyMessage(chatID int, lang, key string, data tg.TemplateData) error {
+	req, err := b.newMessage(chatID, lang, key, data)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = b.bot.SendMessage(req)
+
+	return err
+}
+
+func formatTrafficLimit(lang string, pack model.PackageEntity) string {
+	if pack.HasUnlimitedTraffic() {
+		return locale.T(lang, locale.LabelUnlimited)
+	}
+
+	return util.ToHumanReadableBytes(pack.TrafficLimit)
+}
+
+func formatExpireAt(lang string, pack model.PackageEntity) string {
+	if pack.ExpireAt == nil {
+		return locale.T(lang, locale.LabelNever)
+	}
+
+	return pack.ExpireAt.Format(TimeFormat)
+}
+
+func formatExpiration(lang string, pack model.PackageEntity) string {
+	if !pack.HasExpiry() {
+		return locale.T(lang, locale.LabelNever)
+	}
+
+	return locale.T(lang, locale.LabelDays, pack.ExpirationInDays)
+}
+
+func isAuthorized(update tg.Update) bool {
+	return !update.Sender().IsBot
 }
 
 func mapCommandsByName(commands []tg.BotCommand) map[string]tg.BotCommand {
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"fmt"
+	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"github.com/charmbracelet/log"
+	"strings"
+	"sync"
+)
+
+// NewBotFunc builds a MainBot for the config, the bots share the services of the main bot.
+type NewBotFunc func(cfg *config.MainBotConfig, bot *tg.Bot) *MainBot
+
+// Manager runs the bots of the resellers next to the main bot, each one is a MainBot of its own running with
+// a copy of the main bot's config.
+type Manager struct {
+	mu      sync.RWMutex
+	cfg     *config.MainBotConfig
+	logger  *log.Logger
+	bots    *tg.Bots
+	newBot  NewBotFunc
+	running map[int]*MainBot
+	secrets map[int]string
+}
+
+func NewManager(cfg *config.MainBotConfig, logger *log.Logger, bots *tg.Bots, newBot NewBotFunc) *Manager {
+	return &Manager{
+		cfg:     cfg,
+		logger:  logger,
+		bots:    bots,
+		newBot:  newBot,
+		running: make(map[int]*MainBot),
+		secrets: make(map[int]string),
+	}
+}
+
+// Start starts the bot if it's not running already, it receives its updates the same way the main bot does.
+func (m *Manager) Start(entity model.BotEntity) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.running[entity.ID]; ok {
+		return
+	}
+
+	cfg := *m.cfg
+	cfg.ID = entity.ID
+	cfg.Token = entity.Token
+	cfg.Username = entity.Username
+	cfg.WebhookSecret = entity.WebhookSecret
+	// the support chat belongs to the main bot, tickets of every bot are sent there.
+	cfg.SupportChatID = 0
+
+	if cfg.WebhookUrl != "" {
+		cfg.WebhookUrl = fmt.Sprintf("%s/%d", strings.TrimSuffix(cfg.WebhookUrl, "/"), entity.ID)
+	}
+
+	client := tg.NewBot(entity.Token)
+	mainBot := m.newBot(&cfg, client)
+
+	m.running[entity.ID] = mainBot
+	m.secrets[entity.ID] = entity.WebhookSecret
+	m.bots.Add(entity.ID, client)
+
+	go func() {
+		if err := mainBot.Run(); err != nil {
+			m.logger.Error(err.Error(), "bot_id", entity.ID)
+		}
+	}()
+}
+
+// Stop stops the bot, it won't get any updates until it's started again.
+func (m *Manager) Stop(id int) {
+	m.mu.Lock()
+	mainBot, ok := m.running[id]
+	delete(m.running, id)
+	delete(m.secrets, id)
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	m.bots.Remove(id)
+
+	// otherwise telegram keeps retrying the updates on the webhook of the stopped bot.
+	if err := mainBot.bot.DeleteWebhook(); err != nil {
+		m.logger.Error(err.Error(), "bot_id", id)
+	}
+
+	mainBot.Stop()
+}
+
+// WebhookSecret returns the secret the webhook of the bot is set with, it's false if the bot isn't running.
+func (m *Manager) WebhookSecret(id int) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	secret, ok := m.secrets[id]
+
+	return secret, ok
+}
+
+// HandleBotUpdates passes the updates received from the webhook to the bot they belong to.
+func (m *Manager) HandleBotUpdates(id int, updates []tg.Update) error {
+	m.mu.RLock()
+	mainBot, ok := m.running[id]
+	m.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("bot %d is not running", id)
+	}
+
+	return mainBot.HandleUpdates(updates)
+}
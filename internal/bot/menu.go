@@ -0,0 +1,595 @@
+package bot
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"github.com/alir32a/jupiter/pkg/util"
+	"strconv"
+	"time"
+)
+
+const (
+	QueryResourceMenu = "menu"
+	QueryActionOpen   = "open"
+
+	ScreenStatus      = "status"
+	ScreenConnections = "connections"
+	ScreenSession     = "session"
+	ScreenDisconnect  = "disconnect"
+	ScreenPackages    = "packages"
+	// ScreenLogout asks the user to confirm logging out everywhere, ScreenLogoutConfirmed does it.
+	ScreenLogout          = "logout"
+	ScreenLogoutConfirmed = "logout_confirmed"
+	ScreenAccounts        = "accounts"
+	ScreenSwitchAccount   = "switch_account"
+	ScreenNewAccount      = "new_account"
+
+	menuPageSize = 5
+)
+
+type CallbackService interface {
+	StorePayloads(ctx context.Context, chatID int, payloads []map[string]string, ttl time.Duration) ([]string, error)
+	GetPayload(ctx context.Context, chatID int, key string) (map[string]string, error)
+}
+
+// Screen is a state of the menu, it's stored on the server and the buttons only carry its key, so screens
+// can hold more than fits in the 64 bytes of callback data.
+type Screen struct {
+	Name         string
+	Page         int
+	ConnectionID int
+	// AccountID is the account the screen is about, screens opened by a command are about the active
+	// account and keep showing it even if the user switches to another one later.
+	AccountID int
+}
+
+func (s Screen) payload() map[string]string {
+	return map[string]string{
+		"screen":        s.Name,
+		"page":          strconv.Itoa(s.Page),
+		"connection_id": strconv.Itoa(s.ConnectionID),
+		"account_id":    strconv.Itoa(s.AccountID),
+	}
+}
+
+func parseScreen(payload map[string]string) Screen {
+	page, _ := strconv.Atoi(payload["page"])
+	connectionID, _ := strconv.Atoi(payload["connection_id"])
+	accountID, _ := strconv.Atoi(payload["account_id"])
+
+	return Screen{
+		Name:         payload["screen"],
+		Page:         page,
+		ConnectionID: connectionID,
+		AccountID:    accountID,
+	}
+}
+
+type menuButton struct {
+	Text   string
+	Screen Screen
+}
+
+// menuView is a rendered screen, notice is shown to the user when the screen is opened from a button.
+type menuView struct {
+	msg    model.RenderedMessage
+	rows   [][]menuButton
+	notice string
+}
+
+// openMenu sends the screen in a new message, the buttons of the menu edit this message from then on.
+func (b MainBot) openMenu(msg tg.Message, screen Screen) error {
+	ctx := context.Background()
+
+	view, err := b.renderScreen(ctx, msg.From, screen)
+	if err != nil {
+		return err
+	}
+
+	keyboard, err := b.menuKeyboard(ctx, msg.Chat.ID, view.rows)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.bot.SendMessage(tg.SendMessageRequest{
+		ChatID:      msg.Chat.ID,
+		Text:        view.msg.Text,
+		ParseMode:   view.msg.ParseMode,
+		ReplyMarkup: keyboard,
+	})
+
+	return err
+}
+
+func (b MainBot) handleMenuQuery(callbackQuery tg.CallbackQuery, query Query) error {
+	ctx := context.Background()
+	chatID := callbackQuery.Message.Chat.ID
+	lang := b.language(callbackQuery.From)
+
+	payload, err := b.callbackSvc.GetPayload(ctx, chatID, query.Param)
+	if err != nil {
+		return b.bot.AnswerCallbackQuery(callbackQuery.ID, locale.Error(lang, err))
+	}
+
+	screen := parseScreen(payload)
+
+	var notice string
+	switch screen.Name {
+	case ScreenDisconnect:
+		notice = b.disconnect(ctx, callbackQuery.From, lang, screen)
+		screen = Screen{Name: ScreenConnections, AccountID: screen.AccountID}
+	case ScreenLogoutConfirmed:
+		notice = b.logoutEverywhere(ctx, callbackQuery.From, lang, screen.AccountID)
+		screen = Screen{Name: ScreenConnections, AccountID: screen.AccountID}
+	case ScreenSwitchAccount:
+		notice = b.switchAccount(ctx, callbackQuery.From, lang, screen.AccountID)
+		screen = Screen{Name: ScreenAccounts}
+	case ScreenNewAccount:
+		notice, screen = b.createAccount(ctx, callbackQuery.From, lang)
+	}
+
+	view, err := b.renderScreen(ctx, callbackQuery.From, screen)
+	if err != nil {
+		return err
+	}
+
+	if notice == "" {
+		notice = view.notice
+	}
+
+	keyboard, err := b.menuKeyboard(ctx, chatID, view.rows)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.bot.EditMessageText(tg.EditMessageTextRequest{
+		ChatID:      chatID,
+		MessageID:   callbackQuery.Message.MessageID,
+		Text:        view.msg.Text,
+		ParseMode:   view.msg.ParseMode,
+		ReplyMarkup: keyboard,
+	})
+	if err != nil && !tg.IsNotModified(err) {
+		return err
+	}
+
+	return b.bot.AnswerCallbackQuery(callbackQuery.ID, notice)
+}
+
+func (b MainBot) disconnect(ctx context.Context, from tg.From, lang string, screen Screen) string {
+	user, err := b.account(ctx, from, screen.AccountID)
+	if err != nil {
+		return locale.Error(lang, err)
+	}
+
+	if err := b.connectionSvc.DisconnectUserConnection(ctx, user.Username, screen.ConnectionID); err != nil {
+		return locale.Error(lang, err)
+	}
+
+	return locale.T(lang, locale.LabelDisconnected)
+}
+
+// logoutEverywhere changes the password before disconnecting the devices, so they can't connect again with
+// the old one, the new password is sent in a separate message.
+func (b MainBot) logoutEverywhere(ctx context.Context, from tg.From, lang string, accountID int) string {
+	user, err := b.account(ctx, from, accountID)
+	if err != nil {
+		return locale.Error(lang, err)
+	}
+
+	pass, err := b.userSvc.ChangePassword(ctx, user.Username)
+	if err != nil {
+		return locale.Error(lang, err)
+	}
+
+	if err := b.connectionSvc.DisconnectAllUserConnections(ctx, user.Username); err != nil {
+		return locale.Error(lang, err)
+	}
+
+	if err := b.sendCredentials(from.ID, lang, locale.MsgNewPassword, user.Username, pass); err != nil {
+		b.logger.Error("sending the new password", "user", user.Username, "err", err)
+	}
+
+	return locale.T(lang, locale.LabelLoggedOutEverywhere)
+}
+
+func (b MainBot) switchAccount(ctx context.Context, from tg.From, lang string, accountID int) string {
+	account, err := b.userSvc.SwitchAccount(ctx, strconv.Itoa(from.ID), accountID)
+	if err != nil {
+		return locale.Error(lang, err)
+	}
+
+	return locale.T(lang, locale.LabelSwitchedAccount, account.Username)
+}
+
+// createAccount creates another account and sends its credentials, the menu shows the status of the
+// new account afterward.
+func (b MainBot) createAccount(ctx context.Context, from tg.From, lang string) (string, Screen) {
+	account, err := b.userSvc.CreateAccount(ctx, strconv.Itoa(from.ID))
+	if err != nil {
+		return locale.Error(lang, err), Screen{Name: ScreenAccounts}
+	}
+
+	if err := b.sendCredentials(from.ID, lang, locale.MsgCredentials, account.Username, account.Password); err != nil {
+		b.logger.Error("sending the credentials", "user", account.Username, "err", err)
+	}
+
+	return locale.T(lang, locale.LabelAccountCreated, account.Username), Screen{Name: ScreenStatus, AccountID: account.ID}
+}
+
+// account returns the account the screen is about, or the active account if it's not about a specific one.
+func (b MainBot) account(ctx context.Context, from tg.From, accountID int) (model.UserEntity, error) {
+	if accountID == 0 {
+		return b.userSvc.GetActiveAccount(ctx, strconv.Itoa(from.ID))
+	}
+
+	return b.userSvc.GetAccount(ctx, strconv.Itoa(from.ID), accountID)
+}
+
+// menuKeyboard stores the screens of the buttons and returns the keyboard.
+func (b MainBot) menuKeyboard(ctx context.Context, chatID int, rows [][]menuButton) (*tg.InlineKeyboard, error) {
+	var payloads []map[string]string
+	for _, row := range rows {
+		for _, button := range row {
+			payloads = append(payloads, button.Screen.payload())
+		}
+	}
+
+	keys, err := b.callbackSvc.StorePayloads(ctx, chatID, payloads, b.cfg.MenuTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	keyboardRows := make([][]tg.InlineKeyboardButton, 0, len(rows))
+	for _, row := range rows {
+		keyboardRow := make([]tg.InlineKeyboardButton, 0, len(row))
+
+		for _, button := range row {
+			query, err := NewQuery(QueryActionOpen).SetResource(QueryResourceMenu).SetParam(keys[0]).Marshal()
+			if err != nil {
+				return nil, err
+			}
+
+			keys = keys[1:]
+
+			keyboardRow = append(keyboardRow, tg.InlineKeyboardButton{Text: button.Text, CallbackData: query})
+		}
+
+		keyboardRows = append(keyboardRows, keyboardRow)
+	}
+
+	return tg.NewInlineKeyboardRows(keyboardRows...), nil
+}
+
+func (b MainBot) renderScreen(ctx context.Context, from tg.From, screen Screen) (menuView, error) {
+	lang := b.language(from)
+
+	if screen.Name == ScreenAccounts {
+		return b.renderAccounts(ctx, lang, from)
+	}
+
+	user, err := b.account(ctx, from, screen.AccountID)
+	if err != nil {
+		return menuView{msg: model.RenderedMessage{Text: locale.Error(lang, err)}}, nil
+	}
+
+	var view menuView
+
+	switch screen.Name {
+	case ScreenConnections:
+		view, err = b.renderConnections(ctx, lang, user)
+	case ScreenSession:
+		view, err = b.renderSession(ctx, lang, user, screen.ConnectionID)
+	case ScreenPackages:
+		view, err = b.renderPackages(ctx, lang, user, screen.Page)
+	case ScreenLogout:
+		view, err = b.renderLogout(ctx, lang)
+	default:
+		view, err = b.renderStatus(ctx, lang, user)
+	}
+
+	// the buttons stay on the account of this screen.
+	for _, row := range view.rows {
+		for i := range row {
+			if row[i].Screen.AccountID == 0 && row[i].Screen.Name != ScreenAccounts {
+				row[i].Screen.AccountID = user.ID
+			}
+		}
+	}
+
+	return view, err
+}
+
+func (b MainBot) renderAccounts(ctx context.Context, lang string, from tg.From) (menuView, error) {
+	accounts, err := b.userSvc.GetAccounts(ctx, strconv.Itoa(from.ID))
+	if err != nil {
+		return menuView{msg: model.RenderedMessage{Text: locale.Error(lang, err)}}, nil
+	}
+
+	view := menuView{}
+	items := make([]tg.TemplateData, 0, len(accounts.Accounts))
+
+	for i, account := range accounts.Accounts {
+		text := account.Username
+		if account.ID == accounts.ActiveID {
+			text = locale.T(lang, locale.LabelAccountActive, account.Username)
+		}
+
+		items = append(items, tg.TemplateData{
+			"Index":    i + 1,
+			"Username": account.Username,
+			"Active":   account.ID == accounts.ActiveID,
+		})
+
+		view.rows = append(view.rows, []menuButton{{
+			Text:   text,
+			Screen: Screen{Name: ScreenSwitchAccount, AccountID: account.ID},
+		}})
+	}
+
+	view.msg, err = b.templateSvc.Render(ctx, lang, locale.MsgAccounts, tg.TemplateData{"Accounts": items})
+	if err != nil {
+		return menuView{}, err
+	}
+
+	if len(accounts.Accounts) < b.cfg.MaxAccounts {
+		view.rows = append(view.rows, []menuButton{
+			{Text: locale.T(lang, locale.LabelMenuNewAccount), Screen: Screen{Name: ScreenNewAccount}},
+		})
+	}
+
+	view.rows = append(view.rows, []menuButton{
+		{Text: locale.T(lang, locale.LabelMenuBack), Screen: Screen{Name: ScreenStatus}},
+	})
+
+	return view, nil
+}
+
+func (b MainBot) renderStatus(ctx context.Context, lang string, user model.UserEntity) (menuView, error) {
+	view := menuView{
+		rows: [][]menuButton{
+			{
+				{Text: locale.T(lang, locale.LabelMenuConnections), Screen: Screen{Name: ScreenConnections}},
+				{Text: locale.T(lang, locale.LabelMenuPackages), Screen: Screen{Name: ScreenPackages, Page: 1}},
+			},
+			{
+				{Text: locale.T(lang, locale.LabelMenuAccounts), Screen: Screen{Name: ScreenAccounts}},
+				{Text: locale.T(lang, locale.LabelMenuRefresh), Screen: Screen{Name: ScreenStatus}},
+			},
+		},
+	}
+
+	packages, err := b.packageSvc.GetUserActivePackages(ctx, user.Username)
+	if err != nil {
+		view.msg = model.RenderedMessage{Text: locale.Error(lang, err)}
+
+		return view, nil
+	}
+
+	if packages.ActivePackage.ID == 0 {
+		view.msg, err = b.templateSvc.Render(ctx, lang, locale.MsgNoActivePackage, nil)
+
+		return view, err
+	}
+
+	view.msg, err = b.templateSvc.Render(ctx, lang, locale.MsgStatus, statusData(lang, user.Username, packages))
+
+	return view, err
+}
+
+func (b MainBot) renderConnections(ctx context.Context, lang string, user model.UserEntity) (menuView, error) {
+	view := menuView{}
+
+	conns, err := b.connectionSvc.GetUserActiveConnections(ctx, user.Username)
+	if err != nil || len(conns) <= 0 {
+		view.msg, err = b.templateSvc.Render(ctx, lang, locale.MsgNoActiveConnections, nil)
+	} else {
+		view.msg, err = b.templateSvc.Render(ctx, lang, locale.MsgConnections, connectionsData(conns))
+	}
+	if err != nil {
+		return menuView{}, err
+	}
+
+	for i, conn := range conns {
+		view.rows = append(view.rows, []menuButton{{
+			Text:   locale.T(lang, locale.LabelMenuSession, i+1, conn.Hostname, conn.RemoteIP),
+			Screen: Screen{Name: ScreenSession, ConnectionID: conn.ID},
+		}})
+	}
+
+	view.rows = append(view.rows,
+		[]menuButton{{Text: locale.T(lang, locale.LabelMenuLogoutEverywhere), Screen: Screen{Name: ScreenLogout}}},
+		[]menuButton{
+			{Text: locale.T(lang, locale.LabelMenuBack), Screen: Screen{Name: ScreenStatus}},
+			{Text: locale.T(lang, locale.LabelMenuRefresh), Screen: Screen{Name: ScreenConnections}},
+		},
+	)
+
+	return view, nil
+}
+
+func (b MainBot) renderLogout(ctx context.Context, lang string) (menuView, error) {
+	msg, err := b.templateSvc.Render(ctx, lang, locale.MsgLogoutEverywhere, nil)
+	if err != nil {
+		return menuView{}, err
+	}
+
+	return menuView{
+		msg: msg,
+		rows: [][]menuButton{{
+			{Text: locale.T(lang, locale.LabelMenuConfirm), Screen: Screen{Name: ScreenLogoutConfirmed}},
+			{Text: locale.T(lang, locale.LabelMenuBack), Screen: Screen{Name: ScreenConnections}},
+		}},
+	}, nil
+}
+
+func (b MainBot) renderSession(ctx context.Context, lang string, user model.UserEntity, connectionID int) (menuView, error) {
+	conns, err := b.connectionSvc.GetUserActiveConnections(ctx, user.Username)
+	if err != nil {
+		return menuView{}, err
+	}
+
+	for _, conn := range conns {
+		if conn.ID != connectionID {
+			continue
+		}
+
+		msg, err := b.templateSvc.Render(ctx, lang, locale.MsgSession, sessionData(conn))
+		if err != nil {
+			return menuView{}, err
+		}
+
+		return menuView{
+			msg: msg,
+			rows: [][]menuButton{
+				{{
+					Text:   locale.T(lang, locale.LabelMenuDisconnect),
+					Screen: Screen{Name: ScreenDisconnect, ConnectionID: conn.ID},
+				}},
+				{{Text: locale.T(lang, locale.LabelMenuBack), Screen: Screen{Name: ScreenConnections}}},
+			},
+		}, nil
+	}
+
+	// the connection has ended since the list was shown.
+	view, err := b.renderConnections(ctx, lang, user)
+	view.notice = locale.Error(lang, errorext.ErrConnectionNotFound)
+
+	return view, err
+}
+
+func (b MainBot) renderPackages(ctx context.Context, lang string, user model.UserEntity, page int) (menuView, error) {
+	view := menuView{}
+
+	packages, err := b.packageSvc.GetUserActiveAndReservedPackages(ctx, user.ID)
+	if err != nil {
+		view.msg = model.RenderedMessage{Text: locale.Error(lang, err)}
+	}
+
+	all := packages.ReservedPackages
+	if packages.ActivePackage.ID != 0 {
+		all = append([]model.PackageEntity{packages.ActivePackage}, all...)
+	}
+
+	if err == nil && len(all) <= 0 {
+		view.msg, err = b.templateSvc.Render(ctx, lang, locale.MsgNoActivePackage, nil)
+		if err != nil {
+			return menuView{}, err
+		}
+	}
+
+	totalPages := max(1, (len(all)+menuPageSize-1)/menuPageSize)
+	page = min(max(page, 1), totalPages)
+
+	if len(all) > 0 {
+		start := (page - 1) * menuPageSize
+		end := min(start+menuPageSize, len(all))
+
+		items := make([]tg.TemplateData, 0, end-start)
+		for i, pack := range all[start:end] {
+			items = append(items, packageData(lang, start+i+1, pack))
+		}
+
+		view.msg, err = b.templateSvc.Render(ctx, lang, locale.MsgPackages, tg.TemplateData{
+			"Packages":   items,
+			"Page":       page,
+			"TotalPages": totalPages,
+		})
+		if err != nil {
+			return menuView{}, err
+		}
+	}
+
+	var pagination []menuButton
+	if page > 1 {
+		pagination = append(pagination, menuButton{
+			Text:   locale.T(lang, locale.LabelMenuPrevious),
+			Screen: Screen{Name: ScreenPackages, Page: page - 1},
+		})
+	}
+
+	if page < totalPages {
+		pagination = append(pagination, menuButton{
+			Text:   locale.T(lang, locale.LabelMenuNext),
+			Screen: Screen{Name: ScreenPackages, Page: page + 1},
+		})
+	}
+
+	view.rows = [][]menuButton{
+		pagination,
+		{{Text: locale.T(lang, locale.LabelMenuBack), Screen: Screen{Name: ScreenStatus}}},
+	}
+
+	return view, nil
+}
+
+func statusData(lang, username string, packages model.GetUserPackages) tg.TemplateData {
+	activePack := packages.ActivePackage
+
+	reserved := make([]tg.TemplateData, 0, len(packages.ReservedPackages))
+	for i, pack := range packages.ReservedPackages {
+		reserved = append(reserved, tg.TemplateData{
+			"Index":          i + 1,
+			"TrafficLimit":   formatTrafficLimit(lang, pack),
+			"MaxConnections": pack.MaxConnections,
+			"Expiration":     formatExpiration(lang, pack),
+		})
+	}
+
+	return tg.TemplateData{
+		"Username":         username,
+		"TrafficLimit":     formatTrafficLimit(lang, activePack),
+		"DownloadUsage":    util.ToHumanReadableBytes(activePack.DownloadTrafficUsage),
+		"UploadUsage":      util.ToHumanReadableBytes(activePack.UploadTrafficUsage),
+		"TotalUsage":       util.ToHumanReadableBytes(activePack.DownloadTrafficUsage + activePack.UploadTrafficUsage),
+		"MaxConnections":   activePack.MaxConnections,
+		"ExpireAt":         formatExpireAt(lang, activePack),
+		"FairUse":          activePack.HasFairUse(),
+		"ThrottleRate":     util.ToHumanReadableBytes(activePack.ThrottleRate),
+		"FairUseCap":       util.ToHumanReadableBytes(activePack.FairUseCap),
+		"ReservedPackages": reserved,
+	}
+}
+
+func connectionsData(conns []model.ConnectionEntity) tg.TemplateData {
+	connections := make([]tg.TemplateData, 0, len(conns))
+	for i, conn := range conns {
+		data := sessionData(conn)
+		data["Index"] = i + 1
+
+		connections = append(connections, data)
+	}
+
+	return tg.TemplateData{"Connections": connections}
+}
+
+func sessionData(conn model.ConnectionEntity) tg.TemplateData {
+	return tg.TemplateData{
+		"IP":            conn.RemoteIP,
+		"Location":      conn.Location,
+		"UserAgent":     conn.UserAgent,
+		"Device":        conn.Hostname,
+		"DownloadUsage": util.ToHumanReadableBytes(conn.DownloadTrafficUsage),
+		"UploadUsage":   util.ToHumanReadableBytes(conn.UploadTrafficUsage),
+		"ConnectedAt":   conn.ConnectedAt.Format(TimeFormat),
+	}
+}
+
+func packageData(lang string, index int, pack model.PackageEntity) tg.TemplateData {
+	status, expiration := locale.T(lang, locale.LabelPackageReserved), formatExpiration(lang, pack)
+	if pack.IsActive() {
+		status, expiration = locale.T(lang, locale.LabelPackageActive), formatExpireAt(lang, pack)
+	}
+
+	return tg.TemplateData{
+		"Index":          index,
+		"Status":         status,
+		"TrafficLimit":   formatTrafficLimit(lang, pack),
+		"TotalUsage":     util.ToHumanReadableBytes(pack.DownloadTrafficUsage + pack.UploadTrafficUsage),
+		"MaxConnections": pack.MaxConnections,
+		"Expiration":     expiration,
+	}
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+)
+
+// maxListedPlans is how many plans /plans shows, the newest ones are shown first.
+const maxListedPlans = 20
+
+type PlanService interface {
+	GetPlans(ctx context.Context, req model.GetPlansRequest) (model.GetPlansResponse, error)
+}
+
+// GetPlans shows the active plans of the bot, every bot sells its own plans with its own prices.
+func (b MainBot) GetPlans(msg tg.Message) error {
+	lang := b.language(msg.From)
+
+	resp, err := b.planSvc.GetPlans(context.Background(), model.GetPlansRequest{
+		Pagination: model.Pagination{CurrentPage: 1, PageSize: maxListedPlans},
+		BotID:      &b.cfg.ID,
+		ActiveOnly: true,
+	})
+	if err != nil {
+		return b.reply(msg.From.ID, locale.Error(lang, err))
+	}
+
+	if len(resp.Plans) == 0 {
+		return b.replyMessage(msg.From.ID, lang, locale.MsgNoPlans, nil)
+	}
+
+	plans := make([]tg.TemplateData, 0, len(resp.Plans))
+	for _, plan := range resp.Plans {
+		plans = append(plans, tg.TemplateData{
+			"Name":           plan.Name,
+			"TrafficLimit":   formatTrafficLimit(lang, planPackage(plan)),
+			"MaxConnections": plan.MaxConnections,
+			"Expiration":     formatExpiration(lang, planPackage(plan)),
+			"Price":          plan.Price,
+		})
+	}
+
+	return b.replyMessage(msg.From.ID, lang, locale.MsgPlans, tg.TemplateData{"Plans": plans})
+}
+
+// planPackage returns the package the plan creates, so it's formatted the same way as the packages.
+func planPackage(plan model.PlanEntity) model.PackageEntity {
+	return model.PackageEntity{
+		PackageType:      plan.PackageType,
+		TrafficLimit:     plan.TrafficLimit,
+		MaxConnections:   plan.MaxConnections,
+		ExpirationInDays: plan.ExpirationInDays,
+	}
+}
@@ -5,12 +5,16 @@ import "encoding/json"
 const (
 	QueryActionGetResource = "get_resource"
 	QueryActionCancel      = "cancel"
+	QueryActionConfirm     = "confirm"
+	QueryActionSelect      = "select"
 )
 
+// Query is sent as the callback data of inline keyboard buttons, telegram limits callback data to 64 bytes,
+// hence the short field names.
 type Query struct {
-	Action   string `json:"action"`
-	Resource string `json:"resource,omitempty"`
-	Param    string `json:"param,omitempty"`
+	Action   string `json:"a"`
+	Resource string `json:"r,omitempty"`
+	Param    string `json:"p,omitempty"`
 }
 
 func (q *Query) SetResource(v string) *Query {
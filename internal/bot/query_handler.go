@@ -5,7 +5,7 @@ import (
 	"github.com/alir32a/jupiter/pkg/tg"
 )
 
-type QueryHandler func(callbackQueryID string, query Query) error
+type QueryHandler func(callbackQuery tg.CallbackQuery, query Query) error
 
 type QueryCommander struct {
 	queries         map[string]QueryHandler
@@ -42,5 +42,5 @@ func (q *QueryCommander) Handle(callbackQuery tg.CallbackQuery) error {
 		return errors.New("resource not found")
 	}
 
-	return handler(callbackQuery.ID, *query)
+	return handler(callbackQuery, *query)
 }
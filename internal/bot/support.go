@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	ConversationSupport = "support"
+	stepSupportMessage  = "message"
+
+	supportTicketsPageSize = 10
+)
+
+type SupportService interface {
+	CreateTicket(ctx context.Context, req model.CreateSupportMessageRequest) (model.SupportTicketEntity, error)
+	Reply(ctx context.Context, req model.ReplySupportTicketRequest) error
+	ReplyToForwarded(ctx context.Context, forwardedMessageID int, adminUsername, text string) (int, error)
+	CloseTicket(ctx context.Context, id int) error
+	GetTickets(ctx context.Context, req model.GetSupportTicketsRequest) (model.GetSupportTicketsResponse, error)
+}
+
+// Support adds the text after the command to the user's ticket, or asks for it if there isn't any.
+func (b MainBot) Support(msg tg.Message, text string) error {
+	if text == "" {
+		return b.conversations.Start(ConversationSupport, msg.Chat.ID, msg.From, nil)
+	}
+
+	return b.createTicket(msg.Chat.ID, msg.From, text)
+}
+
+func (b MainBot) supportConversation() Conversation {
+	return Conversation{
+		Name:      ConversationSupport,
+		FirstStep: stepSupportMessage,
+		Steps: map[string]ConversationStep{
+			stepSupportMessage: {
+				Prompt: func(state ConversationState) (string, error) {
+					return locale.T(b.language(state.From), locale.LabelEnterSupportMessage), nil
+				},
+				Handle: func(state ConversationState, input string) (string, error) {
+					err := b.createTicket(state.ChatID, state.From, input)
+					if err != nil {
+						var extErr *errorext.Error
+						if errors.As(err, &extErr) && extErr.Status() == http.StatusBadRequest {
+							return "", NewInputError(locale.Error(b.language(state.From), err))
+						}
+
+						return "", err
+					}
+
+					return EndConversation, nil
+				},
+			},
+		},
+	}
+}
+
+func (b MainBot) createTicket(chatID int, from tg.From, text string) error {
+	ctx := context.Background()
+
+	req := model.CreateSupportMessageRequest{
+		BotID:  b.cfg.ID,
+		ChatID: strconv.Itoa(chatID),
+		From:   supportSender(from),
+		Text:   text,
+	}
+
+	if user, err := b.userSvc.GetUserByExternalID(ctx, strconv.Itoa(from.ID)); err == nil {
+		req.UserID = &user.ID
+		req.From += fmt.Sprintf(" (user %s)", user.Username)
+	}
+
+	if b.cfg.ID != model.MainBotID {
+		req.From += fmt.Sprintf(" via @%s", b.cfg.Username)
+	}
+
+	ticket, err := b.supportSvc.CreateTicket(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return b.replyMessage(chatID, b.language(from), locale.MsgTicketReceived, tg.TemplateData{"ID": ticket.ID})
+}
+
+// isSupportChat reports whether the message was sent in the chat support tickets are copied to.
+func (b MainBot) isSupportChat(msg tg.Message) bool {
+	return b.cfg.SupportChatID != 0 && msg.Chat.ID == b.cfg.SupportChatID
+}
+
+// handleSupportChatMessage answers the ticket of the message the admin has replied to in the support chat,
+// other messages of the chat are ignored, so the admins can talk to each other there.
+func (b MainBot) handleSupportChatMessage(msg tg.Message) error {
+	if msg.ReplyToMessage == nil || strings.TrimSpace(msg.Text) == "" {
+		return nil
+	}
+
+	actor, err := b.authorizeAdmin(msg.From)
+	if err != nil {
+		if errors.Is(err, errorext.ErrNotAdmin) {
+			return nil
+		}
+
+		return err
+	}
+
+	id, err := b.supportSvc.ReplyToForwarded(context.Background(), msg.ReplyToMessage.MessageID, actor, msg.Text)
+	if err != nil {
+		return b.reply(msg.Chat.ID, err.Error())
+	}
+
+	return b.reply(msg.Chat.ID, fmt.Sprintf("reply to ticket #%d has been sent by %s", id, actor))
+}
+
+func (b MainBot) AdminGetTickets(msg tg.Message) error {
+	resp, err := b.supportSvc.GetTickets(context.Background(), model.GetSupportTicketsRequest{
+		Status:     model.SupportTicketStatusOpen,
+		Pagination: model.Pagination{PageSize: supportTicketsPageSize},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Tickets) <= 0 {
+		return b.reply(msg.From.ID, "there are no open tickets")
+	}
+
+	reply := fmt.Sprintf("open tickets (%d):\n", resp.Total)
+	for _, ticket := range resp.Tickets {
+		username := ticket.Username
+		if username == "" {
+			username = "chat " + ticket.ChatID
+		}
+
+		reply += fmt.Sprintf("- #%d %s, updated at %s\n", ticket.ID, username, ticket.UpdatedAt.Format(TimeFormat))
+	}
+
+	return b.reply(msg.From.ID, reply)
+}
+
+// AdminReplyTicket sends the rest of the message after the ticket id to the user, line breaks are kept.
+func (b MainBot) AdminReplyTicket(msg tg.Message, actor, args string) error {
+	params := strings.Fields(args)
+	if len(params) < 2 {
+		return b.reply(msg.From.ID, AdminBotCommands["/reply"])
+	}
+
+	id, err := strconv.Atoi(params[0])
+	if err != nil {
+		return b.reply(msg.From.ID, fmt.Sprintf("%s is not a valid ticket id", params[0]))
+	}
+
+	err = b.supportSvc.Reply(context.Background(), model.ReplySupportTicketRequest{
+		TicketID:      id,
+		AdminUsername: actor,
+		Text:          strings.TrimPrefix(args, params[0]),
+	})
+	if err != nil {
+		return b.reply(msg.From.ID, err.Error())
+	}
+
+	return b.reply(msg.From.ID, "done")
+}
+
+func (b MainBot) AdminCloseTicket(msg tg.Message, params []string) error {
+	if len(params) != 1 {
+		return b.reply(msg.From.ID, AdminBotCommands["/close"])
+	}
+
+	id, err := strconv.Atoi(params[0])
+	if err != nil {
+		return b.reply(msg.From.ID, fmt.Sprintf("%s is not a valid ticket id", params[0]))
+	}
+
+	if err := b.supportSvc.CloseTicket(context.Background(), id); err != nil {
+		return b.reply(msg.From.ID, err.Error())
+	}
+
+	return b.reply(msg.From.ID, "done")
+}
+
+// supportSender is how the telegram user is shown to the admins in the support chat.
+func supportSender(from tg.From) string {
+	if from.Username != "" {
+		return "@" + from.Username
+	}
+
+	return fmt.Sprintf("%s (%d)", from.FirstName, from.ID)
+}
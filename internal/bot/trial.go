@@ -0,0 +1,204 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+const (
+	ConversationCaptcha = "captcha"
+	stepCaptchaAnswer   = "answer"
+
+	captchaDataA = "a"
+	captchaDataB = "b"
+)
+
+// ChatMemberGetter looks up the membership of the users in the required channel, the main bot does it for
+// every bot, since only it is an administrator of the channel.
+type ChatMemberGetter interface {
+	GetChatMember(chatID string, userID int) (tg.ChatMember, error)
+}
+
+// CreateUser creates the user of the telegram account, if the trial package is gated, the user gets their
+// account right away and the trial once they join the required channel and answer the captcha.
+func (b MainBot) CreateUser(msg tg.Message) error {
+	if !b.trialCfg.Gated() {
+		_, _, err := b.createUser(msg.From, false)
+
+		return err
+	}
+
+	user, err := b.userSvc.GetUserByExternalID(context.Background(), strconv.Itoa(msg.From.ID))
+	if err != nil {
+		if !errorext.IsNotFound(err) {
+			return err
+		}
+
+		var created bool
+
+		user, created, err = b.createUser(msg.From, true)
+		if err != nil || !created {
+			return err
+		}
+	}
+
+	// users that have no trial left to claim get the usual error instead of going through the gate.
+	if !user.TrialPending {
+		return b.reply(msg.From.ID, locale.Error(b.language(msg.From), errorext.ErrUserAlreadyExists))
+	}
+
+	joined, err := b.hasJoinedChannel(msg.From)
+	if err != nil {
+		return err
+	}
+
+	if !joined {
+		return b.askToJoinChannel(msg.From)
+	}
+
+	if b.trialCfg.Captcha {
+		return b.conversations.Start(ConversationCaptcha, msg.Chat.ID, msg.From, newCaptcha())
+	}
+
+	return b.grantTrial(msg.From)
+}
+
+func (b MainBot) grantTrial(from tg.From) error {
+	ctx := context.Background()
+	lang := b.language(from)
+
+	user, err := b.userSvc.GetUserByExternalID(ctx, strconv.Itoa(from.ID))
+	if err != nil {
+		return b.reply(from.ID, locale.Error(lang, err))
+	}
+
+	if err := b.userSvc.GrantTrial(ctx, user.ID); err != nil {
+		return b.reply(from.ID, locale.Error(lang, err))
+	}
+
+	return b.reply(from.ID, locale.T(lang, locale.LabelTrialGranted))
+}
+
+func (b MainBot) hasJoinedChannel(from tg.From) (bool, error) {
+	if b.trialCfg.RequiredChannel == "" {
+		return true, nil
+	}
+
+	member, err := b.members.GetChatMember(b.trialCfg.RequiredChannel, from.ID)
+	if err != nil {
+		return false, fmt.Errorf("couldn't check the membership of %d in %s: %w", from.ID,
+			b.trialCfg.RequiredChannel, err)
+	}
+
+	return member.InChat(), nil
+}
+
+func (b MainBot) askToJoinChannel(from tg.From) error {
+	lang := b.language(from)
+
+	req, err := b.newMessage(from.ID, lang, locale.MsgJoinChannel, tg.TemplateData{
+		"Channel": b.trialCfg.RequiredChannel,
+	})
+	if err != nil {
+		return err
+	}
+
+	// only public channels have a link, private ones are joined with the invite links the admins share.
+	if channel, ok := strings.CutPrefix(b.trialCfg.RequiredChannel, "@"); ok {
+		req.ReplyMarkup = tg.NewInlineKeyboard(tg.InlineKeyboardButton{
+			Text: locale.T(lang, locale.LabelJoinChannel),
+			Url:  "https://t.me/" + channel,
+		})
+	}
+
+	_, err = b.bot.SendMessage(req)
+
+	return err
+}
+
+func (b MainBot) captchaConversation() Conversation {
+	return Conversation{
+		Name:      ConversationCaptcha,
+		FirstStep: stepCaptchaAnswer,
+		Steps: map[string]ConversationStep{
+			stepCaptchaAnswer: {
+				Prompt: func(state ConversationState) (string, error) {
+					x, _ := strconv.Atoi(state.Data[captchaDataA])
+					y, _ := strconv.Atoi(state.Data[captchaDataB])
+
+					return locale.T(b.language(state.From), locale.LabelCaptcha, x, y), nil
+				},
+				Handle: func(state ConversationState, input string) (string, error) {
+					x, _ := strconv.Atoi(state.Data[captchaDataA])
+					y, _ := strconv.Atoi(state.Data[captchaDataB])
+
+					if answer, err := strconv.Atoi(input); err == nil && answer == x+y {
+						return EndConversation, b.grantCaptchaTrial(state.From)
+					}
+
+					return b.failCaptcha(state)
+				},
+			},
+		},
+	}
+}
+
+// grantCaptchaTrial grants the trial to the user that has answered the captcha, the membership is checked
+// again, since the user might have left the channel in the meantime.
+func (b MainBot) grantCaptchaTrial(from tg.From) error {
+	joined, err := b.hasJoinedChannel(from)
+	if err != nil {
+		return err
+	}
+
+	if !joined {
+		return b.askToJoinChannel(from)
+	}
+
+	return b.grantTrial(from)
+}
+
+// failCaptcha counts the wrong answer of the user, the user is asked a new question until they run out of
+// attempts and lose the trial.
+func (b MainBot) failCaptcha(state ConversationState) (string, error) {
+	ctx := context.Background()
+	lang := b.language(state.From)
+
+	user, err := b.userSvc.GetUserByExternalID(ctx, strconv.Itoa(state.From.ID))
+	if err != nil {
+		return "", err
+	}
+
+	user, err = b.userSvc.AddCaptchaFailure(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if !user.TrialPending {
+		return EndConversation, b.reply(state.ChatID, locale.T(lang, locale.LabelCaptchaFailed))
+	}
+
+	if err := b.reply(state.ChatID, locale.T(lang, locale.LabelWrongCaptcha)); err != nil {
+		return "", err
+	}
+
+	// the user is asked a new question instead of the one they got wrong.
+	for key, value := range newCaptcha() {
+		state.Data[key] = value
+	}
+
+	return stepCaptchaAnswer, nil
+}
+
+func newCaptcha() map[string]string {
+	return map[string]string{
+		captchaDataA: strconv.Itoa(rand.IntN(9) + 1),
+		captchaDataB: strconv.Itoa(rand.IntN(9) + 1),
+	}
+}
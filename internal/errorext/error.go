@@ -1,6 +1,7 @@
 package errorext
 
 import (
+	"errors"
 	clog "github.com/charmbracelet/log"
 	"net/http"
 	"runtime/debug"
@@ -45,6 +46,13 @@ func NewNotFoundError(err error) error {
 	}
 }
 
+// IsNotFound reports whether err is a not found error.
+func IsNotFound(err error) bool {
+	var extErr *Error
+
+	return errors.As(err, &extErr) && extErr.status == http.StatusNotFound
+}
+
 func NewBadRequestError(err error) error {
 	return &Error{
 		message:    err.Error(),
@@ -5,4 +5,64 @@ var (
 	ErrNoActivePackage           = New("you don't have any active package")
 	ErrUserBanned                = New("user banned")
 	ErrUserOrPasswordIsIncorrect = New("user or password is incorrect")
+	ErrInvalidReferralCode       = New("referral code is invalid")
+	ErrSelfReferral              = New("you can't use your own referral code")
+	ErrReferralDeactivated       = New("referral program is not activated")
+	ErrPackageNotFound           = New("package does not exist")
+	ErrPackageNotActive          = New("package is not active")
+	ErrPackageNotReserved        = New("only unused reserved packages can be transferred")
+	ErrPackageCanceled           = New("package is already canceled")
+	ErrInvalidPackageType        = New("package type is invalid")
+	ErrPackageTrafficRequired    = New("traffic limit is required for this package type")
+	ErrPackageExpiryRequired     = New("expiry is required for this package type")
+	ErrPackageHasNoTrafficLimit  = New("package has unlimited traffic")
+	ErrPackageHasNoExpiry        = New("package never expires")
+	ErrPlanNotFound              = New("plan does not exist")
+	ErrPlanNotActive             = New("plan is not active")
+	ErrInvalidFairUse            = New("fair use cap and throttle rate must be set together")
+	ErrFairUseWithoutExpiry      = New("packages that never expire can't have a fair use cap")
+	ErrNotAdmin                  = New("you are not an admin")
+	ErrBroadcastNotFound         = New("broadcast does not exist")
+	ErrBroadcastTextRequired     = New("broadcast text is required")
+	ErrInvalidBroadcastFilter    = New("broadcast filter is invalid")
+	ErrInvalidParseMode          = New("parse mode must be empty, MarkdownV2 or HTML")
+	ErrBroadcastButtonIncomplete = New("broadcast button text and url must be set together")
+	ErrUnsupportedLanguage       = New("language is not supported")
+	ErrMessageTemplateNotFound   = New("message template does not exist")
+	ErrEmptyMessageTemplate      = New("message template renders an empty message")
+	ErrCredentialRevealNotFound  = New("the password has already been revealed or has expired")
+	ErrPasswordTooShort          = New("password must be at least 8 characters")
+	ErrPasswordTooWeak           = New("password must have at least three of lowercase letters, uppercase letters, digits and symbols")
+	ErrPasswordContainsUsername  = New("password must not contain your username")
+	ErrPasswordHasWhitespace     = New("password must not contain spaces")
+	ErrVPNHostNotSet             = New("connection settings are not configured yet, please ask the administrators")
+	ErrMenuExpired               = New("this menu has expired, please open it again")
+	ErrConnectionNotFound        = New("connection does not exist or has already ended")
+	ErrUserAlreadyExists         = New("you already have a user")
+	ErrUsernameTaken             = New("username is already taken")
+	ErrInvalidUsername           = New("username must be 3 to 32 letters, digits, dots, dashes or underscores")
+	ErrInvalidLinkCode           = New("link code is invalid or has expired")
+	ErrAccountAlreadyLinked      = New("this account is already linked to a user")
+	ErrAccountNotFound           = New("account does not exist")
+	ErrTooManyAccounts           = New("you have reached the maximum number of accounts")
+	ErrTicketNotFound            = New("ticket does not exist")
+	ErrTicketClosed              = New("ticket is already closed")
+	ErrSupportMessageRequired    = New("please describe your problem in a text message")
+	ErrInvalidTicketStatus       = New("ticket status must be empty, open, answered or closed")
+	ErrInvalidPlanPrice          = New("plan price must not be negative")
+	ErrPlanOfAnotherBot          = New("plan belongs to another bot")
+	ErrBotNotFound               = New("bot does not exist")
+	ErrAdminNotFound             = New("admin does not exist")
+	ErrBotAlreadyExists          = New("bot is already registered")
+	ErrInvalidBotToken           = New("bot token is invalid")
+	ErrUserOfAnotherBot          = New("your user belongs to another bot")
+	ErrResellerNotFound          = New("reseller does not exist")
+	ErrInvalidAPIToken           = New("api token is invalid")
+	ErrResellerUserQuotaExceeded = New("you have reached your user quota")
+	ErrResellerTrafficExceeded   = New("you don't have enough traffic left in your quota")
+	ErrUnlimitedResellerPackage  = New("resellers can only create packages with a traffic limit")
+	ErrInvalidResellerQuota      = New("quota must not be negative")
+	ErrInvalidCommissionRate     = New("commission rate must be between 0 and 100")
+	ErrInvalidPayoutAmount       = New("payout amount must be positive and not more than the balance")
+	ErrTrialUnavailable          = New("the trial package is not available for you")
 )
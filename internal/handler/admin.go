@@ -15,6 +15,8 @@ import (
 type AdminService interface {
 	Login(ctx context.Context, req model.AdminLoginRequest) error
 	ChangePassword(ctx context.Context, req model.ChangePasswordRequest) error
+	CreateLinkCode(ctx context.Context, username string) (model.LinkCode, error)
+	UnlinkTelegram(ctx context.Context, username string) error
 }
 
 type AdminHandler struct {
@@ -111,6 +113,34 @@ func (a AdminHandler) ChangePassword(ctx echo.Context) error {
 	return a.Logout(ctx)
 }
 
+// CreateLinkCode returns the code the admin sends to the bot to link their telegram account.
+func (a AdminHandler) CreateLinkCode(ctx echo.Context) error {
+	username, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, a.logger, err)
+	}
+
+	resp, err := a.svc.CreateLinkCode(ctx.Request().Context(), username)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, a.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, toCtrlAdminLinkCode(resp))
+}
+
+func (a AdminHandler) UnlinkTelegram(ctx echo.Context) error {
+	username, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, a.logger, err)
+	}
+
+	if err := a.svc.UnlinkTelegram(ctx.Request().Context(), username); err != nil {
+		return NewFailedHTTPResponse(ctx, a.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
 func (a AdminHandler) SetNoAuthRoutes(router *echo.Group) {
 	router.POST("/login", a.Login)
 }
@@ -119,4 +149,6 @@ func (a AdminHandler) SetRoutes(router *echo.Group) {
 	router.GET("/self", a.Self)
 	router.POST("/logout", a.Logout)
 	router.POST("/change-password", a.ChangePassword)
+	router.POST("/link-telegram", a.CreateLinkCode)
+	router.POST("/unlink-telegram", a.UnlinkTelegram)
 }
@@ -34,3 +34,11 @@ func toModelChangePasswordRequest(req ChangePasswordRequest) model.ChangePasswor
 		NewPassword:     req.NewPassword,
 	}
 }
+
+func toCtrlAdminLinkCode(req model.LinkCode) LinkCode {
+	return LinkCode{
+		Code:     req.Code,
+		Command:  "/linkadmin " + req.Code,
+		ExpireAt: req.ExpireAt,
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type AuditLogService interface {
+	GetAuditLogs(ctx context.Context, req model.GetAuditLogsRequest) (model.GetAuditLogsResponse, error)
+}
+
+type AuditLogHandler struct {
+	svc    AuditLogService
+	logger *clog.Logger
+}
+
+func NewAuditLogHandler(svc AuditLogService, logger *clog.Logger) *AuditLogHandler {
+	return &AuditLogHandler{svc: svc, logger: logger}
+}
+
+func (a AuditLogHandler) GetAuditLogs(ctx echo.Context) error {
+	var req GetAuditLogsRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := a.svc.GetAuditLogs(ctx.Request().Context(), toModelGetAuditLogsRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, a.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, GetAuditLogsResponse{
+		Pagination: toCtrlPagination(resp.Pagination),
+		AuditLogs:  toCtrlAuditLogEntities(resp.AuditLogs),
+	})
+}
+
+func (a AuditLogHandler) SetRoutes(router *echo.Group) {
+	router.GET("/audit-logs", a.GetAuditLogs)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type AuditLogEntity struct {
+	ID         int       `json:"id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	Resource   string    `json:"resource"`
+	ResourceID int       `json:"resource_id"`
+	Details    string    `json:"details"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetAuditLogsRequest struct {
+	Page       int    `query:"page"`
+	PageSize   int    `query:"page_size"`
+	Resource   string `query:"resource"`
+	ResourceID int    `query:"resource_id"`
+}
+
+type GetAuditLogsResponse struct {
+	Pagination
+	AuditLogs []AuditLogEntity `json:"audit_logs"`
+}
+
+func toModelGetAuditLogsRequest(req GetAuditLogsRequest) model.GetAuditLogsRequest {
+	return model.GetAuditLogsRequest{
+		Pagination: model.Pagination{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+		},
+		Resource:   req.Resource,
+		ResourceID: req.ResourceID,
+	}
+}
+
+func toCtrlAuditLogEntity(req model.AuditLogEntity) AuditLogEntity {
+	return AuditLogEntity{
+		ID:         req.ID,
+		Actor:      req.Actor,
+		Action:     req.Action,
+		Resource:   req.Resource,
+		ResourceID: req.ResourceID,
+		Details:    req.Details,
+		CreatedAt:  req.CreatedAt,
+	}
+}
+
+func toCtrlAuditLogEntities(logs []model.AuditLogEntity) []AuditLogEntity {
+	result := make([]AuditLogEntity, 0, len(logs))
+
+	for _, log := range logs {
+		result = append(result, toCtrlAuditLogEntity(log))
+	}
+
+	return result
+}
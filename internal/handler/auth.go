@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"errors"
+	"github.com/alir32a/jupiter/pkg/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+func getAdminUsername(ctx echo.Context) (string, error) {
+	claim, ok := ctx.Get("user").(*jwt.Claim)
+	if !ok {
+		return "", errors.New("user not found")
+	}
+
+	return claim.Username, nil
+}
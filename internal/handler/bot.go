@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type BotService interface {
+	CreateBot(ctx context.Context, req model.CreateBotRequest) (model.BotEntity, error)
+	GetBots(ctx context.Context, req model.GetBotsRequest) (model.GetBotsResponse, error)
+	SetBotActive(ctx context.Context, req model.SetBotActiveRequest) error
+}
+
+type BotHandler struct {
+	svc    BotService
+	logger *clog.Logger
+}
+
+func NewBotHandler(svc BotService, logger *clog.Logger) *BotHandler {
+	return &BotHandler{svc: svc, logger: logger}
+}
+
+func (b BotHandler) GetBots(ctx echo.Context) error {
+	var req GetBotsRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := b.svc.GetBots(ctx.Request().Context(), toModelGetBotsRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, GetBotsResponse{
+		Pagination: toCtrlPagination(resp.Pagination),
+		Bots:       toCtrlBotEntities(resp.Bots),
+	})
+}
+
+func (b BotHandler) CreateBot(ctx echo.Context) error {
+	var req CreateBotRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if req.AdminUsername == "" || req.Token == "" {
+		return NewBindingError(ctx, errors.New("admin username and token are required"))
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	bot, err := b.svc.CreateBot(ctx.Request().Context(), model.CreateBotRequest{
+		AdminUsername: req.AdminUsername,
+		Token:         req.Token,
+		Actor:         actor,
+	})
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, toCtrlBotEntity(bot))
+}
+
+func (b BotHandler) SetBotActive(ctx echo.Context) error {
+	var req SetBotActiveRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	err = b.svc.SetBotActive(ctx.Request().Context(), model.SetBotActiveRequest{
+		ID:     req.ID,
+		Active: req.Active,
+		Actor:  actor,
+	})
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (b BotHandler) SetRoutes(router *echo.Group) {
+	router.GET("/bots", b.GetBots)
+	router.POST("/bots", b.CreateBot)
+	router.POST("/bots/:id/active", b.SetBotActive)
+}
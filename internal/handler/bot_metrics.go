@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/pkg/tg"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type BotMetricsProvider interface {
+	Metrics() tg.OutboxMetrics
+}
+
+type BotMetricsHandler struct {
+	bot BotMetricsProvider
+}
+
+func NewBotMetricsHandler(bot BotMetricsProvider) *BotMetricsHandler {
+	return &BotMetricsHandler{bot: bot}
+}
+
+func (b BotMetricsHandler) GetMetrics(ctx echo.Context) error {
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlBotMetrics(b.bot.Metrics()))
+}
+
+func (b BotMetricsHandler) SetRoutes(router *echo.Group) {
+	router.GET("/bot/metrics", b.GetMetrics)
+}
@@ -0,0 +1,24 @@
+package handler
+
+import "github.com/alir32a/jupiter/pkg/tg"
+
+type BotMetrics struct {
+	Sent        int64 `json:"sent"`
+	Failed      int64 `json:"failed"`
+	Retried     int64 `json:"retried"`
+	RateLimited int64 `json:"rate_limited"`
+	Pending     int64 `json:"pending"`
+	// WaitedSeconds is the total time the messages have waited for the rate limits.
+	WaitedSeconds float64 `json:"waited_seconds"`
+}
+
+func toCtrlBotMetrics(metrics tg.OutboxMetrics) BotMetrics {
+	return BotMetrics{
+		Sent:          metrics.Sent,
+		Failed:        metrics.Failed,
+		Retried:       metrics.Retried,
+		RateLimited:   metrics.RateLimited,
+		Pending:       metrics.Pending,
+		WaitedSeconds: metrics.Waited.Seconds(),
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+// BotEntity is a bot of a reseller, its token and webhook secret are never sent to the panel.
+type BotEntity struct {
+	ID            int       `json:"id"`
+	AdminUsername string    `json:"admin_username"`
+	Username      string    `json:"username"`
+	IsActive      bool      `json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type GetBotsRequest struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+type GetBotsResponse struct {
+	Pagination
+	Bots []BotEntity `json:"bots"`
+}
+
+type CreateBotRequest struct {
+	AdminUsername string `json:"admin_username"`
+	Token         string `json:"token"`
+}
+
+type SetBotActiveRequest struct {
+	ID     int  `param:"id"`
+	Active bool `json:"active"`
+}
+
+func toModelGetBotsRequest(req GetBotsRequest) model.GetBotsRequest {
+	return model.GetBotsRequest{
+		Pagination: model.Pagination{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+		},
+	}
+}
+
+func toCtrlBotEntity(req model.BotEntity) BotEntity {
+	return BotEntity{
+		ID:            req.ID,
+		AdminUsername: req.AdminUsername,
+		Username:      req.Username,
+		IsActive:      req.IsActive,
+		CreatedAt:     req.CreatedAt,
+	}
+}
+
+func toCtrlBotEntities(bots []model.BotEntity) []BotEntity {
+	result := make([]BotEntity, 0, len(bots))
+
+	for _, bot := range bots {
+		result = append(result, toCtrlBotEntity(bot))
+	}
+
+	return result
+}
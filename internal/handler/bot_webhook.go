@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"github.com/alir32a/jupiter/pkg/tg"
+	clog "github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type BotUpdateHandler interface {
+	HandleUpdates(updates []tg.Update) error
+}
+
+// BotsUpdateHandler handles the updates of the bots of the resellers, each of them has its own secret.
+type BotsUpdateHandler interface {
+	WebhookSecret(id int) (string, bool)
+	HandleBotUpdates(id int, updates []tg.Update) error
+}
+
+type BotWebhookHandler struct {
+	bot    BotUpdateHandler
+	bots   BotsUpdateHandler
+	secret string
+	logger *clog.Logger
+}
+
+func NewBotWebhookHandler(bot BotUpdateHandler, bots BotsUpdateHandler, secret string,
+	logger *clog.Logger) *BotWebhookHandler {
+	return &BotWebhookHandler{
+		bot:    bot,
+		bots:   bots,
+		secret: secret,
+		logger: logger,
+	}
+}
+
+func (b BotWebhookHandler) HandleUpdate(ctx echo.Context) error {
+	return b.handle(ctx, b.secret, b.bot.HandleUpdates)
+}
+
+func (b BotWebhookHandler) HandleBotUpdate(ctx echo.Context) error {
+	// the body is the update itself, so only the path is bound.
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	secret, ok := b.bots.WebhookSecret(id)
+	if !ok {
+		return ctx.NoContent(http.StatusNotFound)
+	}
+
+	return b.handle(ctx, secret, func(updates []tg.Update) error {
+		return b.bots.HandleBotUpdates(id, updates)
+	})
+}
+
+func (b BotWebhookHandler) handle(ctx echo.Context, secret string, handleUpdates func([]tg.Update) error) error {
+	token := ctx.Request().Header.Get(tg.SecretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	data, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	update, err := tg.ParseUpdate(data)
+	if err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	// telegram keeps retrying the update until it gets a 2xx response, so failed updates are only logged.
+	if err := handleUpdates([]tg.Update{update}); err != nil {
+		b.logger.Error(err.Error())
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (b BotWebhookHandler) SetRoutes(router *echo.Group) {
+	router.POST("/bot/webhook", b.HandleUpdate)
+	router.POST("/bot/webhook/:id", b.HandleBotUpdate)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type BroadcastService interface {
+	CreateBroadcast(ctx context.Context, req model.CreateBroadcastRequest) (model.BroadcastEntity, error)
+	GetBroadcasts(ctx context.Context, req model.GetBroadcastsRequest) (model.GetBroadcastsResponse, error)
+	GetBroadcastReport(ctx context.Context, id int) (model.GetBroadcastReportResponse, error)
+}
+
+type BroadcastHandler struct {
+	svc    BroadcastService
+	logger *clog.Logger
+}
+
+func NewBroadcastHandler(svc BroadcastService, logger *clog.Logger) *BroadcastHandler {
+	return &BroadcastHandler{svc: svc, logger: logger}
+}
+
+func (b BroadcastHandler) CreateBroadcast(ctx echo.Context) error {
+	var req CreateBroadcastRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if req.Text == "" {
+		return NewBindingError(ctx, errors.New("text is required"))
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	broadcast, err := b.svc.CreateBroadcast(ctx.Request().Context(), toModelCreateBroadcastRequest(req, actor))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, toCtrlBroadcastEntity(broadcast))
+}
+
+func (b BroadcastHandler) GetBroadcasts(ctx echo.Context) error {
+	var req GetBroadcastsRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := b.svc.GetBroadcasts(ctx.Request().Context(), toModelGetBroadcastsRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, GetBroadcastsResponse{
+		Pagination: toCtrlPagination(resp.Pagination),
+		Broadcasts: toCtrlBroadcastEntities(resp.Broadcasts),
+	})
+}
+
+func (b BroadcastHandler) GetBroadcastReport(ctx echo.Context) error {
+	var req GetBroadcastReportRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := b.svc.GetBroadcastReport(ctx.Request().Context(), req.ID)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, b.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlGetBroadcastReportResponse(resp))
+}
+
+func (b BroadcastHandler) SetRoutes(router *echo.Group) {
+	router.GET("/broadcasts", b.GetBroadcasts)
+	router.POST("/broadcasts", b.CreateBroadcast)
+	router.GET("/broadcasts/:id", b.GetBroadcastReport)
+}
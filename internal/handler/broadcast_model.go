@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type BroadcastEntity struct {
+	ID         int        `json:"id"`
+	Text       string     `json:"text"`
+	ParseMode  string     `json:"parse_mode"`
+	ButtonText string     `json:"button_text"`
+	ButtonUrl  string     `json:"button_url"`
+	Filter     string     `json:"filter"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Sent       int        `json:"sent"`
+	Failed     int        `json:"failed"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+type BroadcastDeliveryEntity struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	ChatID    string    `json:"chat_id"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateBroadcastRequest struct {
+	Text       string `json:"text"`
+	ParseMode  string `json:"parse_mode"`
+	ButtonText string `json:"button_text"`
+	ButtonUrl  string `json:"button_url"`
+	Filter     string `json:"filter"`
+}
+
+type GetBroadcastsRequest struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+type GetBroadcastsResponse struct {
+	Pagination
+	Broadcasts []BroadcastEntity `json:"broadcasts"`
+}
+
+type GetBroadcastReportRequest struct {
+	ID int `param:"id"`
+}
+
+type GetBroadcastReportResponse struct {
+	Broadcast        BroadcastEntity           `json:"broadcast"`
+	FailedDeliveries []BroadcastDeliveryEntity `json:"failed_deliveries"`
+}
+
+func toModelCreateBroadcastRequest(req CreateBroadcastRequest, actor string) model.CreateBroadcastRequest {
+	return model.CreateBroadcastRequest{
+		Text:       req.Text,
+		ParseMode:  req.ParseMode,
+		ButtonText: req.ButtonText,
+		ButtonUrl:  req.ButtonUrl,
+		Filter:     req.Filter,
+		CreatedBy:  actor,
+	}
+}
+
+func toModelGetBroadcastsRequest(req GetBroadcastsRequest) model.GetBroadcastsRequest {
+	return model.GetBroadcastsRequest{
+		Pagination: model.Pagination{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+		},
+	}
+}
+
+func toCtrlBroadcastEntity(req model.BroadcastEntity) BroadcastEntity {
+	return BroadcastEntity{
+		ID:         req.ID,
+		Text:       req.Text,
+		ParseMode:  req.ParseMode,
+		ButtonText: req.ButtonText,
+		ButtonUrl:  req.ButtonUrl,
+		Filter:     req.Filter,
+		Status:     req.Status,
+		Total:      req.Total,
+		Sent:       req.Sent,
+		Failed:     req.Failed,
+		CreatedBy:  req.CreatedBy,
+		CreatedAt:  req.CreatedAt,
+		FinishedAt: req.FinishedAt,
+	}
+}
+
+func toCtrlBroadcastEntities(broadcasts []model.BroadcastEntity) []BroadcastEntity {
+	result := make([]BroadcastEntity, 0, len(broadcasts))
+
+	for _, broadcast := range broadcasts {
+		result = append(result, toCtrlBroadcastEntity(broadcast))
+	}
+
+	return result
+}
+
+func toCtrlBroadcastDeliveryEntity(req model.BroadcastDeliveryEntity) BroadcastDeliveryEntity {
+	return BroadcastDeliveryEntity{
+		ID:        req.ID,
+		UserID:    req.UserID,
+		ChatID:    req.ChatID,
+		Attempts:  req.Attempts,
+		Error:     req.Error,
+		UpdatedAt: req.UpdatedAt,
+	}
+}
+
+func toCtrlGetBroadcastReportResponse(req model.GetBroadcastReportResponse) GetBroadcastReportResponse {
+	deliveries := make([]BroadcastDeliveryEntity, 0, len(req.FailedDeliveries))
+
+	for _, delivery := range req.FailedDeliveries {
+		deliveries = append(deliveries, toCtrlBroadcastDeliveryEntity(delivery))
+	}
+
+	return GetBroadcastReportResponse{
+		Broadcast:        toCtrlBroadcastEntity(req.Broadcast),
+		FailedDeliveries: deliveries,
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strings"
+)
+
+type MessageTemplateService interface {
+	GetMessageTemplates(ctx context.Context) ([]model.MessageTemplate, error)
+	SaveMessageTemplate(ctx context.Context, req model.SaveMessageTemplateRequest) error
+	ResetMessageTemplate(ctx context.Context, req model.ResetMessageTemplateRequest) error
+	PreviewMessageTemplate(ctx context.Context, req model.PreviewMessageTemplateRequest) (model.RenderedMessage, error)
+}
+
+type MessageTemplateHandler struct {
+	svc    MessageTemplateService
+	logger *clog.Logger
+}
+
+func NewMessageTemplateHandler(svc MessageTemplateService, logger *clog.Logger) *MessageTemplateHandler {
+	return &MessageTemplateHandler{svc: svc, logger: logger}
+}
+
+func (m MessageTemplateHandler) GetMessageTemplates(ctx echo.Context) error {
+	templates, err := m.svc.GetMessageTemplates(ctx.Request().Context())
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, m.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, GetMessageTemplatesResponse{
+		Templates: toCtrlMessageTemplates(templates),
+	})
+}
+
+func (m MessageTemplateHandler) SaveMessageTemplate(ctx echo.Context) error {
+	var req SaveMessageTemplateRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if strings.TrimSpace(req.Body) == "" {
+		return NewBindingError(ctx, errors.New("body is required"))
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, m.logger, err)
+	}
+
+	err = m.svc.SaveMessageTemplate(ctx.Request().Context(), toModelSaveMessageTemplateRequest(req, actor))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, m.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (m MessageTemplateHandler) ResetMessageTemplate(ctx echo.Context) error {
+	var req ResetMessageTemplateRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, m.logger, err)
+	}
+
+	err = m.svc.ResetMessageTemplate(ctx.Request().Context(), model.ResetMessageTemplateRequest{
+		Key:      req.Key,
+		Language: req.Language,
+		Actor:    actor,
+	})
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, m.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (m MessageTemplateHandler) PreviewMessageTemplate(ctx echo.Context) error {
+	var req PreviewMessageTemplateRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := m.svc.PreviewMessageTemplate(ctx.Request().Context(), toModelPreviewMessageTemplateRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, m.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, PreviewMessageTemplateResponse{
+		Text:      resp.Text,
+		ParseMode: resp.ParseMode,
+	})
+}
+
+func (m MessageTemplateHandler) SetRoutes(router *echo.Group) {
+	router.GET("/message-templates", m.GetMessageTemplates)
+	router.POST("/message-templates/:key/:language", m.SaveMessageTemplate)
+	router.POST("/message-templates/:key/:language/reset", m.ResetMessageTemplate)
+	router.POST("/message-templates/:key/:language/preview", m.PreviewMessageTemplate)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type MessageTemplate struct {
+	Key              string     `json:"key"`
+	Language         string     `json:"language"`
+	ParseMode        string     `json:"parse_mode"`
+	Body             string     `json:"body"`
+	DefaultParseMode string     `json:"default_parse_mode"`
+	DefaultBody      string     `json:"default_body"`
+	Customized       bool       `json:"customized"`
+	Variables        []string   `json:"variables"`
+	UpdatedBy        string     `json:"updated_by"`
+	UpdatedAt        *time.Time `json:"updated_at"`
+}
+
+type GetMessageTemplatesResponse struct {
+	Templates []MessageTemplate `json:"templates"`
+}
+
+type SaveMessageTemplateRequest struct {
+	Key       string `param:"key"`
+	Language  string `param:"language"`
+	ParseMode string `json:"parse_mode"`
+	Body      string `json:"body"`
+}
+
+type ResetMessageTemplateRequest struct {
+	Key      string `param:"key"`
+	Language string `param:"language"`
+}
+
+type PreviewMessageTemplateRequest struct {
+	Key       string `param:"key"`
+	Language  string `param:"language"`
+	ParseMode string `json:"parse_mode"`
+	Body      string `json:"body"`
+}
+
+type PreviewMessageTemplateResponse struct {
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+func toModelSaveMessageTemplateRequest(req SaveMessageTemplateRequest, actor string) model.SaveMessageTemplateRequest {
+	return model.SaveMessageTemplateRequest{
+		Key:       req.Key,
+		Language:  req.Language,
+		ParseMode: req.ParseMode,
+		Body:      req.Body,
+		Actor:     actor,
+	}
+}
+
+func toModelPreviewMessageTemplateRequest(req PreviewMessageTemplateRequest) model.PreviewMessageTemplateRequest {
+	return model.PreviewMessageTemplateRequest{
+		Key:       req.Key,
+		Language:  req.Language,
+		ParseMode: req.ParseMode,
+		Body:      req.Body,
+	}
+}
+
+func toCtrlMessageTemplate(req model.MessageTemplate) MessageTemplate {
+	return MessageTemplate{
+		Key:              req.Key,
+		Language:         req.Language,
+		ParseMode:        req.ParseMode,
+		Body:             req.Body,
+		DefaultParseMode: req.DefaultParseMode,
+		DefaultBody:      req.DefaultBody,
+		Customized:       req.Customized,
+		Variables:        req.Variables,
+		UpdatedBy:        req.UpdatedBy,
+		UpdatedAt:        req.UpdatedAt,
+	}
+}
+
+func toCtrlMessageTemplates(templates []model.MessageTemplate) []MessageTemplate {
+	result := make([]MessageTemplate, 0, len(templates))
+
+	for _, tmpl := range templates {
+		result = append(result, toCtrlMessageTemplate(tmpl))
+	}
+
+	return result
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"github.com/alir32a/jupiter/internal/model"
 	clog "github.com/charmbracelet/log"
 	"github.com/labstack/echo/v4"
@@ -12,6 +13,10 @@ type PackageService interface {
 	GetPackages(ctx context.Context, req model.GetPackagesRequest) (model.GetPackagesResponse, error)
 	CreatePackage(ctx context.Context, req model.CreatePackageRequest) error
 	GetUserActiveAndReservedPackages(ctx context.Context, userID int) (model.GetUserPackages, error)
+	TopUpPackage(ctx context.Context, req model.TopUpPackageRequest) error
+	ExtendPackageExpiry(ctx context.Context, req model.ExtendPackageExpiryRequest) error
+	TransferPackage(ctx context.Context, req model.TransferPackageRequest) error
+	CancelPackage(ctx context.Context, req model.CancelPackageRequest) error
 }
 
 type PackageHandler struct {
@@ -48,7 +53,12 @@ func (p PackageHandler) CreatePackage(ctx echo.Context) error {
 		return NewBindingError(ctx, err)
 	}
 
-	err := p.svc.CreatePackage(ctx.Request().Context(), toModelCreatePackageRequest(req))
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	err = p.svc.CreatePackage(ctx.Request().Context(), toModelCreatePackageRequest(req, actor))
 	if err != nil {
 		return NewFailedHTTPResponse(ctx, p.logger, err)
 	}
@@ -71,8 +81,108 @@ func (p PackageHandler) GetUserActiveAndReservedPackages(ctx echo.Context) error
 	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlGetUserActiveAndReservedPackagesResponse(resp))
 }
 
+func (p PackageHandler) TopUpPackage(ctx echo.Context) error {
+	var req TopUpPackageRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if req.Traffic <= 0 {
+		return NewBindingError(ctx, errors.New("traffic must be positive"))
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	err = p.svc.TopUpPackage(ctx.Request().Context(), toModelTopUpPackageRequest(req, actor))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (p PackageHandler) ExtendPackageExpiry(ctx echo.Context) error {
+	var req ExtendPackageExpiryRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if req.Days <= 0 {
+		return NewBindingError(ctx, errors.New("days must be positive"))
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	err = p.svc.ExtendPackageExpiry(ctx.Request().Context(), toModelExtendPackageExpiryRequest(req, actor))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (p PackageHandler) TransferPackage(ctx echo.Context) error {
+	var req TransferPackageRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if req.Username == "" {
+		return NewBindingError(ctx, errors.New("username is required"))
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	err = p.svc.TransferPackage(ctx.Request().Context(), toModelTransferPackageRequest(req, actor))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (p PackageHandler) CancelPackage(ctx echo.Context) error {
+	var req CancelPackageRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if req.Refund < 0 {
+		return NewBindingError(ctx, errors.New("refund can't be negative"))
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	err = p.svc.CancelPackage(ctx.Request().Context(), toModelCancelPackageRequest(req, actor))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
 func (p PackageHandler) SetRoutes(router *echo.Group) {
 	router.GET("/packages", p.GetPackages)
 	router.POST("/packages", p.CreatePackage)
 	router.GET("/active-packages", p.GetUserActiveAndReservedPackages)
+	router.POST("/packages/:id/top-up", p.TopUpPackage)
+	router.POST("/packages/:id/extend", p.ExtendPackageExpiry)
+	router.POST("/packages/:id/transfer", p.TransferPackage)
+	router.POST("/packages/:id/cancel", p.CancelPackage)
 }
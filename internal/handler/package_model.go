@@ -9,14 +9,20 @@ import (
 type PackageEntity struct {
 	ID                   int        `json:"id"`
 	UserID               int        `json:"user_id"`
+	PlanID               *int       `json:"plan_id"`
 	Username             string     `json:"username"`
+	PackageType          string     `json:"package_type"`
 	TrafficLimit         string     `json:"traffic_limit"`
 	DownloadTrafficUsage string     `json:"download_traffic_usage"`
 	UploadTrafficUsage   string     `json:"upload_traffic_usage"`
 	MaxConnections       int        `json:"max_connections"`
 	IsTrial              bool       `json:"is_trial"`
 	ExpirationInDays     int        `json:"expiration_in_days"`
+	FairUseCap           string     `json:"fair_use_cap"`
+	ThrottleRate         string     `json:"throttle_rate"`
+	ActivatedAt          *time.Time `json:"activated_at"`
 	ExpireAt             *time.Time `json:"expire_at"`
+	CanceledAt           *time.Time `json:"canceled_at"`
 	CreatedAt            time.Time  `json:"created_at"`
 }
 
@@ -33,6 +39,8 @@ type GetPackagesResponse struct {
 
 type CreatePackageRequest struct {
 	Username       string `json:"username"`
+	PlanID         int    `json:"plan_id"`
+	PackageType    string `json:"package_type"`
 	TrafficLimit   int    `json:"traffic_limit"`
 	MaxConnections int    `json:"max_connections"`
 	Expiry         int    `json:"expiry"`
@@ -42,8 +50,29 @@ type GetUserActiveAndReservedPackagesRequest struct {
 	UserID int `query:"user_id"`
 }
 
+type TopUpPackageRequest struct {
+	ID      int `param:"id"`
+	Traffic int `json:"traffic"`
+}
+
+type ExtendPackageExpiryRequest struct {
+	ID   int `param:"id"`
+	Days int `json:"days"`
+}
+
+type TransferPackageRequest struct {
+	ID       int    `param:"id"`
+	Username string `json:"username"`
+}
+
+type CancelPackageRequest struct {
+	ID     int `param:"id"`
+	Refund int `json:"refund"`
+}
+
 type PackageSummary struct {
 	Status       string     `json:"status"`
+	PackageType  string     `json:"package_type"`
 	TrafficLimit string     `json:"traffic_limit"`
 	UsedTraffic  string     `json:"used_traffic"`
 	ExpiresAt    *time.Time `json:"expires_at"`
@@ -63,14 +92,20 @@ func toCtrlPackageEntity(req model.PackageEntity) PackageEntity {
 	return PackageEntity{
 		ID:                   req.ID,
 		UserID:               req.UserID,
+		PlanID:               req.PlanID,
 		Username:             req.Username,
-		TrafficLimit:         util.ToHumanReadableBytes(req.TrafficLimit),
+		PackageType:          req.PackageType,
+		TrafficLimit:         toCtrlTrafficLimit(req),
 		DownloadTrafficUsage: util.ToHumanReadableBytes(req.DownloadTrafficUsage),
 		UploadTrafficUsage:   util.ToHumanReadableBytes(req.UploadTrafficUsage),
 		MaxConnections:       req.MaxConnections,
 		IsTrial:              req.IsTrial,
 		ExpirationInDays:     req.ExpirationInDays,
+		FairUseCap:           util.ToHumanReadableBytes(req.FairUseCap),
+		ThrottleRate:         util.ToHumanReadableBytes(req.ThrottleRate) + "/s",
+		ActivatedAt:          req.ActivatedAt,
 		ExpireAt:             req.ExpireAt,
+		CanceledAt:           req.CanceledAt,
 		CreatedAt:            req.CreatedAt,
 	}
 }
@@ -85,19 +120,63 @@ func toCtrlPackageEntities(packages []model.PackageEntity) []PackageEntity {
 	return result
 }
 
-func toModelCreatePackageRequest(req CreatePackageRequest) model.CreatePackageRequest {
+func toModelCreatePackageRequest(req CreatePackageRequest, actor string) model.CreatePackageRequest {
 	return model.CreatePackageRequest{
 		Username:         req.Username,
+		PlanID:           req.PlanID,
+		PackageType:      req.PackageType,
 		Traffic:          req.TrafficLimit,
 		MaxConnections:   req.MaxConnections,
 		ExpirationInDays: req.Expiry,
+		Actor:            actor,
+	}
+}
+
+func toModelTopUpPackageRequest(req TopUpPackageRequest, actor string) model.TopUpPackageRequest {
+	return model.TopUpPackageRequest{
+		ID:      req.ID,
+		Traffic: req.Traffic,
+		Actor:   actor,
 	}
 }
 
+func toModelExtendPackageExpiryRequest(req ExtendPackageExpiryRequest, actor string) model.ExtendPackageExpiryRequest {
+	return model.ExtendPackageExpiryRequest{
+		ID:    req.ID,
+		Days:  req.Days,
+		Actor: actor,
+	}
+}
+
+func toModelTransferPackageRequest(req TransferPackageRequest, actor string) model.TransferPackageRequest {
+	return model.TransferPackageRequest{
+		ID:       req.ID,
+		Username: req.Username,
+		Actor:    actor,
+	}
+}
+
+func toModelCancelPackageRequest(req CancelPackageRequest, actor string) model.CancelPackageRequest {
+	return model.CancelPackageRequest{
+		ID:     req.ID,
+		Refund: req.Refund,
+		Actor:  actor,
+	}
+}
+
+func toCtrlTrafficLimit(pack model.PackageEntity) string {
+	if pack.HasUnlimitedTraffic() {
+		return "Unlimited"
+	}
+
+	return util.ToHumanReadableBytes(pack.TrafficLimit)
+}
+
 func toPackageSummary(status string, pack model.PackageEntity) PackageSummary {
 	return PackageSummary{
 		Status:       status,
-		TrafficLimit: util.ToHumanReadableBytes(pack.TrafficLimit),
+		PackageType:  pack.PackageType,
+		TrafficLimit: toCtrlTrafficLimit(pack),
 		UsedTraffic:  util.ToHumanReadableBytes(pack.DownloadTrafficUsage + pack.UploadTrafficUsage),
 		ExpiresAt:    pack.ExpireAt,
 	}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type PlanService interface {
+	CreatePlan(ctx context.Context, req model.CreatePlanRequest) error
+	GetPlans(ctx context.Context, req model.GetPlansRequest) (model.GetPlansResponse, error)
+}
+
+type PlanHandler struct {
+	svc    PlanService
+	logger *clog.Logger
+}
+
+func NewPlanHandler(svc PlanService, logger *clog.Logger) *PlanHandler {
+	return &PlanHandler{svc: svc, logger: logger}
+}
+
+func (p PlanHandler) GetPlans(ctx echo.Context) error {
+	var req GetPlansRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := p.svc.GetPlans(ctx.Request().Context(), toModelGetPlansRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, GetPlansResponse{
+		Pagination: toCtrlPagination(resp.Pagination),
+		Plans:      toCtrlPlanEntities(resp.Plans),
+	})
+}
+
+func (p PlanHandler) CreatePlan(ctx echo.Context) error {
+	var req CreatePlanRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if req.Name == "" {
+		return NewBindingError(ctx, errors.New("name is required"))
+	}
+
+	err := p.svc.CreatePlan(ctx.Request().Context(), toModelCreatePlanRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, p.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, nil)
+}
+
+func (p PlanHandler) SetRoutes(router *echo.Group) {
+	router.GET("/plans", p.GetPlans)
+	router.POST("/plans", p.CreatePlan)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/util"
+	"time"
+)
+
+type PlanEntity struct {
+	ID               int       `json:"id"`
+	BotID            int       `json:"bot_id"`
+	Name             string    `json:"name"`
+	PackageType      string    `json:"package_type"`
+	TrafficLimit     string    `json:"traffic_limit"`
+	MaxConnections   int       `json:"max_connections"`
+	ExpirationInDays int       `json:"expiration_in_days"`
+	FairUseCap       string    `json:"fair_use_cap"`
+	ThrottleRate     string    `json:"throttle_rate"`
+	Price            int       `json:"price"`
+	IsActive         bool      `json:"is_active"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type CreatePlanRequest struct {
+	BotID          int    `json:"bot_id"`
+	Name           string `json:"name"`
+	PackageType    string `json:"package_type"`
+	TrafficLimit   int    `json:"traffic_limit"`
+	MaxConnections int    `json:"max_connections"`
+	Expiry         int    `json:"expiry"`
+	FairUseCap     int    `json:"fair_use_cap"`
+	ThrottleRate   int    `json:"throttle_rate"`
+	Price          int    `json:"price"`
+}
+
+type GetPlansRequest struct {
+	Page       int  `query:"page"`
+	PageSize   int  `query:"page_size"`
+	BotID      *int `query:"bot_id"`
+	ActiveOnly bool `query:"active_only"`
+}
+
+type GetPlansResponse struct {
+	Pagination
+	Plans []PlanEntity `json:"plans"`
+}
+
+func toModelCreatePlanRequest(req CreatePlanRequest) model.CreatePlanRequest {
+	return model.CreatePlanRequest{
+		BotID:            req.BotID,
+		Name:             req.Name,
+		PackageType:      req.PackageType,
+		TrafficLimit:     req.TrafficLimit,
+		MaxConnections:   req.MaxConnections,
+		ExpirationInDays: req.Expiry,
+		FairUseCap:       req.FairUseCap,
+		ThrottleRate:     req.ThrottleRate,
+		Price:            req.Price,
+	}
+}
+
+func toModelGetPlansRequest(req GetPlansRequest) model.GetPlansRequest {
+	return model.GetPlansRequest{
+		Pagination: model.Pagination{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+		},
+		BotID:      req.BotID,
+		ActiveOnly: req.ActiveOnly,
+	}
+}
+
+func toCtrlPlanEntity(req model.PlanEntity) PlanEntity {
+	result := PlanEntity{
+		ID:               req.ID,
+		BotID:            req.BotID,
+		Name:             req.Name,
+		PackageType:      req.PackageType,
+		TrafficLimit:     util.ToHumanReadableBytes(req.TrafficLimit),
+		MaxConnections:   req.MaxConnections,
+		ExpirationInDays: req.ExpirationInDays,
+		FairUseCap:       util.ToHumanReadableBytes(req.FairUseCap),
+		ThrottleRate:     util.ToHumanReadableBytes(req.ThrottleRate) + "/s",
+		Price:            req.Price,
+		IsActive:         req.IsActive,
+		CreatedAt:        req.CreatedAt,
+	}
+
+	if req.PackageType == model.PackageTypeUnlimited {
+		result.TrafficLimit = "Unlimited"
+	}
+
+	return result
+}
+
+func toCtrlPlanEntities(plans []model.PlanEntity) []PlanEntity {
+	result := make([]PlanEntity, 0, len(plans))
+
+	for _, plan := range plans {
+		result = append(result, toCtrlPlanEntity(plan))
+	}
+
+	return result
+}
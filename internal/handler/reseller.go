@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// apiTokenHeader is the header the resellers send their api token in.
+const apiTokenHeader = "X-API-Token"
+
+type ResellerService interface {
+	CreateReseller(ctx context.Context, req model.CreateResellerRequest) (model.AdminEntity, error)
+	GetResellers(ctx context.Context, req model.GetResellersRequest) (model.GetResellersResponse, error)
+	AddQuota(ctx context.Context, req model.AddResellerQuotaRequest) error
+	RotateAPIToken(ctx context.Context, id int, actor string) (string, error)
+	Authenticate(ctx context.Context, token string) (model.AdminEntity, error)
+	CreateUser(ctx context.Context, resellerID int, req model.CreatePanelUserRequest) (model.CreateUserResponse, error)
+	GetUsers(ctx context.Context, resellerID int, req model.GetAllUsersRequest) (model.GetAllUsersResponse, error)
+	CreatePackage(ctx context.Context, resellerID int, req model.CreatePackageRequest) error
+	GetCommissions(ctx context.Context, req model.GetResellerCommissionsRequest) (model.GetResellerCommissionsResponse, error)
+	CreatePayout(ctx context.Context, req model.CreatePayoutRequest) error
+}
+
+type ResellerHandler struct {
+	svc    ResellerService
+	logger *clog.Logger
+}
+
+func NewResellerHandler(svc ResellerService, logger *clog.Logger) *ResellerHandler {
+	return &ResellerHandler{svc: svc, logger: logger}
+}
+
+func (r ResellerHandler) GetResellers(ctx echo.Context) error {
+	var req GetResellersRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := r.svc.GetResellers(ctx.Request().Context(), toModelGetResellersRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, GetResellersResponse{
+		Pagination: toCtrlPagination(resp.Pagination),
+		Resellers:  toCtrlResellerEntities(resp.Resellers),
+	})
+}
+
+func (r ResellerHandler) CreateReseller(ctx echo.Context) error {
+	var req CreateResellerRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	reseller, err := r.svc.CreateReseller(ctx.Request().Context(), model.CreateResellerRequest{
+		Username:       req.Username,
+		Password:       req.Password,
+		UserQuota:      req.UserQuota,
+		TrafficQuota:   req.TrafficQuota,
+		CommissionRate: req.CommissionRate,
+		Actor:          actor,
+	})
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, toCtrlResellerEntity(reseller))
+}
+
+func (r ResellerHandler) AddQuota(ctx echo.Context) error {
+	var req AddResellerQuotaRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	err = r.svc.AddQuota(ctx.Request().Context(), model.AddResellerQuotaRequest{
+		ID:      req.ID,
+		Users:   req.Users,
+		Traffic: req.Traffic,
+		Actor:   actor,
+	})
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (r ResellerHandler) RotateAPIToken(ctx echo.Context) error {
+	var req RotateAPITokenRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	token, err := r.svc.RotateAPIToken(ctx.Request().Context(), req.ID, actor)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, RotateAPITokenResponse{Token: token})
+}
+
+func (r ResellerHandler) GetCommissions(ctx echo.Context) error {
+	var req GetResellerCommissionsRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := r.svc.GetCommissions(ctx.Request().Context(), toModelGetResellerCommissionsRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlGetResellerCommissionsResponse(resp))
+}
+
+func (r ResellerHandler) CreatePayout(ctx echo.Context) error {
+	var req CreatePayoutRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	err = r.svc.CreatePayout(ctx.Request().Context(), model.CreatePayoutRequest{
+		ResellerID: req.ID,
+		Amount:     req.Amount,
+		Note:       req.Note,
+		Actor:      actor,
+	})
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, nil)
+}
+
+// Authenticate lets the requests with the api token of an active reseller through, the reseller is kept in
+// the context for the handlers.
+func (r ResellerHandler) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		reseller, err := r.svc.Authenticate(ctx.Request().Context(), ctx.Request().Header.Get(apiTokenHeader))
+		if err != nil {
+			return ctx.JSON(http.StatusUnauthorized, "invalid token")
+		}
+
+		ctx.Set("reseller", reseller)
+
+		return next(ctx)
+	}
+}
+
+func (r ResellerHandler) GetSelf(ctx echo.Context) error {
+	reseller, err := getReseller(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlResellerEntity(reseller))
+}
+
+func (r ResellerHandler) GetUsers(ctx echo.Context) error {
+	var req GetAllUsersRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	reseller, err := getReseller(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	resp, err := r.svc.GetUsers(ctx.Request().Context(), reseller.ID, toModelGetAllUsersRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlGetAllUsersResponse(resp))
+}
+
+func (r ResellerHandler) CreateUser(ctx echo.Context) error {
+	var req CreateResellerUserRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	reseller, err := getReseller(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	resp, err := r.svc.CreateUser(ctx.Request().Context(), reseller.ID, model.CreatePanelUserRequest{
+		Username: req.Username,
+		BotID:    req.BotID,
+	})
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, toCtrlCreateUserResponse(resp))
+}
+
+func (r ResellerHandler) CreatePackage(ctx echo.Context) error {
+	var req CreatePackageRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	reseller, err := getReseller(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	err = r.svc.CreatePackage(ctx.Request().Context(), reseller.ID, toModelCreatePackageRequest(req, reseller.Username))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, nil)
+}
+
+func (r ResellerHandler) GetOwnCommissions(ctx echo.Context) error {
+	var req GetResellerCommissionsRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	reseller, err := getReseller(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	req.ID = reseller.ID
+
+	resp, err := r.svc.GetCommissions(ctx.Request().Context(), toModelGetResellerCommissionsRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, r.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlGetResellerCommissionsResponse(resp))
+}
+
+func (r ResellerHandler) SetRoutes(router *echo.Group) {
+	router.GET("/resellers", r.GetResellers)
+	router.POST("/resellers", r.CreateReseller)
+	router.POST("/resellers/:id/quota", r.AddQuota)
+	router.POST("/resellers/:id/token", r.RotateAPIToken)
+	router.GET("/resellers/:id/commissions", r.GetCommissions)
+	router.POST("/resellers/:id/payouts", r.CreatePayout)
+}
+
+// SetAPIRoutes sets the routes the resellers call with their api token.
+func (r ResellerHandler) SetAPIRoutes(router *echo.Group) {
+	router.Use(r.Authenticate)
+
+	router.GET("/self", r.GetSelf)
+	router.GET("/users", r.GetUsers)
+	router.POST("/users", r.CreateUser)
+	router.POST("/packages", r.CreatePackage)
+	router.GET("/commissions", r.GetOwnCommissions)
+}
+
+func getReseller(ctx echo.Context) (model.AdminEntity, error) {
+	reseller, ok := ctx.Get("reseller").(model.AdminEntity)
+	if !ok {
+		return model.AdminEntity{}, errors.New("reseller not found")
+	}
+
+	return reseller, nil
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+// ResellerEntity is a reseller along with their quota, traffic is in bytes.
+type ResellerEntity struct {
+	ID             int       `json:"id"`
+	Username       string    `json:"username"`
+	UserQuota      int       `json:"user_quota"`
+	UsedUsers      int       `json:"used_users"`
+	TrafficQuota   int       `json:"traffic_quota"`
+	UsedTraffic    int       `json:"used_traffic"`
+	CommissionRate int       `json:"commission_rate"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type GetResellersRequest struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+type GetResellersResponse struct {
+	Pagination
+	Resellers []ResellerEntity `json:"resellers"`
+}
+
+// CreateResellerRequest creates a reseller, traffic quota is in GB and the commission rate in percents.
+type CreateResellerRequest struct {
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+	UserQuota      int    `json:"user_quota"`
+	TrafficQuota   int    `json:"traffic_quota"`
+	CommissionRate int    `json:"commission_rate"`
+}
+
+type AddResellerQuotaRequest struct {
+	ID      int `param:"id"`
+	Users   int `json:"users"`
+	Traffic int `json:"traffic"`
+}
+
+type RotateAPITokenRequest struct {
+	ID int `param:"id"`
+}
+
+type RotateAPITokenResponse struct {
+	Token string `json:"token"`
+}
+
+type ResellerCommissionEntity struct {
+	ID        int       `json:"id"`
+	UserID    *int      `json:"user_id"`
+	PlanID    *int      `json:"plan_id"`
+	Amount    int       `json:"amount"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetResellerCommissionsRequest struct {
+	ID       int `param:"id"`
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+type GetResellerCommissionsResponse struct {
+	Pagination
+	Commissions []ResellerCommissionEntity `json:"commissions"`
+	Balance     int                        `json:"balance"`
+}
+
+type CreatePayoutRequest struct {
+	ID     int    `param:"id"`
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
+}
+
+// CreateResellerUserRequest creates a user for the reseller, the bot must be the main bot or one of theirs.
+type CreateResellerUserRequest struct {
+	Username string `json:"username"`
+	BotID    int    `json:"bot_id"`
+}
+
+func toModelGetResellersRequest(req GetResellersRequest) model.GetResellersRequest {
+	return model.GetResellersRequest{
+		Pagination: model.Pagination{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+		},
+	}
+}
+
+func toCtrlResellerEntity(req model.AdminEntity) ResellerEntity {
+	return ResellerEntity{
+		ID:             req.ID,
+		Username:       req.Username,
+		UserQuota:      req.UserQuota,
+		UsedUsers:      req.UsedUsers,
+		TrafficQuota:   req.TrafficQuota,
+		UsedTraffic:    req.UsedTraffic,
+		CommissionRate: req.CommissionRate,
+		IsActive:       req.IsActive,
+		CreatedAt:      req.CreatedAt,
+	}
+}
+
+func toCtrlResellerEntities(resellers []model.AdminEntity) []ResellerEntity {
+	result := make([]ResellerEntity, 0, len(resellers))
+
+	for _, reseller := range resellers {
+		result = append(result, toCtrlResellerEntity(reseller))
+	}
+
+	return result
+}
+
+func toModelGetResellerCommissionsRequest(req GetResellerCommissionsRequest) model.GetResellerCommissionsRequest {
+	return model.GetResellerCommissionsRequest{
+		ResellerID: req.ID,
+		Pagination: model.Pagination{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+		},
+	}
+}
+
+func toCtrlResellerCommissionEntity(req model.ResellerCommissionEntity) ResellerCommissionEntity {
+	return ResellerCommissionEntity{
+		ID:        req.ID,
+		UserID:    req.UserID,
+		PlanID:    req.PlanID,
+		Amount:    req.Amount,
+		Note:      req.Note,
+		CreatedAt: req.CreatedAt,
+	}
+}
+
+func toCtrlResellerCommissionEntities(commissions []model.ResellerCommissionEntity) []ResellerCommissionEntity {
+	result := make([]ResellerCommissionEntity, 0, len(commissions))
+
+	for _, commission := range commissions {
+		result = append(result, toCtrlResellerCommissionEntity(commission))
+	}
+
+	return result
+}
+
+func toCtrlGetResellerCommissionsResponse(req model.GetResellerCommissionsResponse) GetResellerCommissionsResponse {
+	return GetResellerCommissionsResponse{
+		Pagination:  toCtrlPagination(req.Pagination),
+		Commissions: toCtrlResellerCommissionEntities(req.Commissions),
+		Balance:     req.Balance,
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/config"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type SettingHandler struct {
+	cfg *config.Config
+}
+
+func NewSettingHandler(cfg *config.Config) *SettingHandler {
+	return &SettingHandler{cfg: cfg}
+}
+
+func (s SettingHandler) GetSettings(ctx echo.Context) error {
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlSettingsResponse(s.cfg))
+}
+
+func (s SettingHandler) SetRoutes(router *echo.Group) {
+	router.GET("/settings", s.GetSettings)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/model"
+)
+
+type SettingsResponse struct {
+	Package      PackageSettings      `json:"package"`
+	TrialPackage TrialPackageSettings `json:"trial_package"`
+	Referral     ReferralSettings     `json:"referral"`
+}
+
+type PackageSettings struct {
+	ConsumptionOrder  string   `json:"consumption_order"`
+	ConsumptionOrders []string `json:"consumption_orders"`
+}
+
+type TrialPackageSettings struct {
+	Activated        bool    `json:"activated"`
+	TrafficLimit     float64 `json:"traffic_limit"`
+	MaxConnections   int     `json:"max_connections"`
+	ExpirationInDays int     `json:"expiration_in_days"`
+	RequiredChannel  string  `json:"required_channel"`
+	Captcha          bool    `json:"captcha"`
+	CaptchaAttempts  int     `json:"captcha_attempts"`
+}
+
+type ReferralSettings struct {
+	Activated bool `json:"activated"`
+}
+
+func toCtrlSettingsResponse(cfg *config.Config) SettingsResponse {
+	return SettingsResponse{
+		Package: PackageSettings{
+			ConsumptionOrder:  cfg.Package.ConsumptionOrder,
+			ConsumptionOrders: model.PackageConsumptionOrders,
+		},
+		TrialPackage: TrialPackageSettings{
+			Activated:        cfg.TrialPackage.Activated,
+			TrafficLimit:     cfg.TrialPackage.TrafficLimit,
+			MaxConnections:   cfg.TrialPackage.MaxConnections,
+			ExpirationInDays: cfg.TrialPackage.ExpirationInDays,
+			RequiredChannel:  cfg.TrialPackage.RequiredChannel,
+			Captcha:          cfg.TrialPackage.Captcha,
+			CaptchaAttempts:  cfg.TrialPackage.CaptchaAttempts,
+		},
+		Referral: ReferralSettings{
+			Activated: cfg.Referral.Activated,
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type SupportService interface {
+	GetTickets(ctx context.Context, req model.GetSupportTicketsRequest) (model.GetSupportTicketsResponse, error)
+	GetTicket(ctx context.Context, id int) (model.GetSupportTicketResponse, error)
+	Reply(ctx context.Context, req model.ReplySupportTicketRequest) error
+	CloseTicket(ctx context.Context, id int) error
+}
+
+type SupportHandler struct {
+	svc    SupportService
+	logger *clog.Logger
+}
+
+func NewSupportHandler(svc SupportService, logger *clog.Logger) *SupportHandler {
+	return &SupportHandler{svc: svc, logger: logger}
+}
+
+func (s SupportHandler) GetTickets(ctx echo.Context) error {
+	var req GetSupportTicketsRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := s.svc.GetTickets(ctx.Request().Context(), toModelGetSupportTicketsRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, s.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, GetSupportTicketsResponse{
+		Pagination: toCtrlPagination(resp.Pagination),
+		Tickets:    toCtrlSupportTicketEntities(resp.Tickets),
+	})
+}
+
+func (s SupportHandler) GetTicket(ctx echo.Context) error {
+	var req GetSupportTicketRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := s.svc.GetTicket(ctx.Request().Context(), req.ID)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, s.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlGetSupportTicketResponse(resp))
+}
+
+func (s SupportHandler) ReplyTicket(ctx echo.Context) error {
+	var req ReplySupportTicketRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if req.Text == "" {
+		return NewBindingError(ctx, errors.New("text is required"))
+	}
+
+	actor, err := getAdminUsername(ctx)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, s.logger, err)
+	}
+
+	err = s.svc.Reply(ctx.Request().Context(), model.ReplySupportTicketRequest{
+		TicketID:      req.ID,
+		AdminUsername: actor,
+		Text:          req.Text,
+	})
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, s.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (s SupportHandler) CloseTicket(ctx echo.Context) error {
+	var req CloseSupportTicketRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	if err := s.svc.CloseTicket(ctx.Request().Context(), req.ID); err != nil {
+		return NewFailedHTTPResponse(ctx, s.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
+}
+
+func (s SupportHandler) SetRoutes(router *echo.Group) {
+	router.GET("/tickets", s.GetTickets)
+	router.GET("/tickets/:id", s.GetTicket)
+	router.POST("/tickets/:id/reply", s.ReplyTicket)
+	router.POST("/tickets/:id/close", s.CloseTicket)
+}
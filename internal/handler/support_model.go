@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type SupportTicketEntity struct {
+	ID        int        `json:"id"`
+	UserID    *int       `json:"user_id"`
+	Username  string     `json:"username"`
+	BotID     int        `json:"bot_id"`
+	ChatID    string     `json:"chat_id"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
+type SupportMessageEntity struct {
+	ID            int       `json:"id"`
+	Sender        string    `json:"sender"`
+	AdminUsername string    `json:"admin_username"`
+	Text          string    `json:"text"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type GetSupportTicketsRequest struct {
+	Status   string `query:"status"`
+	Page     int    `query:"page"`
+	PageSize int    `query:"page_size"`
+}
+
+type GetSupportTicketsResponse struct {
+	Pagination
+	Tickets []SupportTicketEntity `json:"tickets"`
+}
+
+type GetSupportTicketRequest struct {
+	ID int `param:"id"`
+}
+
+type GetSupportTicketResponse struct {
+	Ticket   SupportTicketEntity    `json:"ticket"`
+	Messages []SupportMessageEntity `json:"messages"`
+}
+
+type ReplySupportTicketRequest struct {
+	ID   int    `param:"id"`
+	Text string `json:"text"`
+}
+
+type CloseSupportTicketRequest struct {
+	ID int `param:"id"`
+}
+
+func toModelGetSupportTicketsRequest(req GetSupportTicketsRequest) model.GetSupportTicketsRequest {
+	return model.GetSupportTicketsRequest{
+		Status: req.Status,
+		Pagination: model.Pagination{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+		},
+	}
+}
+
+func toCtrlSupportTicketEntity(req model.SupportTicketEntity) SupportTicketEntity {
+	return SupportTicketEntity{
+		ID:        req.ID,
+		UserID:    req.UserID,
+		Username:  req.Username,
+		BotID:     req.BotID,
+		ChatID:    req.ChatID,
+		Status:    req.Status,
+		CreatedAt: req.CreatedAt,
+		UpdatedAt: req.UpdatedAt,
+		ClosedAt:  req.ClosedAt,
+	}
+}
+
+func toCtrlSupportTicketEntities(tickets []model.SupportTicketEntity) []SupportTicketEntity {
+	result := make([]SupportTicketEntity, 0, len(tickets))
+
+	for _, ticket := range tickets {
+		result = append(result, toCtrlSupportTicketEntity(ticket))
+	}
+
+	return result
+}
+
+func toCtrlSupportMessageEntity(req model.SupportMessageEntity) SupportMessageEntity {
+	return SupportMessageEntity{
+		ID:            req.ID,
+		Sender:        req.Sender,
+		AdminUsername: req.AdminUsername,
+		Text:          req.Text,
+		CreatedAt:     req.CreatedAt,
+	}
+}
+
+func toCtrlGetSupportTicketResponse(req model.GetSupportTicketResponse) GetSupportTicketResponse {
+	messages := make([]SupportMessageEntity, 0, len(req.Messages))
+
+	for _, message := range req.Messages {
+		messages = append(messages, toCtrlSupportMessageEntity(message))
+	}
+
+	return GetSupportTicketResponse{
+		Ticket:   toCtrlSupportTicketEntity(req.Ticket),
+		Messages: messages,
+	}
+}
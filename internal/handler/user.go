@@ -12,6 +12,9 @@ type UserService interface {
 	GetAllUsers(ctx context.Context, req model.GetAllUsersRequest) (model.GetAllUsersResponse, error)
 	BanUser(ctx context.Context, userID int) error
 	UnbanUser(ctx context.Context, userID int) error
+	CreatePanelUser(ctx context.Context, req model.CreatePanelUserRequest) (model.CreateUserResponse, error)
+	CreateLinkCode(ctx context.Context, userID int) (model.LinkCode, error)
+	GetUserIdentities(ctx context.Context, userID int) ([]model.UserIdentityEntity, error)
 }
 
 type UserHandler struct {
@@ -68,8 +71,56 @@ func (u UserHandler) UnBanUser(ctx echo.Context) error {
 	return NewSuccessHTTPResponse(ctx, http.StatusOK, nil)
 }
 
+func (u UserHandler) CreateUser(ctx echo.Context) error {
+	var req CreateUserRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := u.svc.CreatePanelUser(ctx.Request().Context(), toModelCreatePanelUserRequest(req))
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, u.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, toCtrlCreateUserResponse(resp))
+}
+
+func (u UserHandler) CreateLinkCode(ctx echo.Context) error {
+	var req CreateLinkCodeRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := u.svc.CreateLinkCode(ctx.Request().Context(), req.ID)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, u.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusCreated, toCtrlLinkCode(resp))
+}
+
+func (u UserHandler) GetUserIdentities(ctx echo.Context) error {
+	var req GetUserIdentitiesRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		return NewBindingError(ctx, err)
+	}
+
+	resp, err := u.svc.GetUserIdentities(ctx.Request().Context(), req.ID)
+	if err != nil {
+		return NewFailedHTTPResponse(ctx, u.logger, err)
+	}
+
+	return NewSuccessHTTPResponse(ctx, http.StatusOK, toCtrlUserIdentityEntities(resp))
+}
+
 func (u UserHandler) SetRoutes(router *echo.Group) {
 	router.GET("/users", u.GetAllUsers)
+	router.POST("/users", u.CreateUser)
 	router.POST("/users/:id/ban", u.BanUser)
 	router.POST("/users/:id/unban", u.UnBanUser)
+	router.POST("/users/:id/link-code", u.CreateLinkCode)
+	router.GET("/users/:id/identities", u.GetUserIdentities)
 }
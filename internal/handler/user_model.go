@@ -6,20 +6,30 @@ import (
 )
 
 type UserEntity struct {
-	ID           int        `json:"id"`
-	Username     string     `json:"username"`
-	ExternalID   string     `json:"external_id"`
-	UserType     string     `json:"user_type"`
-	ReferralCode string     `json:"referral_code"`
-	Referral     *string    `json:"referral"`
-	BannedAt     *time.Time `json:"banned_at"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID                   int        `json:"id"`
+	Username             string     `json:"username"`
+	ExternalID           string     `json:"external_id"`
+	UserType             string     `json:"user_type"`
+	ReferralCode         string     `json:"referral_code"`
+	Referral             *string    `json:"referral"`
+	WalletBalance        int        `json:"wallet_balance"`
+	NotificationsEnabled bool       `json:"notifications_enabled"`
+	Language             string     `json:"language"`
+	OwnerID              *int       `json:"owner_id"`
+	BotID                int        `json:"bot_id"`
+	ResellerID           *int       `json:"reseller_id"`
+	ThrottledAt          *time.Time `json:"throttled_at"`
+	BannedAt             *time.Time `json:"banned_at"`
+	CreatedAt            time.Time  `json:"created_at"`
 }
 
 type GetAllUsersRequest struct {
 	Page     int    `query:"page"`
 	PageSize int    `query:"page_size"`
 	Username string `query:"username"`
+	BotID    *int   `query:"bot_id"`
+	// ResellerID limits the users to the customers of the reseller.
+	ResellerID *int `query:"reseller_id"`
 }
 
 type GetAllUsersResponse struct {
@@ -35,26 +45,66 @@ type UnBanUserRequest struct {
 	ID int `param:"id"`
 }
 
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	BotID    int    `json:"bot_id"`
+}
+
+type CreateUserResponse struct {
+	User     UserEntity `json:"user"`
+	Password string     `json:"password"`
+}
+
+type CreateLinkCodeRequest struct {
+	ID int `param:"id"`
+}
+
+type LinkCode struct {
+	Code     string    `json:"code"`
+	Command  string    `json:"command"`
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+type GetUserIdentitiesRequest struct {
+	ID int `param:"id"`
+}
+
+type UserIdentityEntity struct {
+	ID         int       `json:"id"`
+	Provider   string    `json:"provider"`
+	ExternalID string    `json:"external_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 func toModelGetAllUsersRequest(req GetAllUsersRequest) model.GetAllUsersRequest {
 	return model.GetAllUsersRequest{
 		Pagination: model.Pagination{
 			CurrentPage: req.Page,
 			PageSize:    req.PageSize,
 		},
-		Username: req.Username,
+		Username:   req.Username,
+		BotID:      req.BotID,
+		ResellerID: req.ResellerID,
 	}
 }
 
 func toCtrlUserEntity(req model.UserEntity) UserEntity {
 	return UserEntity{
-		ID:           req.ID,
-		Username:     req.Username,
-		ExternalID:   req.ExternalID,
-		UserType:     req.UserType,
-		ReferralCode: req.ReferralCode,
-		Referral:     req.Referral,
-		BannedAt:     req.BannedAt,
-		CreatedAt:    req.CreatedAt,
+		ID:                   req.ID,
+		Username:             req.Username,
+		ExternalID:           req.ExternalID,
+		UserType:             req.UserType,
+		ReferralCode:         req.ReferralCode,
+		Referral:             req.Referral,
+		WalletBalance:        req.WalletBalance,
+		NotificationsEnabled: req.NotificationsEnabled,
+		Language:             req.Language,
+		OwnerID:              req.OwnerID,
+		BotID:                req.BotID,
+		ResellerID:           req.ResellerID,
+		ThrottledAt:          req.ThrottledAt,
+		BannedAt:             req.BannedAt,
+		CreatedAt:            req.CreatedAt,
 	}
 }
 
@@ -74,3 +124,40 @@ func toCtrlGetAllUsersResponse(req model.GetAllUsersResponse) GetAllUsersRespons
 		Pagination: toCtrlPagination(req.Pagination),
 	}
 }
+
+func toModelCreatePanelUserRequest(req CreateUserRequest) model.CreatePanelUserRequest {
+	return model.CreatePanelUserRequest{
+		Username: req.Username,
+		BotID:    req.BotID,
+	}
+}
+
+func toCtrlCreateUserResponse(req model.CreateUserResponse) CreateUserResponse {
+	return CreateUserResponse{
+		User:     toCtrlUserEntity(req.UserEntity),
+		Password: req.Password,
+	}
+}
+
+func toCtrlLinkCode(req model.LinkCode) LinkCode {
+	return LinkCode{
+		Code:     req.Code,
+		Command:  "/link " + req.Code,
+		ExpireAt: req.ExpireAt,
+	}
+}
+
+func toCtrlUserIdentityEntities(req []model.UserIdentityEntity) []UserIdentityEntity {
+	result := make([]UserIdentityEntity, 0, len(req))
+
+	for _, identity := range req {
+		result = append(result, UserIdentityEntity{
+			ID:         identity.ID,
+			Provider:   identity.Provider,
+			ExternalID: identity.ExternalID,
+			CreatedAt:  identity.CreatedAt,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,194 @@
+package locale
+
+var englishTemplates = map[string]string{
+	MsgHelp: `hey there 👋
+this is jupiter bot, you can manage your openconnect vpn account here.
+currently, you can't buy packages from this bot (yet).
+you can create user (you can create only one user per telegram account),
+see your active package (remaining traffic, expire time etc.),
+change your account password and see active connections
+let's f**ck sansoorchi.✊
+
+<b>supported commands:</b>
+- /start: show this message
+- /status: show your active package status
+- /create: create a user and show credentials, and activate a trial package if trial is activated by administrators
+- /link CODE: link this telegram account to the user the administrators have created for you
+- /password: change your account password
+- /setpassword: choose your own account password
+- /connect: show how to connect to the server
+- /connections: show active connections
+- /accounts: switch between your accounts or create another one
+- /plans: show the plans and their prices
+- /referrals: show your referral link and rewards
+- /notifications on|off: turn usage, expiry and account notifications on or off
+- /language: change the language of the bot
+- /support: ask the administrators for help
+- /cancel: cancel the current operation`,
+	MsgUnknownCommand: "huh? use /start if you don't know how to use me, or /support if you need help",
+	MsgCredentials: `your account is ready, tap the button below to see your password, it can be seen only once
+and this message will be deleted in {{.ExpiresIn}}.
+you can change your password anytime using /password, or choose your own using /setpassword
+
+<b>Username:</b> <code>{{.Username}}</code>`,
+	MsgNewPassword: `your password has been changed, tap the button below to see your new password, it can be seen
+only once and this message will be deleted in {{.ExpiresIn}}
+
+<b>Username:</b> <code>{{.Username}}</code>`,
+	MsgNoActivePackage: "oops, you don't have any active package",
+	MsgStatus: `<b>Account:</b> {{.Username}}
+
+<b>Active Package</b>
+Traffic Limit: {{.TrafficLimit}}
+Download Traffic Usage: {{.DownloadUsage}}
+Upload Traffic Usage: {{.UploadUsage}}
+Total Traffic Usage: {{.TotalUsage}}
+Max Connections: {{.MaxConnections}}
+Expire At: {{.ExpireAt}}
+{{- if .FairUse}}
+Fair Use: throttled to {{.ThrottleRate}}/s after {{.FairUseCap}}
+{{- end}}
+{{- if .ReservedPackages}}
+
+<b>Reserved Packages</b>
+{{- range .ReservedPackages}}
+
+# {{.Index}}
+Traffic Limit: {{.TrafficLimit}}
+Max Connections: {{.MaxConnections}}
+Expiration: {{.Expiration}}
+{{- end}}
+{{- end}}`,
+	MsgNoActiveConnections: "there is no active connections",
+	MsgConnections: `{{range .Connections}}<b># {{.Index}}</b>
+IP: {{.IP}}
+Location: {{.Location}}
+UserAgent: {{.UserAgent}}
+Device: {{.Device}}
+Download Traffic Usage: {{.DownloadUsage}}
+Upload Traffic Usage: {{.UploadUsage}}
+Connected At: {{.ConnectedAt}}
+
+{{end}}`,
+	MsgReferrals: `<b>Referral Code:</b> <code>{{.ReferralCode}}</code>
+<b>Referral Link:</b> {{.ReferralLink}}
+Invited Users: {{.InvitedUsers}}
+Rewarded Purchases: {{.RewardedUsers}}
+Earned Traffic: {{.EarnedTraffic}}
+Earned Days: {{.EarnedDays}}
+Earned Credit: {{.EarnedCredit}}
+Wallet Balance: {{.WalletBalance}}`,
+	MsgNotificationsUsage: "use /notifications on or /notifications off",
+	MsgNotificationsOn:    "notifications turned on",
+	MsgNotificationsOff:   "notifications turned off",
+	MsgChooseLanguage:     "choose your language",
+	MsgLanguageChanged:    "language changed to English",
+	MsgUsageThreshold:     "you have used {{.Percent}}% of your package traffic ({{.Usage}} of {{.Limit}})",
+	MsgTrafficRunOut:      "your package traffic has run out, you'll be disconnected unless you have a reserved package",
+	MsgFairUseCapReached:  "you have reached the fair use cap of your package, your speed is limited to {{.ThrottleRate}}/s",
+	MsgPackageActivated: `your reserved package has been activated
+Traffic Limit: {{.TrafficLimit}}
+Max Connections: {{.MaxConnections}}`,
+	MsgPackageExpires:  "your package expires at {{.ExpireAt}}",
+	MsgBanned:          "your account has been banned by the administrators",
+	MsgUnbanned:        "your account has been unbanned, welcome back",
+	MsgPasswordChanged: "your password has been changed, use it the next time you connect",
+	MsgSession: `<b>Connection</b>
+IP: {{.IP}}
+Location: {{.Location}}
+UserAgent: {{.UserAgent}}
+Device: {{.Device}}
+Download Traffic Usage: {{.DownloadUsage}}
+Upload Traffic Usage: {{.UploadUsage}}
+Connected At: {{.ConnectedAt}}`,
+	MsgPackages: `<b>Packages</b> ({{.Page}}/{{.TotalPages}})
+{{- range .Packages}}
+
+<b># {{.Index}}</b> {{.Status}}
+Traffic Limit: {{.TrafficLimit}}
+Total Traffic Usage: {{.TotalUsage}}
+Max Connections: {{.MaxConnections}}
+Expiration: {{.Expiration}}
+{{- end}}`,
+	MsgLogoutEverywhere: "all of your devices will be disconnected and your password will be changed, " +
+		"you'll get the new password here. do you want to continue?",
+	MsgAccounts: `<b>Accounts</b>
+{{- range .Accounts}}
+{{.Index}}. {{.Username}}{{if .Active}} (active){{end}}
+{{- end}}
+
+/status, /connections, /connect and /password are about the active account, tap an account to switch to it`,
+	MsgTicketReceived: "we've got your message, your ticket number is <b>#{{.ID}}</b>. " +
+		"you'll get the answer right here, use /support again if you want to add anything",
+	MsgSupportReply: `<b>Reply to ticket #{{.ID}}</b>
+
+{{.Text}}
+
+use /support if you want to answer`,
+	MsgTicketClosed: "ticket <b>#{{.ID}}</b> has been closed, use /support if you need help again",
+	MsgPlans: `<b>Plans</b>
+{{- range .Plans}}
+
+<b>{{.Name}}</b>
+Traffic Limit: {{.TrafficLimit}}
+Max Connections: {{.MaxConnections}}
+Expiration: {{.Expiration}}
+Price: {{.Price}}
+{{- end}}
+
+use /support to buy a plan`,
+	MsgNoPlans:       "there are no plans available right now",
+	MsgJoinChannel:   "to get your trial package, join {{.Channel}} first and then send /create again",
+	MsgAccountLinked: "this telegram account is now linked to <b>{{.Username}}</b>, use /status to see your package",
+	MsgConnect: `<b>Server:</b> <code>{{.Address}}</code>
+<b>Username:</b> <code>{{.Username}}</code>
+
+<b>AnyConnect / Cisco Secure Client (Android, iOS):</b> scan this QR code, or add a connection with the server address above
+
+<b>OpenConnect (Linux, macOS):</b>
+<pre>{{.Command}}</pre>
+
+desktop clients can import the profile below, use /password or /setpassword if you don't have your password`,
+}
+
+var englishLabels = map[string]string{
+	LabelUnlimited:            "Unlimited",
+	LabelNever:                "Never",
+	LabelDays:                 "%d Days",
+	LabelMinutes:              "%d Minutes",
+	LabelCancel:               "Cancel",
+	LabelConversationTimedOut: "this conversation has timed out, please start over",
+	LabelNothingToCancel:      "there is nothing to cancel",
+	LabelCanceled:             "canceled",
+	LabelRevealPassword:       "🔑 Reveal password",
+	LabelRevealedPassword:     "Username: %s\nPassword: %s\n\nthis password won't be shown again",
+	LabelEnterPassword: "send the password you want, it must be at least %d characters and have at least three of " +
+		"lowercase letters, uppercase letters, digits and symbols, your message will be deleted right away",
+	LabelProfileCaption:       "AnyConnect profile, import it in your client",
+	LabelMenuConnections:      "🔌 Connections",
+	LabelMenuPackages:         "📦 Packages",
+	LabelMenuRefresh:          "🔄 Refresh",
+	LabelMenuBack:             "« Back",
+	LabelMenuPrevious:         "‹ Previous",
+	LabelMenuNext:             "Next ›",
+	LabelMenuDisconnect:       "⛔ Disconnect",
+	LabelMenuSession:          "#%d %s (%s)",
+	LabelDisconnected:         "the device has been disconnected",
+	LabelPackageActive:        "Active",
+	LabelPackageReserved:      "Reserved",
+	LabelMenuLogoutEverywhere: "🚪 Log out everywhere",
+	LabelMenuConfirm:          "✅ Yes, continue",
+	LabelLoggedOutEverywhere:  "all of your devices have been disconnected",
+	LabelLinkUsage:            "send the code you got from the administrators like this: /link CODE",
+	LabelMenuAccounts:         "👥 Accounts",
+	LabelMenuNewAccount:       "➕ New account",
+	LabelAccountActive:        "✅ %s",
+	LabelSwitchedAccount:      "switched to %s",
+	LabelAccountCreated:       "%s has been created",
+	LabelEnterSupportMessage:  "tell us what the problem is, the administrators will answer you here",
+	LabelJoinChannel:          "📢 Join the channel",
+	LabelCaptcha:              "to get your trial package, answer this question: what is %d + %d?",
+	LabelWrongCaptcha:         "that's not the right answer, let's try another one",
+	LabelCaptchaFailed:        "that's not the right answer either, the trial package is not available for you anymore",
+	LabelTrialGranted:         "your trial package is active, use /status to see it",
+}
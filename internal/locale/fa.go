@@ -0,0 +1,224 @@
+package locale
+
+import "github.com/alir32a/jupiter/internal/errorext"
+
+var persianTemplates = map[string]string{
+	MsgHelp: `سلام 👋
+این ربات ژوپیتر است، اینجا می‌توانید حساب vpn اوپن‌کانکت خود را مدیریت کنید.
+فعلا امکان خرید بسته از این ربات وجود ندارد.
+می‌توانید کاربر بسازید (برای هر حساب تلگرام فقط یک کاربر)،
+بسته فعال خود را ببینید (ترافیک باقی‌مانده، زمان انقضا و ...)،
+رمز عبور حساب خود را تغییر دهید و اتصال‌های فعال را ببینید.
+
+<b>دستورات:</b>
+- /start: نمایش همین پیام
+- /status: نمایش وضعیت بسته فعال
+- /create: ساخت کاربر و نمایش اطلاعات ورود، و فعال‌سازی بسته آزمایشی در صورت فعال بودن
+- /link CODE: اتصال این حساب تلگرام به کاربری که مدیران برایتان ساخته‌اند
+- /password: تغییر رمز عبور حساب
+- /setpassword: انتخاب رمز عبور دلخواه
+- /connect: نمایش روش اتصال به سرور
+- /connections: نمایش اتصال‌های فعال
+- /accounts: جابه‌جایی بین حساب‌ها یا ساخت حساب جدید
+- /plans: نمایش پلن‌ها و قیمت آن‌ها
+- /referrals: نمایش لینک دعوت و پاداش‌ها
+- /notifications on|off: روشن یا خاموش کردن اعلان‌های مصرف، انقضا و حساب
+- /language: تغییر زبان ربات
+- /support: درخواست کمک از مدیران
+- /cancel: لغو عملیات فعلی`,
+	MsgUnknownCommand: "متوجه نشدم! اگر نمی‌دانید چطور از ربات استفاده کنید /start و اگر به کمک نیاز دارید /support را بزنید",
+	MsgCredentials: `حساب شما آماده است، برای دیدن رمز عبور دکمه زیر را بزنید، رمز عبور فقط یک بار نمایش داده می‌شود
+و این پیام تا {{.ExpiresIn}} دیگر حذف می‌شود.
+هر زمان می‌توانید رمز عبور را با /password تغییر دهید یا با /setpassword رمز دلخواه خود را انتخاب کنید
+
+<b>نام کاربری:</b> <code>{{.Username}}</code>`,
+	MsgNewPassword: `رمز عبور شما تغییر کرد، برای دیدن رمز عبور جدید دکمه زیر را بزنید، رمز عبور فقط یک بار
+نمایش داده می‌شود و این پیام تا {{.ExpiresIn}} دیگر حذف می‌شود
+
+<b>نام کاربری:</b> <code>{{.Username}}</code>`,
+	MsgNoActivePackage: "شما هیچ بسته فعالی ندارید",
+	MsgStatus: `<b>حساب:</b> {{.Username}}
+
+<b>بسته فعال</b>
+حجم ترافیک: {{.TrafficLimit}}
+مصرف دانلود: {{.DownloadUsage}}
+مصرف آپلود: {{.UploadUsage}}
+مصرف کل: {{.TotalUsage}}
+حداکثر اتصال همزمان: {{.MaxConnections}}
+تاریخ انقضا: {{.ExpireAt}}
+{{- if .FairUse}}
+مصرف منصفانه: پس از {{.FairUseCap}} سرعت به {{.ThrottleRate}}/s محدود می‌شود
+{{- end}}
+{{- if .ReservedPackages}}
+
+<b>بسته‌های رزرو</b>
+{{- range .ReservedPackages}}
+
+# {{.Index}}
+حجم ترافیک: {{.TrafficLimit}}
+حداکثر اتصال همزمان: {{.MaxConnections}}
+مدت اعتبار: {{.Expiration}}
+{{- end}}
+{{- end}}`,
+	MsgNoActiveConnections: "هیچ اتصال فعالی وجود ندارد",
+	MsgConnections: `{{range .Connections}}<b># {{.Index}}</b>
+آی‌پی: {{.IP}}
+موقعیت: {{.Location}}
+کلاینت: {{.UserAgent}}
+دستگاه: {{.Device}}
+مصرف دانلود: {{.DownloadUsage}}
+مصرف آپلود: {{.UploadUsage}}
+زمان اتصال: {{.ConnectedAt}}
+
+{{end}}`,
+	MsgReferrals: `<b>کد دعوت:</b> <code>{{.ReferralCode}}</code>
+<b>لینک دعوت:</b> {{.ReferralLink}}
+کاربران دعوت‌شده: {{.InvitedUsers}}
+خریدهای دارای پاداش: {{.RewardedUsers}}
+ترافیک دریافتی: {{.EarnedTraffic}}
+روزهای دریافتی: {{.EarnedDays}}
+اعتبار دریافتی: {{.EarnedCredit}}
+موجودی کیف پول: {{.WalletBalance}}`,
+	MsgNotificationsUsage: "از /notifications on یا /notifications off استفاده کنید",
+	MsgNotificationsOn:    "اعلان‌ها روشن شد",
+	MsgNotificationsOff:   "اعلان‌ها خاموش شد",
+	MsgChooseLanguage:     "زبان خود را انتخاب کنید",
+	MsgLanguageChanged:    "زبان به فارسی تغییر کرد",
+	MsgUsageThreshold:     "شما {{.Percent}}% از ترافیک بسته خود را مصرف کرده‌اید ({{.Usage}} از {{.Limit}})",
+	MsgTrafficRunOut:      "ترافیک بسته شما تمام شد، اگر بسته رزروی نداشته باشید اتصال شما قطع می‌شود",
+	MsgFairUseCapReached:  "شما به سقف مصرف منصفانه بسته خود رسیده‌اید، سرعت شما به {{.ThrottleRate}}/s محدود شد",
+	MsgPackageActivated: `بسته رزرو شما فعال شد
+حجم ترافیک: {{.TrafficLimit}}
+حداکثر اتصال همزمان: {{.MaxConnections}}`,
+	MsgPackageExpires:  "بسته شما در {{.ExpireAt}} منقضی می‌شود",
+	MsgBanned:          "حساب شما توسط مدیران مسدود شد",
+	MsgUnbanned:        "مسدودیت حساب شما برداشته شد، خوش برگشتید",
+	MsgPasswordChanged: "رمز عبور شما تغییر کرد، از اتصال بعدی از آن استفاده کنید",
+	MsgSession: `<b>اتصال</b>
+آی‌پی: {{.IP}}
+موقعیت: {{.Location}}
+برنامه: {{.UserAgent}}
+دستگاه: {{.Device}}
+مصرف دانلود: {{.DownloadUsage}}
+مصرف آپلود: {{.UploadUsage}}
+زمان اتصال: {{.ConnectedAt}}`,
+	MsgPackages: `<b>بسته‌ها</b> ({{.Page}}/{{.TotalPages}})
+{{- range .Packages}}
+
+<b># {{.Index}}</b> {{.Status}}
+حجم ترافیک: {{.TrafficLimit}}
+مصرف کل: {{.TotalUsage}}
+حداکثر اتصال همزمان: {{.MaxConnections}}
+انقضا: {{.Expiration}}
+{{- end}}`,
+	MsgLogoutEverywhere: "اتصال همه دستگاه‌های شما قطع و رمز عبورتان عوض می‌شود، " +
+		"رمز عبور جدید همین‌جا برایتان ارسال می‌شود. ادامه می‌دهید؟",
+	MsgAccounts: `<b>حساب‌ها</b>
+{{- range .Accounts}}
+{{.Index}}. {{.Username}}{{if .Active}} (فعال){{end}}
+{{- end}}
+
+/status، /connections، /connect و /password مربوط به حساب فعال هستند، برای تغییر حساب روی آن بزنید`,
+	MsgTicketReceived: "پیام شما دریافت شد، شماره تیکت شما <b>#{{.ID}}</b> است. " +
+		"پاسخ همین‌جا برایتان ارسال می‌شود، اگر چیزی می‌خواهید اضافه کنید دوباره /support را بزنید",
+	MsgSupportReply: `<b>پاسخ تیکت #{{.ID}}</b>
+
+{{.Text}}
+
+برای پاسخ دادن از /support استفاده کنید`,
+	MsgTicketClosed: "تیکت <b>#{{.ID}}</b> بسته شد، اگر دوباره به کمک نیاز داشتید /support را بزنید",
+	MsgPlans: `<b>پلن‌ها</b>
+{{- range .Plans}}
+
+<b>{{.Name}}</b>
+حجم ترافیک: {{.TrafficLimit}}
+حداکثر اتصال همزمان: {{.MaxConnections}}
+انقضا: {{.Expiration}}
+قیمت: {{.Price}}
+{{- end}}
+
+برای خرید پلن از /support استفاده کنید`,
+	MsgNoPlans:       "در حال حاضر پلنی موجود نیست",
+	MsgJoinChannel:   "برای دریافت بسته آزمایشی، ابتدا عضو {{.Channel}} شوید و سپس دوباره /create را بفرستید",
+	MsgAccountLinked: "این حساب تلگرام به <b>{{.Username}}</b> متصل شد، برای دیدن بسته خود از /status استفاده کنید",
+	MsgConnect: `<b>سرور:</b> <code>{{.Address}}</code>
+<b>نام کاربری:</b> <code>{{.Username}}</code>
+
+<b>AnyConnect / Cisco Secure Client (اندروید، iOS):</b> این کد QR را اسکن کنید یا یک اتصال با آدرس سرور بالا بسازید
+
+<b>OpenConnect (لینوکس، مک):</b>
+<pre>{{.Command}}</pre>
+
+برنامه‌های دسکتاپ می‌توانند پروفایل زیر را وارد کنند، اگر رمز عبور خود را ندارید از /password یا /setpassword استفاده کنید`,
+}
+
+var persianLabels = map[string]string{
+	LabelUnlimited:            "نامحدود",
+	LabelNever:                "بدون انقضا",
+	LabelDays:                 "%d روز",
+	LabelMinutes:              "%d دقیقه",
+	LabelCancel:               "لغو",
+	LabelConversationTimedOut: "زمان این گفتگو به پایان رسید، لطفا دوباره شروع کنید",
+	LabelNothingToCancel:      "عملیاتی برای لغو وجود ندارد",
+	LabelCanceled:             "لغو شد",
+	LabelRevealPassword:       "🔑 نمایش رمز عبور",
+	LabelRevealedPassword:     "نام کاربری: %s\nرمز عبور: %s\n\nاین رمز عبور دوباره نمایش داده نمی‌شود",
+	LabelEnterPassword: "رمز عبور دلخواه خود را بفرستید، رمز عبور باید حداقل %d کاراکتر باشد و حداقل سه مورد از " +
+		"حروف کوچک، حروف بزرگ، اعداد و نمادها را داشته باشد، پیام شما بلافاصله حذف می‌شود",
+	LabelProfileCaption:       "پروفایل AnyConnect، آن را در برنامه خود وارد کنید",
+	LabelMenuConnections:      "🔌 اتصال‌ها",
+	LabelMenuPackages:         "📦 بسته‌ها",
+	LabelMenuRefresh:          "🔄 به‌روزرسانی",
+	LabelMenuBack:             "« بازگشت",
+	LabelMenuPrevious:         "‹ قبلی",
+	LabelMenuNext:             "بعدی ›",
+	LabelMenuDisconnect:       "⛔ قطع اتصال",
+	LabelMenuSession:          "#%d %s (%s)",
+	LabelDisconnected:         "اتصال دستگاه قطع شد",
+	LabelPackageActive:        "فعال",
+	LabelPackageReserved:      "رزرو",
+	LabelMenuLogoutEverywhere: "🚪 خروج از همه دستگاه‌ها",
+	LabelMenuConfirm:          "✅ بله، ادامه",
+	LabelLoggedOutEverywhere:  "اتصال همه دستگاه‌های شما قطع شد",
+	LabelLinkUsage:            "کدی که از مدیران گرفته‌اید را این‌طور بفرستید: /link CODE",
+	LabelMenuAccounts:         "👥 حساب‌ها",
+	LabelMenuNewAccount:       "➕ حساب جدید",
+	LabelAccountActive:        "✅ %s",
+	LabelSwitchedAccount:      "به %s تغییر کرد",
+	LabelAccountCreated:       "%s ساخته شد",
+	LabelEnterSupportMessage:  "مشکل خود را برای ما بنویسید، مدیران همین‌جا به شما پاسخ می‌دهند",
+	LabelJoinChannel:          "📢 عضویت در کانال",
+	LabelCaptcha:              "برای دریافت بسته آزمایشی به این سوال پاسخ دهید: %d + %d چند می‌شود؟",
+	LabelWrongCaptcha:         "پاسخ درست نیست، یک سوال دیگر امتحان کنید",
+	LabelCaptchaFailed:        "این پاسخ هم درست نیست، بسته آزمایشی دیگر برای شما در دسترس نیست",
+	LabelTrialGranted:         "بسته آزمایشی شما فعال شد، برای دیدن آن از /status استفاده کنید",
+}
+
+var persianErrors = map[string]string{
+	errorext.ErrUserNotFound.Error():             "کاربر وجود ندارد",
+	errorext.ErrNoActivePackage.Error():          "شما هیچ بسته فعالی ندارید",
+	errorext.ErrUserBanned.Error():               "حساب کاربری مسدود شده است",
+	errorext.ErrInvalidReferralCode.Error():      "کد دعوت نامعتبر است",
+	errorext.ErrSelfReferral.Error():             "نمی‌توانید از کد دعوت خودتان استفاده کنید",
+	errorext.ErrReferralDeactivated.Error():      "برنامه دعوت فعال نیست",
+	errorext.ErrUnsupportedLanguage.Error():      "این زبان پشتیبانی نمی‌شود",
+	errorext.ErrCredentialRevealNotFound.Error(): "رمز عبور قبلا نمایش داده شده یا منقضی شده است",
+	errorext.ErrPasswordTooShort.Error():         "رمز عبور باید حداقل ۸ کاراکتر باشد",
+	errorext.ErrPasswordTooWeak.Error():          "رمز عبور باید حداقل سه مورد از حروف کوچک، حروف بزرگ، اعداد و نمادها را داشته باشد",
+	errorext.ErrPasswordContainsUsername.Error(): "رمز عبور نباید شامل نام کاربری شما باشد",
+	errorext.ErrPasswordHasWhitespace.Error():    "رمز عبور نباید فاصله داشته باشد",
+	errorext.ErrVPNHostNotSet.Error():            "تنظیمات اتصال هنوز انجام نشده است، لطفا با مدیران تماس بگیرید",
+	errorext.ErrMenuExpired.Error():              "این منو منقضی شده است، لطفا دوباره آن را باز کنید",
+	errorext.ErrConnectionNotFound.Error():       "این اتصال وجود ندارد یا قبلا قطع شده است",
+	errorext.ErrUserAlreadyExists.Error():        "شما قبلا کاربر ساخته‌اید",
+	errorext.ErrUsernameTaken.Error():            "این نام کاربری قبلا گرفته شده است",
+	errorext.ErrInvalidLinkCode.Error():          "کد اتصال نامعتبر است یا منقضی شده است",
+	errorext.ErrAccountAlreadyLinked.Error():     "این حساب قبلا به یک کاربر متصل شده است",
+	errorext.ErrAccountNotFound.Error():          "این حساب وجود ندارد",
+	errorext.ErrTooManyAccounts.Error():          "به حداکثر تعداد حساب‌ها رسیده‌اید",
+	errorext.ErrTicketNotFound.Error():           "این تیکت وجود ندارد",
+	errorext.ErrTicketClosed.Error():             "این تیکت قبلا بسته شده است",
+	errorext.ErrSupportMessageRequired.Error():   "لطفا مشکل خود را در یک پیام متنی توضیح دهید",
+	errorext.ErrUserOfAnotherBot.Error():         "کاربر شما متعلق به ربات دیگری است",
+	errorext.ErrTrialUnavailable.Error():         "بسته آزمایشی برای شما در دسترس نیست",
+}
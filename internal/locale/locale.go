@@ -0,0 +1,102 @@
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"strings"
+)
+
+const (
+	LanguageEnglish = "en"
+	LanguagePersian = "fa"
+
+	DefaultLanguage = LanguageEnglish
+
+	// DefaultParseMode is the parse mode of the default templates.
+	DefaultParseMode = tg.ParseModeHTML
+)
+
+var Languages = []string{LanguageEnglish, LanguagePersian}
+
+// LanguageNames are the names of the supported languages in their own language.
+var LanguageNames = map[string]string{
+	LanguageEnglish: "English",
+	LanguagePersian: "فارسی",
+}
+
+var templates = map[string]map[string]string{
+	LanguageEnglish: englishTemplates,
+	LanguagePersian: persianTemplates,
+}
+
+var labels = map[string]map[string]string{
+	LanguageEnglish: englishLabels,
+	LanguagePersian: persianLabels,
+}
+
+// errorCatalogs translates the messages of errorext errors, english messages are the errors themselves.
+var errorCatalogs = map[string]map[string]string{
+	LanguagePersian: persianErrors,
+}
+
+// Template returns the default template of the message, the english template is used if the message isn't
+// translated. It reports false if there is no such message.
+func Template(lang, key string) (string, bool) {
+	if tmpl, ok := templates[lang][key]; ok {
+		return tmpl, true
+	}
+
+	tmpl, ok := englishTemplates[key]
+
+	return tmpl, ok
+}
+
+// T returns the label of the language formatted with args, the english label is used if the label isn't
+// translated.
+func T(lang, key string, args ...any) string {
+	label, ok := labels[lang][key]
+	if !ok {
+		label, ok = englishLabels[key]
+		if !ok {
+			return key
+		}
+	}
+
+	if len(args) <= 0 {
+		return label
+	}
+
+	return fmt.Sprintf(label, args...)
+}
+
+// Error translates the errorext errors, other errors are returned as they are.
+func Error(lang string, err error) string {
+	var extErr *errorext.Error
+	if !errors.As(err, &extErr) {
+		return err.Error()
+	}
+
+	if msg, ok := errorCatalogs[lang][extErr.Error()]; ok {
+		return msg
+	}
+
+	return extErr.Error()
+}
+
+func IsSupported(lang string) bool {
+	_, ok := templates[lang]
+
+	return ok
+}
+
+// Detect returns the supported language of an IETF language tag (e.g. fa-IR), or the default language.
+func Detect(tag string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	if IsSupported(lang) {
+		return lang
+	}
+
+	return DefaultLanguage
+}
@@ -0,0 +1,118 @@
+package locale
+
+// keys of the message templates, admins can override the templates of each language.
+const (
+	MsgHelp                = "help"
+	MsgUnknownCommand      = "unknown_command"
+	MsgCredentials         = "credentials"
+	MsgNewPassword         = "new_password"
+	MsgNoActivePackage     = "no_active_package"
+	MsgStatus              = "status"
+	MsgNoActiveConnections = "no_active_connections"
+	MsgConnections         = "connections"
+	MsgReferrals           = "referrals"
+	MsgNotificationsUsage  = "notifications_usage"
+	MsgNotificationsOn     = "notifications_on"
+	MsgNotificationsOff    = "notifications_off"
+	MsgChooseLanguage      = "choose_language"
+	MsgLanguageChanged     = "language_changed"
+	MsgUsageThreshold      = "usage_threshold"
+	MsgTrafficRunOut       = "traffic_run_out"
+	MsgFairUseCapReached   = "fair_use_cap_reached"
+	MsgPackageActivated    = "package_activated"
+	MsgPackageExpires      = "package_expires"
+	MsgBanned              = "banned"
+	MsgUnbanned            = "unbanned"
+	MsgPasswordChanged     = "password_changed"
+	MsgConnect             = "connect"
+	MsgSession             = "session"
+	MsgPackages            = "packages"
+	MsgLogoutEverywhere    = "logout_everywhere"
+	MsgAccountLinked       = "account_linked"
+	MsgAccounts            = "accounts"
+	MsgTicketReceived      = "ticket_received"
+	MsgSupportReply        = "support_reply"
+	MsgTicketClosed        = "ticket_closed"
+	MsgPlans               = "plans"
+	MsgNoPlans             = "no_plans"
+	MsgJoinChannel         = "join_channel"
+)
+
+// keys of the labels, labels are plain text used within the messages and are not editable.
+const (
+	LabelUnlimited            = "unlimited"
+	LabelNever                = "never"
+	LabelDays                 = "days"
+	LabelMinutes              = "minutes"
+	LabelCancel               = "cancel"
+	LabelConversationTimedOut = "conversation_timed_out"
+	LabelNothingToCancel      = "nothing_to_cancel"
+	LabelCanceled             = "canceled"
+	LabelRevealPassword       = "reveal_password"
+	LabelRevealedPassword     = "revealed_password"
+	LabelEnterPassword        = "enter_password"
+	LabelProfileCaption       = "profile_caption"
+	LabelMenuConnections      = "menu_connections"
+	LabelMenuPackages         = "menu_packages"
+	LabelMenuRefresh          = "menu_refresh"
+	LabelMenuBack             = "menu_back"
+	LabelMenuPrevious         = "menu_previous"
+	LabelMenuNext             = "menu_next"
+	LabelMenuDisconnect       = "menu_disconnect"
+	LabelMenuSession          = "menu_session"
+	LabelDisconnected         = "disconnected"
+	LabelPackageActive        = "package_active"
+	LabelPackageReserved      = "package_reserved"
+	LabelMenuLogoutEverywhere = "menu_logout_everywhere"
+	LabelMenuConfirm          = "menu_confirm"
+	LabelLoggedOutEverywhere  = "logged_out_everywhere"
+	LabelLinkUsage            = "link_usage"
+	LabelMenuAccounts         = "menu_accounts"
+	LabelMenuNewAccount       = "menu_new_account"
+	LabelAccountActive        = "account_active"
+	LabelSwitchedAccount      = "switched_account"
+	LabelAccountCreated       = "account_created"
+	LabelEnterSupportMessage  = "enter_support_message"
+	LabelJoinChannel          = "join_channel"
+	LabelCaptcha              = "captcha"
+	LabelWrongCaptcha         = "wrong_captcha"
+	LabelCaptchaFailed        = "captcha_failed"
+	LabelTrialGranted         = "trial_granted"
+)
+
+var MessageKeys = []string{
+	MsgHelp,
+	MsgUnknownCommand,
+	MsgCredentials,
+	MsgNewPassword,
+	MsgNoActivePackage,
+	MsgStatus,
+	MsgNoActiveConnections,
+	MsgConnections,
+	MsgReferrals,
+	MsgNotificationsUsage,
+	MsgNotificationsOn,
+	MsgNotificationsOff,
+	MsgChooseLanguage,
+	MsgLanguageChanged,
+	MsgUsageThreshold,
+	MsgTrafficRunOut,
+	MsgFairUseCapReached,
+	MsgPackageActivated,
+	MsgPackageExpires,
+	MsgBanned,
+	MsgUnbanned,
+	MsgPasswordChanged,
+	MsgConnect,
+	MsgSession,
+	MsgPackages,
+	MsgLogoutEverywhere,
+	MsgAccountLinked,
+	MsgAccounts,
+	MsgTicketReceived,
+	MsgSupportReply,
+	MsgTicketClosed,
+	MsgPlans,
+	MsgNoPlans,
+	MsgJoinChannel,
+}
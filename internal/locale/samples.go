@@ -0,0 +1,114 @@
+package locale
+
+import "github.com/alir32a/jupiter/pkg/tg"
+
+// samples are the data the templates are previewed with, they also document the variables of each template.
+var samples = map[string]tg.TemplateData{
+	MsgCredentials: {"Username": "jupiter", "ExpiresIn": "60 Minutes"},
+	MsgNewPassword: {"Username": "jupiter", "ExpiresIn": "60 Minutes"},
+	MsgStatus: {
+		"Username":       "jupiter",
+		"TrafficLimit":   "50.00 GB",
+		"DownloadUsage":  "12.30 GB",
+		"UploadUsage":    "1.20 GB",
+		"TotalUsage":     "13.50 GB",
+		"MaxConnections": 2,
+		"ExpireAt":       "2024-06-01T12:00:00 +03:30",
+		"FairUse":        true,
+		"ThrottleRate":   "128.00 KB",
+		"FairUseCap":     "40.00 GB",
+		"ReservedPackages": []tg.TemplateData{
+			{"Index": 1, "TrafficLimit": "20.00 GB", "MaxConnections": 1, "Expiration": "30 Days"},
+		},
+	},
+	MsgConnections: {
+		"Connections": []tg.TemplateData{
+			{
+				"Index":         1,
+				"IP":            "203.0.113.7",
+				"Location":      "Tehran",
+				"UserAgent":     "AnyConnect Android 4.10",
+				"Device":        "pixel-7",
+				"DownloadUsage": "310.00 MB",
+				"UploadUsage":   "25.00 MB",
+				"ConnectedAt":   "2024-05-01T09:30:00 +03:30",
+			},
+		},
+	},
+	MsgReferrals: {
+		"ReferralCode":  "cn1v8a2t0ed8l3b6ii8g",
+		"ReferralLink":  "https://t.me/jupiter_bot?start=cn1v8a2t0ed8l3b6ii8g",
+		"InvitedUsers":  3,
+		"RewardedUsers": 1,
+		"EarnedTraffic": "5.00 GB",
+		"EarnedDays":    7,
+		"EarnedCredit":  0,
+		"WalletBalance": 0,
+	},
+	MsgUsageThreshold:    {"Percent": 80, "Usage": "40.00 GB", "Limit": "50.00 GB"},
+	MsgFairUseCapReached: {"ThrottleRate": "128.00 KB"},
+	MsgPackageActivated:  {"TrafficLimit": "20.00 GB", "MaxConnections": 1},
+	MsgPackageExpires:    {"ExpireAt": "2024-06-01 12:00:00"},
+	MsgAccountLinked:     {"Username": "jupiter"},
+	MsgTicketReceived:    {"ID": 12},
+	MsgSupportReply:      {"ID": 12, "Text": "please reinstall the profile with /connect and try again"},
+	MsgTicketClosed:      {"ID": 12},
+	MsgJoinChannel:       {"Channel": "@jupiter"},
+	MsgPlans: {
+		"Plans": []tg.TemplateData{
+			{"Name": "monthly", "TrafficLimit": "50.00 GB", "MaxConnections": 2, "Expiration": "30 days",
+				"Price": 150000},
+		},
+	},
+	MsgAccounts: {
+		"Accounts": []tg.TemplateData{
+			{"Index": 1, "Username": "jupiter", "Active": true},
+			{"Index": 2, "Username": "jupiter-2", "Active": false},
+		},
+	},
+	MsgSession: {
+		"IP":            "203.0.113.7",
+		"Location":      "Tehran",
+		"UserAgent":     "AnyConnect Android 4.10",
+		"Device":        "pixel-7",
+		"DownloadUsage": "310.00 MB",
+		"UploadUsage":   "25.00 MB",
+		"ConnectedAt":   "2024-05-01T09:30:00 +03:30",
+	},
+	MsgPackages: {
+		"Packages": []tg.TemplateData{
+			{
+				"Index":          1,
+				"Status":         "Active",
+				"TrafficLimit":   "50.00 GB",
+				"TotalUsage":     "13.50 GB",
+				"MaxConnections": 2,
+				"Expiration":     "2024-06-01T12:00:00 +03:30",
+			},
+			{
+				"Index":          2,
+				"Status":         "Reserved",
+				"TrafficLimit":   "20.00 GB",
+				"TotalUsage":     "0.00 B",
+				"MaxConnections": 1,
+				"Expiration":     "30 Days",
+			},
+		},
+		"Page":       1,
+		"TotalPages": 1,
+	},
+	MsgConnect: {
+		"Address":  "vpn.example.com",
+		"Username": "jupiter",
+		"Command":  "sudo openconnect --user=jupiter vpn.example.com",
+	},
+}
+
+// SampleData returns the data the template of the message is previewed with.
+func SampleData(key string) tg.TemplateData {
+	if data, ok := samples[key]; ok {
+		return data
+	}
+
+	return tg.TemplateData{}
+}
@@ -2,12 +2,30 @@ package model
 
 import "time"
 
+const (
+	AdminRoleAdmin = "admin"
+	// AdminRoleReseller can only manage their own users through the api, within the quota they've bought.
+	AdminRoleReseller = "reseller"
+)
+
 type AdminEntity struct {
-	ID        int
-	Username  string
-	Password  string
-	IsActive  bool
-	CreatedAt time.Time
+	ID         int
+	Username   string
+	Password   string
+	TelegramID *string
+	Role       string
+	// the quotas and the commission rate are only used for resellers, traffic is in bytes.
+	UserQuota      int
+	UsedUsers      int
+	TrafficQuota   int
+	UsedTraffic    int
+	CommissionRate int
+	IsActive       bool
+	CreatedAt      time.Time
+}
+
+func (a AdminEntity) IsReseller() bool {
+	return a.Role == AdminRoleReseller
 }
 
 type CreateAdminRequest struct {
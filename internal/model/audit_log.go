@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+const (
+	AuditResourcePackage = "package"
+
+	AuditActionTopUpPackage    = "top_up"
+	AuditActionExtendPackage   = "extend"
+	AuditActionTransferPackage = "transfer"
+	AuditActionCancelPackage   = "cancel"
+	AuditActionCreatePackage   = "create"
+)
+
+type AuditLogEntity struct {
+	ID         int
+	Actor      string
+	Action     string
+	Resource   string
+	ResourceID int
+	Details    string
+	CreatedAt  time.Time
+}
+
+type CreateAuditLogRequest struct {
+	Actor      string
+	Action     string
+	Resource   string
+	ResourceID int
+	Details    string
+}
+
+type GetAuditLogsRequest struct {
+	Pagination
+	Resource   string
+	ResourceID int
+}
+
+type GetAuditLogsResponse struct {
+	AuditLogs []AuditLogEntity
+	Pagination
+}
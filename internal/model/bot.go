@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// MainBotID is the id the main bot is known by, the bots of the resellers are kept in the bot table.
+const MainBotID = 0
+
+const (
+	AuditResourceBot = "bot"
+
+	AuditActionCreateBot     = "create"
+	AuditActionActivateBot   = "activate"
+	AuditActionDeactivateBot = "deactivate"
+)
+
+type BotEntity struct {
+	ID            int
+	AdminID       int
+	AdminUsername string
+	Token         string
+	Username      string
+	WebhookSecret string
+	IsActive      bool
+	CreatedAt     time.Time
+}
+
+type CreateBotRequest struct {
+	// AdminUsername is the reseller the bot belongs to.
+	AdminUsername string
+	Token         string
+	Actor         string
+}
+
+type SetBotActiveRequest struct {
+	ID     int
+	Active bool
+	Actor  string
+}
+
+type GetBotsRequest struct {
+	Pagination
+}
+
+type GetBotsResponse struct {
+	Bots []BotEntity
+	Pagination
+}
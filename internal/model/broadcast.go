@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+const (
+	BroadcastFilterAll           = "all"
+	BroadcastFilterActivePackage = "active_package"
+	BroadcastFilterTrial         = "trial"
+	BroadcastFilterBanned        = "banned"
+
+	BroadcastStatusRunning = "running"
+	BroadcastStatusDone    = "done"
+
+	BroadcastDeliveryStatusPending = "pending"
+	BroadcastDeliveryStatusSent    = "sent"
+	BroadcastDeliveryStatusFailed  = "failed"
+)
+
+type BroadcastEntity struct {
+	ID         int
+	Text       string
+	ParseMode  string
+	ButtonText string
+	ButtonUrl  string
+	Filter     string
+	Status     string
+	Total      int
+	Sent       int
+	Failed     int
+	CreatedBy  string
+	CreatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+type BroadcastDeliveryEntity struct {
+	ID            int
+	BroadcastID   int
+	UserID        int
+	ChatID        string
+	Status        string
+	Attempts      int
+	Error         string
+	NextAttemptAt time.Time
+	UpdatedAt     time.Time
+}
+
+type CreateBroadcastRequest struct {
+	Text       string
+	ParseMode  string
+	ButtonText string
+	ButtonUrl  string
+	Filter     string
+	CreatedBy  string
+}
+
+type UpdateBroadcastDeliveryRequest struct {
+	ID          int
+	BroadcastID int
+	Status      string
+	Attempts    int
+	Error       string
+	// NextAttemptAt is when a pending delivery is retried.
+	NextAttemptAt time.Time
+}
+
+type GetBroadcastsRequest struct {
+	Pagination
+}
+
+type GetBroadcastsResponse struct {
+	Broadcasts []BroadcastEntity
+	Pagination
+}
+
+type GetBroadcastReportResponse struct {
+	Broadcast        BroadcastEntity
+	FailedDeliveries []BroadcastDeliveryEntity
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// CallbackPayloadEntity is the data of an inline keyboard button, buttons only carry the key of their payload
+// since telegram limits callback data to 64 bytes.
+type CallbackPayloadEntity struct {
+	Key       string
+	ChatID    int
+	Data      map[string]string
+	ExpireAt  time.Time
+	CreatedAt time.Time
+}
+
+type SaveCallbackPayloadRequest struct {
+	Key      string
+	ChatID   int
+	Data     map[string]string
+	ExpireAt time.Time
+}
+
+func (c CallbackPayloadEntity) IsExpired() bool {
+	return c.ExpireAt.Before(time.Now())
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+type ConversationEntity struct {
+	BotID     int
+	ChatID    int
+	Name      string
+	Step      string
+	Data      map[string]string
+	ExpireAt  time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SaveConversationRequest struct {
+	BotID    int
+	ChatID   int
+	Name     string
+	Step     string
+	Data     map[string]string
+	ExpireAt time.Time
+}
+
+func (c ConversationEntity) IsExpired() bool {
+	return c.ExpireAt.Before(time.Now())
+}
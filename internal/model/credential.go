@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// MessageDeletionEntity is a bot message that has to be deleted from the chat at DeleteAt.
+type MessageDeletionEntity struct {
+	ID        int
+	BotID     int
+	ChatID    int
+	MessageID int
+	DeleteAt  time.Time
+	Attempts  int
+}
+
+type ScheduleMessageDeletionRequest struct {
+	BotID     int
+	ChatID    int
+	MessageID int
+	DeleteAt  time.Time
+}
+
+// CredentialRevealEntity is a password waiting to be revealed once, the password is sealed with the reveal
+// token which is only kept in the button of the credentials message.
+type CredentialRevealEntity struct {
+	ID             int
+	TokenHash      string
+	ChatID         int
+	Username       string
+	SealedPassword string
+	ExpireAt       time.Time
+	CreatedAt      time.Time
+}
+
+type CreateCredentialRevealRequest struct {
+	TokenHash      string
+	ChatID         int
+	Username       string
+	SealedPassword string
+	ExpireAt       time.Time
+}
+
+type RevealedCredentials struct {
+	Username string
+	Password string
+}
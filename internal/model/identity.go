@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+const (
+	IdentityProviderTelegram = "telegram"
+)
+
+// UserIdentityEntity is an account of the user on one of the providers, a user can have many accounts but
+// an account belongs to only one user.
+type UserIdentityEntity struct {
+	ID         int
+	UserID     int
+	Provider   string
+	ExternalID string
+	CreatedAt  time.Time
+}
+
+type LinkIdentityRequest struct {
+	CodeHash   string
+	Provider   string
+	ExternalID string
+	// BotID is the bot the account is linked from, codes of the users of other bots are rejected.
+	BotID int
+}
+
+type CreateLinkCodeRequest struct {
+	CodeHash string
+	UserID   int
+	ExpireAt time.Time
+}
+
+// LinkCode is a one time code the user sends to the bot to link their account to an existing user.
+type LinkCode struct {
+	Code     string
+	ExpireAt time.Time
+}
+
+type CreatePanelUserRequest struct {
+	Username   string
+	BotID      int
+	ResellerID *int
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+const AuditResourceMessageTemplate = "message_template"
+
+const (
+	AuditActionUpdateMessageTemplate = "update"
+	AuditActionResetMessageTemplate  = "reset"
+)
+
+// MessageTemplateEntity is a template an admin has replaced the default template of a message with.
+type MessageTemplateEntity struct {
+	ID        int
+	Key       string
+	Language  string
+	ParseMode string
+	Body      string
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// MessageTemplate is the template a message is rendered with, along with the default template of the message.
+type MessageTemplate struct {
+	Key              string
+	Language         string
+	ParseMode        string
+	Body             string
+	DefaultParseMode string
+	DefaultBody      string
+	Customized       bool
+	Variables        []string
+	UpdatedBy        string
+	UpdatedAt        *time.Time
+}
+
+type SaveMessageTemplateRequest struct {
+	Key       string
+	Language  string
+	ParseMode string
+	Body      string
+	Actor     string
+}
+
+type ResetMessageTemplateRequest struct {
+	Key      string
+	Language string
+	Actor    string
+}
+
+type PreviewMessageTemplateRequest struct {
+	Key       string
+	Language  string
+	ParseMode string
+	Body      string
+}
+
+type RenderedMessage struct {
+	Text      string
+	ParseMode string
+}
@@ -0,0 +1,17 @@
+package model
+
+const (
+	NotificationKindUsage            = "usage"
+	NotificationKindExpiry           = "expiry"
+	NotificationKindPackageActivated = "package_activated"
+	NotificationKindBanned           = "banned"
+	NotificationKindUnbanned         = "unbanned"
+)
+
+type CreateNotificationRequest struct {
+	UserID int
+	Kind   string
+	// Reference identifies what the notification is about (e.g. a package and a usage threshold),
+	// a notification is sent only once per user, kind and reference.
+	Reference string
+}
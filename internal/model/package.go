@@ -2,30 +2,100 @@ package model
 
 import "time"
 
+const (
+	// PackageTypeStandard packages are limited by both traffic and time.
+	PackageTypeStandard = "standard"
+	// PackageTypeUnlimited packages have unlimited traffic and are limited only by time.
+	PackageTypeUnlimited = "unlimited"
+	// PackageTypeTrafficOnly packages never expire and are limited only by traffic.
+	PackageTypeTrafficOnly = "traffic_only"
+)
+
+const (
+	// PackageConsumptionOrderFIFO consumes reserved packages in the order they were purchased.
+	PackageConsumptionOrderFIFO = "fifo"
+	// PackageConsumptionOrderSmallestFirst consumes the packages with the least traffic first.
+	PackageConsumptionOrderSmallestFirst = "smallest_first"
+	// PackageConsumptionOrderExpiringSoonestFirst consumes the packages with the shortest expiration first.
+	PackageConsumptionOrderExpiringSoonestFirst = "expiring_soonest_first"
+	// PackageConsumptionOrderTrialLast consumes purchased packages before trials, each group in purchase order.
+	PackageConsumptionOrderTrialLast = "trial_last"
+)
+
+var PackageConsumptionOrders = []string{
+	PackageConsumptionOrderFIFO,
+	PackageConsumptionOrderSmallestFirst,
+	PackageConsumptionOrderExpiringSoonestFirst,
+	PackageConsumptionOrderTrialLast,
+}
+
 type CreatePackageRequest struct {
 	Username         string
 	UserID           int
+	PlanID           int
+	PackageType      string
 	Traffic          int
 	MaxConnections   int
 	IsTrial          bool
 	ExpirationInDays int
+	FairUseCap       int
+	ThrottleRate     int
 	ExpireAt         *time.Time
+	Actor            string
+	// ResellerID is set for the packages created by a reseller, only for their own users.
+	ResellerID *int
 }
 
 type PackageEntity struct {
 	ID                   int
 	UserID               int
+	PlanID               *int
 	Username             string
+	PackageType          string
 	TrafficLimit         int
 	DownloadTrafficUsage int
 	UploadTrafficUsage   int
 	MaxConnections       int
 	IsTrial              bool
 	ExpirationInDays     int
+	FairUseCap           int
+	ThrottleRate         int
+	ActivatedAt          *time.Time
 	ExpireAt             *time.Time
+	CanceledAt           *time.Time
 	CreatedAt            time.Time
 }
 
+type ExtendPackageRequest struct {
+	ID      int
+	Traffic int
+	Days    int
+}
+
+type TopUpPackageRequest struct {
+	ID      int
+	Traffic int
+	Actor   string
+}
+
+type ExtendPackageExpiryRequest struct {
+	ID    int
+	Days  int
+	Actor string
+}
+
+type TransferPackageRequest struct {
+	ID       int
+	Username string
+	Actor    string
+}
+
+type CancelPackageRequest struct {
+	ID     int
+	Refund int
+	Actor  string
+}
+
 type UpdateTrafficUsageRequest struct {
 	ID                   int
 	DownloadTrafficUsage int
@@ -52,3 +122,25 @@ type GetPackagesResponse struct {
 	Packages []PackageEntity
 	Pagination
 }
+
+func (p PackageEntity) IsActive() bool {
+	return p.CanceledAt == nil && p.ActivatedAt != nil && (p.ExpireAt == nil || p.ExpireAt.After(time.Now()))
+}
+
+func (p PackageEntity) IsReserved() bool {
+	return p.CanceledAt == nil && p.ActivatedAt == nil
+}
+
+func (p PackageEntity) HasUnlimitedTraffic() bool {
+	return p.PackageType == PackageTypeUnlimited
+}
+
+// HasFairUse reports whether the user should be throttled after reaching the fair use cap instead of being
+// disconnected when the package runs out of traffic.
+func (p PackageEntity) HasFairUse() bool {
+	return p.FairUseCap > 0 && p.ThrottleRate > 0
+}
+
+func (p PackageEntity) HasExpiry() bool {
+	return p.PackageType != PackageTypeTrafficOnly
+}
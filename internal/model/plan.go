@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+type PlanEntity struct {
+	ID               int
+	BotID            int
+	Name             string
+	PackageType      string
+	TrafficLimit     int
+	MaxConnections   int
+	ExpirationInDays int
+	FairUseCap       int
+	ThrottleRate     int
+	Price            int
+	IsActive         bool
+	CreatedAt        time.Time
+}
+
+type CreatePlanRequest struct {
+	BotID            int
+	Name             string
+	PackageType      string
+	TrafficLimit     int
+	MaxConnections   int
+	ExpirationInDays int
+	FairUseCap       int
+	ThrottleRate     int
+	Price            int
+}
+
+type GetPlansRequest struct {
+	Pagination
+	// BotID only returns the plans of the bot if it's set.
+	BotID      *int
+	ActiveOnly bool
+}
+
+type GetPlansResponse struct {
+	Plans []PlanEntity
+	Pagination
+}
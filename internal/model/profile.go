@@ -0,0 +1,13 @@
+package model
+
+// ConnectionProfile is everything a user needs to set up a client for the server.
+type ConnectionProfile struct {
+	Name    string
+	Address string
+	// Command is the openconnect command line that connects the user.
+	Command string
+	// URI adds the server to the AnyConnect mobile apps, the QR code encodes it.
+	URI        string
+	ProfileXML []byte
+	QRCode     []byte
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type ReferralInviteEntity struct {
+	ExternalID   string
+	ReferralCode string
+	CreatedAt    time.Time
+}
+
+type ReferralReward struct {
+	Traffic int
+	Days    int
+	Credit  int
+}
+
+type CreateReferralRewardRequest struct {
+	ReferrerID     int
+	RefereeID      int
+	ReferrerReward ReferralReward
+	RefereeReward  ReferralReward
+}
+
+type ReferralStats struct {
+	ReferralCode  string
+	InvitedUsers  int
+	RewardedUsers int
+	TotalTraffic  int
+	TotalDays     int
+	TotalCredit   int
+	WalletBalance int
+}
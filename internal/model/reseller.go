@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+const (
+	AuditResourceReseller = "reseller"
+
+	AuditActionCreateReseller   = "create"
+	AuditActionAddResellerQuota = "add_quota"
+	AuditActionRotateAPIToken   = "rotate_api_token"
+	AuditActionPayout           = "payout"
+)
+
+// CreateResellerRequest creates a reseller with the quota they've bought, traffic is given in GB and the
+// commission rate in percents of the plan prices.
+type CreateResellerRequest struct {
+	Username       string
+	Password       string
+	UserQuota      int
+	TrafficQuota   int
+	CommissionRate int
+	Actor          string
+}
+
+// AddResellerQuotaRequest adds the quota the reseller has bought, traffic is given in GB.
+type AddResellerQuotaRequest struct {
+	ID      int
+	Users   int
+	Traffic int
+	Actor   string
+}
+
+type GetResellersRequest struct {
+	Pagination
+}
+
+type GetResellersResponse struct {
+	Resellers []AdminEntity
+	Pagination
+}
+
+type ResellerCommissionEntity struct {
+	ID         int
+	ResellerID int
+	UserID     *int
+	PlanID     *int
+	Amount     int
+	Note       string
+	CreatedAt  time.Time
+}
+
+type CreateResellerCommissionRequest struct {
+	ResellerID int
+	UserID     *int
+	PlanID     *int
+	Amount     int
+	Note       string
+}
+
+type GetResellerCommissionsRequest struct {
+	ResellerID int
+	Pagination
+}
+
+type GetResellerCommissionsResponse struct {
+	Commissions []ResellerCommissionEntity
+	// Balance is the commission that isn't paid out yet.
+	Balance int
+	Pagination
+}
+
+type CreatePayoutRequest struct {
+	ResellerID int
+	Amount     int
+	Note       string
+	Actor      string
+}
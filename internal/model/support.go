@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+const (
+	SupportTicketStatusOpen     = "open"
+	SupportTicketStatusAnswered = "answered"
+	SupportTicketStatusClosed   = "closed"
+
+	SupportSenderUser  = "user"
+	SupportSenderAdmin = "admin"
+)
+
+type SupportTicketEntity struct {
+	ID        int
+	UserID    *int
+	Username  string
+	BotID     int
+	ChatID    string
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  *time.Time
+}
+
+type SupportMessageEntity struct {
+	ID                 int
+	TicketID           int
+	Sender             string
+	AdminUsername      string
+	Text               string
+	ForwardedMessageID *int
+	CreatedAt          time.Time
+}
+
+type CreateSupportMessageRequest struct {
+	UserID *int
+	BotID  int
+	ChatID string
+	// From is how the sender is shown to the admins in the support chat.
+	From string
+	Text string
+}
+
+type CreateSupportMessageResponse struct {
+	Ticket  SupportTicketEntity
+	Message SupportMessageEntity
+}
+
+type ReplySupportTicketRequest struct {
+	TicketID      int
+	AdminUsername string
+	Text          string
+}
+
+type GetSupportTicketsRequest struct {
+	Status string
+	Pagination
+}
+
+type GetSupportTicketsResponse struct {
+	Tickets []SupportTicketEntity
+	Pagination
+}
+
+type GetSupportTicketResponse struct {
+	Ticket   SupportTicketEntity
+	Messages []SupportMessageEntity
+}
@@ -4,6 +4,8 @@ import "time"
 
 const (
 	UserTypeTelegram = "telegram"
+	// UserTypePanel is a user created by the administrators, it has no account until one is linked to it.
+	UserTypePanel = "panel"
 )
 
 type CreateUserRequest struct {
@@ -12,6 +14,15 @@ type CreateUserRequest struct {
 	UserType     string
 	ReferralCode string
 	Referral     *string
+	Language     string
+	// OwnerID is set for the accounts a telegram user creates in addition to their own user.
+	OwnerID *int
+	// BotID is the bot the user is created in, users can only use that bot.
+	BotID int
+	// ResellerID is set for the users created by a reseller, they're counted against the reseller's quota.
+	ResellerID *int
+	// DeferTrial creates the user without the trial package, the user gets it once they pass the trial gate.
+	DeferTrial bool
 }
 
 type CreateUserResponse struct {
@@ -26,14 +37,29 @@ type GetUserRequest struct {
 }
 
 type UserEntity struct {
-	ID           int
-	Username     string
-	ExternalID   string
-	UserType     string
-	ReferralCode string
-	Referral     *string
-	BannedAt     *time.Time
-	CreatedAt    time.Time
+	ID                   int
+	Username             string
+	ExternalID           string
+	UserType             string
+	ReferralCode         string
+	Referral             *string
+	WalletBalance        int
+	NotificationsEnabled bool
+	Language             string
+	OwnerID              *int
+	BotID                int
+	ResellerID           *int
+	TrialPending         bool
+	CaptchaFailures      int
+	ThrottledAt          *time.Time
+	BannedAt             *time.Time
+	CreatedAt            time.Time
+}
+
+// UserAccounts are the accounts of a telegram user, the user's own account comes first.
+type UserAccounts struct {
+	Accounts []UserEntity
+	ActiveID int
 }
 
 type GetUsersStatResponse struct {
@@ -45,6 +71,9 @@ type GetUsersStatResponse struct {
 type GetAllUsersRequest struct {
 	Pagination
 	Username string
+	BotID    *int
+	// ResellerID limits the users to the customers of the reseller.
+	ResellerID *int
 }
 
 type GetAllUsersResponse struct {
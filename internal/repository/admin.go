@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
 	"github.com/alir32a/jupiter/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 type AdminRepository struct {
@@ -15,13 +19,82 @@ func NewAdminRepository(db *gorm.DB) *AdminRepository {
 }
 
 func (a AdminRepository) CreateAdmin(ctx context.Context, req model.CreateAdminRequest) error {
-	return a.db.WithContext(ctx).Model(&AdminEntity{}).Create(&req).Error
+	return conn(ctx, a.db).Model(&AdminEntity{}).Create(&req).Error
 }
 
 func (a AdminRepository) GetAdminByUsername(ctx context.Context, username string) (model.AdminEntity, error) {
 	var admin AdminEntity
 
-	err := a.db.WithContext(ctx).Model(&AdminEntity{}).First(&admin, "username = ?", username).Error
+	err := conn(ctx, a.db).Model(&AdminEntity{}).First(&admin, "username = ?", username).Error
+	if err != nil {
+		return model.AdminEntity{}, err
+	}
+
+	return toModelAdminEntity(admin), nil
+}
+
+func (a AdminRepository) GetAdminByTelegramID(ctx context.Context, telegramID string) (model.AdminEntity, error) {
+	var admin AdminEntity
+
+	err := conn(ctx, a.db).Model(&AdminEntity{}).First(&admin, "telegram_id = ?", telegramID).Error
+	if err != nil {
+		return model.AdminEntity{}, err
+	}
+
+	return toModelAdminEntity(admin), nil
+}
+
+func (a AdminRepository) SetTelegramID(ctx context.Context, username string, telegramID *string) error {
+	return conn(ctx, a.db).
+		Model(&AdminEntity{}).
+		Where("username = ?", username).
+		UpdateColumn("telegram_id", telegramID).Error
+}
+
+// SetLinkCode replaces the code the admin links their telegram account with, only the hash of the code is stored.
+func (a AdminRepository) SetLinkCode(ctx context.Context, username, codeHash string, expireAt time.Time) error {
+	return conn(ctx, a.db).
+		Model(&AdminEntity{}).
+		Where("username = ?", username).
+		UpdateColumns(map[string]any{"link_code_hash": codeHash, "link_code_expire_at": expireAt}).Error
+}
+
+// LinkTelegram takes the link code and links the telegram account to the admin the code was created for, the
+// account is unlinked from the admin it was linked to before.
+func (a AdminRepository) LinkTelegram(ctx context.Context, codeHash, telegramID string) (model.AdminEntity, error) {
+	var admin AdminEntity
+
+	err := conn(ctx, a.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&admin, "link_code_hash = ? and link_code_expire_at > now()", codeHash).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errorext.NewNotFoundError(errorext.ErrInvalidLinkCode)
+			}
+
+			return err
+		}
+
+		err = tx.
+			Model(&AdminEntity{}).
+			Where("telegram_id = ? and id <> ?", telegramID, admin.ID).
+			UpdateColumn("telegram_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		admin.TelegramID = &telegramID
+
+		return tx.
+			Model(&AdminEntity{}).
+			Where("id = ?", admin.ID).
+			UpdateColumns(map[string]any{
+				"telegram_id":         telegramID,
+				"link_code_hash":      nil,
+				"link_code_expire_at": nil,
+			}).Error
+	})
 	if err != nil {
 		return model.AdminEntity{}, err
 	}
@@ -30,8 +103,7 @@ func (a AdminRepository) GetAdminByUsername(ctx context.Context, username string
 }
 
 func (a AdminRepository) ChangePassword(ctx context.Context, username, password string) error {
-	return a.db.
-		WithContext(ctx).
+	return conn(ctx, a.db).
 		Model(&AdminEntity{}).
 		Where("username = ?", username).
 		UpdateColumn("password", password).Error
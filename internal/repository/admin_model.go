@@ -6,11 +6,19 @@ import (
 )
 
 type AdminEntity struct {
-	ID        int
-	Username  string
-	Password  string
-	IsActive  bool
-	CreatedAt time.Time
+	ID             int
+	Username       string
+	Password       string
+	TelegramID     *string
+	Role           string
+	UserQuota      int
+	UsedUsers      int
+	TrafficQuota   int
+	UsedTraffic    int
+	CommissionRate int
+	APITokenHash   *string `gorm:"column:api_token_hash"`
+	IsActive       bool
+	CreatedAt      time.Time
 }
 
 func (AdminEntity) TableName() string {
@@ -19,10 +27,27 @@ func (AdminEntity) TableName() string {
 
 func toModelAdminEntity(req AdminEntity) model.AdminEntity {
 	return model.AdminEntity{
-		ID:        req.ID,
-		Username:  req.Username,
-		Password:  req.Password,
-		IsActive:  req.IsActive,
-		CreatedAt: req.CreatedAt,
+		ID:             req.ID,
+		Username:       req.Username,
+		Password:       req.Password,
+		TelegramID:     req.TelegramID,
+		Role:           req.Role,
+		UserQuota:      req.UserQuota,
+		UsedUsers:      req.UsedUsers,
+		TrafficQuota:   req.TrafficQuota,
+		UsedTraffic:    req.UsedTraffic,
+		CommissionRate: req.CommissionRate,
+		IsActive:       req.IsActive,
+		CreatedAt:      req.CreatedAt,
 	}
 }
+
+func toModelAdminEntities(req []AdminEntity) []model.AdminEntity {
+	result := make([]model.AdminEntity, 0, len(req))
+
+	for _, admin := range req {
+		result = append(result, toModelAdminEntity(admin))
+	}
+
+	return result
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (a AuditLogRepository) CreateAuditLog(ctx context.Context, req model.CreateAuditLogRequest) error {
+	return conn(ctx, a.db).Create(&AuditLogEntity{
+		Actor:      req.Actor,
+		Action:     req.Action,
+		Resource:   req.Resource,
+		ResourceID: req.ResourceID,
+		Details:    req.Details,
+	}).Error
+}
+
+func (a AuditLogRepository) GetAuditLogs(ctx context.Context, req model.GetAuditLogsRequest) (model.GetAuditLogsResponse, error) {
+	var logs []AuditLogEntity
+
+	query := conn(ctx, a.db).Model(&AuditLogEntity{})
+
+	if req.Resource != "" {
+		query = query.Where("resource = ?", req.Resource)
+	}
+
+	if req.ResourceID > 0 {
+		query = query.Where("resource_id = ?", req.ResourceID)
+	}
+
+	if err := query.Scopes(Paginate(&req.Pagination)).Order("created_at desc").Find(&logs).Error; err != nil {
+		return model.GetAuditLogsResponse{}, err
+	}
+
+	return model.GetAuditLogsResponse{
+		AuditLogs:  toModelAuditLogEntities(logs),
+		Pagination: req.Pagination,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type AuditLogEntity struct {
+	ID         int
+	Actor      string
+	Action     string
+	Resource   string
+	ResourceID int
+	Details    string
+	CreatedAt  time.Time
+}
+
+func (AuditLogEntity) TableName() string {
+	return "audit_log"
+}
+
+func toModelAuditLogEntity(req AuditLogEntity) model.AuditLogEntity {
+	return model.AuditLogEntity{
+		ID:         req.ID,
+		Actor:      req.Actor,
+		Action:     req.Action,
+		Resource:   req.Resource,
+		ResourceID: req.ResourceID,
+		Details:    req.Details,
+		CreatedAt:  req.CreatedAt,
+	}
+}
+
+func toModelAuditLogEntities(req []AuditLogEntity) []model.AuditLogEntity {
+	result := make([]model.AuditLogEntity, 0, len(req))
+
+	for _, log := range req {
+		result = append(result, toModelAuditLogEntity(log))
+	}
+
+	return result
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BotRepository struct {
+	db *gorm.DB
+}
+
+func NewBotRepository(db *gorm.DB) *BotRepository {
+	return &BotRepository{db: db}
+}
+
+func (b BotRepository) CreateBot(ctx context.Context, req model.BotEntity) (model.BotEntity, error) {
+	bot := BotEntity{
+		AdminID:       req.AdminID,
+		Token:         req.Token,
+		Username:      req.Username,
+		WebhookSecret: req.WebhookSecret,
+		IsActive:      true,
+	}
+
+	err := conn(ctx, b.db).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "created_at"}}}).
+		Create(&bot).Error
+	if err != nil {
+		return model.BotEntity{}, err
+	}
+
+	bot.AdminUsername = req.AdminUsername
+
+	return toModelBotEntity(bot), nil
+}
+
+func (b BotRepository) GetBotByID(ctx context.Context, id int) (model.BotEntity, error) {
+	var bot BotEntity
+
+	err := conn(ctx, b.db).Scopes(withBotAdminUsername).First(&bot, "bot.id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.BotEntity{}, errorext.NewNotFoundError(errorext.ErrBotNotFound)
+		}
+
+		return model.BotEntity{}, err
+	}
+
+	return toModelBotEntity(bot), nil
+}
+
+func (b BotRepository) GetBotByToken(ctx context.Context, token string) (model.BotEntity, error) {
+	var bot BotEntity
+
+	err := conn(ctx, b.db).Scopes(withBotAdminUsername).First(&bot, "bot.token = ?", token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.BotEntity{}, errorext.NewNotFoundError(errorext.ErrBotNotFound)
+		}
+
+		return model.BotEntity{}, err
+	}
+
+	return toModelBotEntity(bot), nil
+}
+
+func (b BotRepository) GetBots(ctx context.Context, req model.GetBotsRequest) (model.GetBotsResponse, error) {
+	var bots []BotEntity
+
+	err := conn(ctx, b.db).
+		Model(&BotEntity{}).
+		Scopes(Paginate(&req.Pagination), withBotAdminUsername).
+		Order("bot.created_at desc").
+		Find(&bots).Error
+	if err != nil {
+		return model.GetBotsResponse{}, err
+	}
+
+	return model.GetBotsResponse{
+		Bots:       toModelBotEntities(bots),
+		Pagination: req.Pagination,
+	}, nil
+}
+
+func (b BotRepository) GetActiveBots(ctx context.Context) ([]model.BotEntity, error) {
+	var bots []BotEntity
+
+	err := conn(ctx, b.db).Scopes(withBotAdminUsername).Where("bot.is_active").Order("bot.id").
+		Find(&bots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelBotEntities(bots), nil
+}
+
+func (b BotRepository) SetBotActive(ctx context.Context, id int, active bool) error {
+	return conn(ctx, b.db).Model(&BotEntity{}).Where("id = ?", id).UpdateColumn("is_active", active).Error
+}
+
+func withBotAdminUsername(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Select(`bot.*, "admin".username as admin_username`).
+		Joins(`JOIN "admin" ON "admin".id = bot.admin_id`)
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type BotEntity struct {
+	ID      int
+	AdminID int
+	// AdminUsername is joined from the admin the bot belongs to, it's never written.
+	AdminUsername string `gorm:"->"`
+	Token         string
+	Username      string
+	WebhookSecret string
+	IsActive      bool
+	CreatedAt     time.Time
+}
+
+func (BotEntity) TableName() string {
+	return "bot"
+}
+
+func toModelBotEntity(req BotEntity) model.BotEntity {
+	return model.BotEntity{
+		ID:            req.ID,
+		AdminID:       req.AdminID,
+		AdminUsername: req.AdminUsername,
+		Token:         req.Token,
+		Username:      req.Username,
+		WebhookSecret: req.WebhookSecret,
+		IsActive:      req.IsActive,
+		CreatedAt:     req.CreatedAt,
+	}
+}
+
+func toModelBotEntities(req []BotEntity) []model.BotEntity {
+	result := make([]model.BotEntity, 0, len(req))
+
+	for _, bot := range req {
+		result = append(result, toModelBotEntity(bot))
+	}
+
+	return result
+}
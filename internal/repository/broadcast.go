@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type BroadcastRepository struct {
+	db *gorm.DB
+}
+
+func NewBroadcastRepository(db *gorm.DB) *BroadcastRepository {
+	return &BroadcastRepository{db: db}
+}
+
+// CreateBroadcast creates the broadcast along with a pending delivery for every user matching its filter.
+func (b BroadcastRepository) CreateBroadcast(ctx context.Context, req model.CreateBroadcastRequest) (model.BroadcastEntity, error) {
+	broadcast := BroadcastEntity{
+		Text:       req.Text,
+		ParseMode:  req.ParseMode,
+		ButtonText: req.ButtonText,
+		ButtonUrl:  req.ButtonUrl,
+		Filter:     req.Filter,
+		Status:     model.BroadcastStatusRunning,
+		CreatedBy:  req.CreatedBy,
+	}
+
+	err := conn(ctx, b.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&broadcast).Error; err != nil {
+			return err
+		}
+
+		recipients := tx.
+			Model(&UserEntity{}).
+			Select("?, \"user\".id, \"user\".external_id", broadcast.ID).
+			Scopes(broadcastFilter(req.Filter))
+
+		result := tx.Exec("insert into broadcast_delivery (broadcast_id, user_id, chat_id) ?", recipients)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		broadcast.Total = int(result.RowsAffected)
+
+		return tx.Model(&broadcast).UpdateColumn("total", broadcast.Total).Error
+	})
+	if err != nil {
+		return model.BroadcastEntity{}, err
+	}
+
+	return toModelBroadcastEntity(broadcast), nil
+}
+
+func (b BroadcastRepository) GetBroadcastByID(ctx context.Context, id int) (model.BroadcastEntity, error) {
+	var broadcast BroadcastEntity
+
+	err := conn(ctx, b.db).First(&broadcast, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.BroadcastEntity{}, errorext.NewNotFoundError(errorext.ErrBroadcastNotFound)
+		}
+
+		return model.BroadcastEntity{}, err
+	}
+
+	return toModelBroadcastEntity(broadcast), nil
+}
+
+func (b BroadcastRepository) GetBroadcasts(ctx context.Context, req model.GetBroadcastsRequest) (model.GetBroadcastsResponse, error) {
+	var broadcasts []BroadcastEntity
+
+	err := conn(ctx, b.db).
+		Model(&BroadcastEntity{}).
+		Scopes(Paginate(&req.Pagination)).
+		Order("created_at desc").
+		Find(&broadcasts).Error
+	if err != nil {
+		return model.GetBroadcastsResponse{}, err
+	}
+
+	return model.GetBroadcastsResponse{
+		Broadcasts: toModelBroadcastEntities(broadcasts),
+		Pagination: req.Pagination,
+	}, nil
+}
+
+func (b BroadcastRepository) GetBroadcastsByStatus(ctx context.Context, status string) ([]model.BroadcastEntity, error) {
+	var broadcasts []BroadcastEntity
+
+	if err := conn(ctx, b.db).Where("status = ?", status).Order("id").Find(&broadcasts).Error; err != nil {
+		return nil, err
+	}
+
+	return toModelBroadcastEntities(broadcasts), nil
+}
+
+func (b BroadcastRepository) GetDeliveries(ctx context.Context, broadcastID int, status string, limit int) ([]model.BroadcastDeliveryEntity, error) {
+	var deliveries []BroadcastDeliveryEntity
+
+	query := conn(ctx, b.db).
+		Where("broadcast_id = ? and status = ?", broadcastID, status).
+		Order("next_attempt_at, id")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return toModelBroadcastDeliveryEntities(deliveries), nil
+}
+
+// UpdateDelivery updates the delivery and the broadcast's progress counters.
+func (b BroadcastRepository) UpdateDelivery(ctx context.Context, req model.UpdateBroadcastDeliveryRequest) error {
+	columns := map[string]any{
+		"status":     req.Status,
+		"attempts":   req.Attempts,
+		"error":      req.Error,
+		"updated_at": time.Now(),
+	}
+
+	if !req.NextAttemptAt.IsZero() {
+		columns["next_attempt_at"] = req.NextAttemptAt
+	}
+
+	return conn(ctx, b.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&BroadcastDeliveryEntity{}).
+			Where("id = ?", req.ID).
+			UpdateColumns(columns).Error
+		if err != nil {
+			return err
+		}
+
+		switch req.Status {
+		case model.BroadcastDeliveryStatusSent:
+			return tx.
+				Model(&BroadcastEntity{}).
+				Where("id = ?", req.BroadcastID).
+				UpdateColumn("sent", gorm.Expr("sent + 1")).Error
+		case model.BroadcastDeliveryStatusFailed:
+			return tx.
+				Model(&BroadcastEntity{}).
+				Where("id = ?", req.BroadcastID).
+				UpdateColumn("failed", gorm.Expr("failed + 1")).Error
+		}
+
+		return nil
+	})
+}
+
+func (b BroadcastRepository) FinishBroadcast(ctx context.Context, id int) error {
+	return conn(ctx, b.db).
+		Model(&BroadcastEntity{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"status":      model.BroadcastStatusDone,
+			"finished_at": time.Now(),
+		}).Error
+}
+
+func broadcastFilter(filter string) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		// broadcasts are sent through the main bot, which can't message the users of reseller bots. the accounts
+		// a user owns share the user's chat, so only the owner gets the broadcast, on behalf of all of them.
+		tx = tx.Where(`"user".user_type = ? and "user".bot_id = ? and "user".owner_id is null and 
+			"user".deleted_at is null`, model.UserTypeTelegram, model.MainBotID)
+
+		packages := tx.Session(&gorm.Session{NewDB: true}).
+			Model(&PackageEntity{}).
+			Select("1").
+			Where(`package.user_id in (select account.id from "user" account where 
+				(account.id = "user".id or account.owner_id = "user".id) and account.deleted_at is null)`).
+			Where("package.activated_at is not null").
+			Scopes(UsablePackages)
+
+		switch filter {
+		case model.BroadcastFilterActivePackage:
+			return tx.Where("\"user\".banned_at is null and exists (?)", packages)
+		case model.BroadcastFilterTrial:
+			return tx.Where("\"user\".banned_at is null and exists (?)", packages.Where("package.is_trial"))
+		case model.BroadcastFilterBanned:
+			return tx.Where("\"user\".banned_at is not null")
+		default:
+			return tx.Where("\"user\".banned_at is null")
+		}
+	}
+}
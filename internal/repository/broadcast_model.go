@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type BroadcastEntity struct {
+	ID         int
+	Text       string
+	ParseMode  string
+	ButtonText string
+	ButtonUrl  string
+	Filter     string
+	Status     string
+	Total      int
+	Sent       int
+	Failed     int
+	CreatedBy  string
+	CreatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+func (BroadcastEntity) TableName() string {
+	return "broadcast"
+}
+
+type BroadcastDeliveryEntity struct {
+	ID            int
+	BroadcastID   int
+	UserID        int
+	ChatID        string
+	Status        string
+	Attempts      int
+	Error         string
+	NextAttemptAt time.Time
+	UpdatedAt     time.Time
+}
+
+func (BroadcastDeliveryEntity) TableName() string {
+	return "broadcast_delivery"
+}
+
+func toModelBroadcastEntity(req BroadcastEntity) model.BroadcastEntity {
+	return model.BroadcastEntity{
+		ID:         req.ID,
+		Text:       req.Text,
+		ParseMode:  req.ParseMode,
+		ButtonText: req.ButtonText,
+		ButtonUrl:  req.ButtonUrl,
+		Filter:     req.Filter,
+		Status:     req.Status,
+		Total:      req.Total,
+		Sent:       req.Sent,
+		Failed:     req.Failed,
+		CreatedBy:  req.CreatedBy,
+		CreatedAt:  req.CreatedAt,
+		FinishedAt: req.FinishedAt,
+	}
+}
+
+func toModelBroadcastEntities(broadcasts []BroadcastEntity) []model.BroadcastEntity {
+	result := make([]model.BroadcastEntity, 0, len(broadcasts))
+
+	for _, broadcast := range broadcasts {
+		result = append(result, toModelBroadcastEntity(broadcast))
+	}
+
+	return result
+}
+
+func toModelBroadcastDeliveryEntity(req BroadcastDeliveryEntity) model.BroadcastDeliveryEntity {
+	return model.BroadcastDeliveryEntity{
+		ID:            req.ID,
+		BroadcastID:   req.BroadcastID,
+		UserID:        req.UserID,
+		ChatID:        req.ChatID,
+		Status:        req.Status,
+		Attempts:      req.Attempts,
+		Error:         req.Error,
+		NextAttemptAt: req.NextAttemptAt,
+		UpdatedAt:     req.UpdatedAt,
+	}
+}
+
+func toModelBroadcastDeliveryEntities(deliveries []BroadcastDeliveryEntity) []model.BroadcastDeliveryEntity {
+	result := make([]model.BroadcastDeliveryEntity, 0, len(deliveries))
+
+	for _, delivery := range deliveries {
+		result = append(result, toModelBroadcastDeliveryEntity(delivery))
+	}
+
+	return result
+}
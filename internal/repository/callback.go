@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+)
+
+type CallbackRepository struct {
+	db *gorm.DB
+}
+
+func NewCallbackRepository(db *gorm.DB) *CallbackRepository {
+	return &CallbackRepository{db: db}
+}
+
+func (c CallbackRepository) SaveCallbackPayloads(ctx context.Context, reqs []model.SaveCallbackPayloadRequest) error {
+	if len(reqs) <= 0 {
+		return nil
+	}
+
+	payloads := make([]CallbackPayloadEntity, 0, len(reqs))
+	for _, req := range reqs {
+		payload, err := toCallbackPayloadEntity(req)
+		if err != nil {
+			return err
+		}
+
+		payloads = append(payloads, payload)
+	}
+
+	return conn(ctx, c.db).Create(&payloads).Error
+}
+
+func (c CallbackRepository) GetCallbackPayload(ctx context.Context, key string) (model.CallbackPayloadEntity, error) {
+	var payload CallbackPayloadEntity
+
+	err := conn(ctx, c.db).First(&payload, "key = ?", key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.CallbackPayloadEntity{}, errorext.NewNotFoundError(errorext.ErrMenuExpired)
+		}
+
+		return model.CallbackPayloadEntity{}, err
+	}
+
+	return toModelCallbackPayloadEntity(payload)
+}
+
+func (c CallbackRepository) DeleteExpiredCallbackPayloads(ctx context.Context) error {
+	return conn(ctx, c.db).
+		Where("expire_at <= now()").
+		Delete(&CallbackPayloadEntity{}).Error
+}
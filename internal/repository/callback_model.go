@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"encoding/json"
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type CallbackPayloadEntity struct {
+	Key       string `gorm:"primaryKey"`
+	ChatID    int
+	Data      string
+	ExpireAt  time.Time
+	CreatedAt time.Time
+}
+
+func (CallbackPayloadEntity) TableName() string {
+	return "callback_payload"
+}
+
+func toCallbackPayloadEntity(req model.SaveCallbackPayloadRequest) (CallbackPayloadEntity, error) {
+	data, err := json.Marshal(req.Data)
+	if err != nil {
+		return CallbackPayloadEntity{}, err
+	}
+
+	return CallbackPayloadEntity{
+		Key:      req.Key,
+		ChatID:   req.ChatID,
+		Data:     string(data),
+		ExpireAt: req.ExpireAt,
+	}, nil
+}
+
+func toModelCallbackPayloadEntity(req CallbackPayloadEntity) (model.CallbackPayloadEntity, error) {
+	data := make(map[string]string)
+	if err := json.Unmarshal([]byte(req.Data), &data); err != nil {
+		return model.CallbackPayloadEntity{}, err
+	}
+
+	return model.CallbackPayloadEntity{
+		Key:       req.Key,
+		ChatID:    req.ChatID,
+		Data:      data,
+		ExpireAt:  req.ExpireAt,
+		CreatedAt: req.CreatedAt,
+	}, nil
+}
@@ -19,7 +19,7 @@ func NewConnectionRepository(db *gorm.DB) *ConnectionRepository {
 func (c ConnectionRepository) UpsertConnections(ctx context.Context, req model.UpsertConnectionsRequest) error {
 	connections := toConnectionEntities(req.Connections)
 
-	return c.db.WithContext(ctx).Clauses(clause.OnConflict{
+	return conn(ctx, c.db).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "external_id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"download_traffic_usage", "upload_traffic_usage", "updated_at"}),
 	}).Create(&connections).Error
@@ -28,8 +28,7 @@ func (c ConnectionRepository) UpsertConnections(ctx context.Context, req model.U
 func (c ConnectionRepository) GetActiveConnections(ctx context.Context, req model.GetActiveConnectionsRequest) (model.GetActiveConnectionsResponse, error) {
 	var result []ConnectionEntity
 
-	query := c.db.
-		WithContext(ctx).
+	query := conn(ctx, c.db).
 		Model(&ConnectionEntity{}).
 		Where("status = ?", ConnectionsStatusConnected)
 
@@ -55,8 +54,7 @@ func (c ConnectionRepository) GetActiveConnections(ctx context.Context, req mode
 func (c ConnectionRepository) GetUserActiveConnections(ctx context.Context, username string) ([]model.ConnectionEntity, error) {
 	var result []ConnectionEntity
 
-	err := c.db.
-		WithContext(ctx).
+	err := conn(ctx, c.db).
 		Model(&ConnectionEntity{}).
 		Where("status = ?", ConnectionsStatusConnected).
 		Find(&result, "username = ?", username).Error
@@ -68,8 +66,7 @@ func (c ConnectionRepository) GetUserActiveConnections(ctx context.Context, user
 }
 
 func (c ConnectionRepository) Disconnect(ctx context.Context, req model.DisconnectRequest) error {
-	return c.db.
-		WithContext(ctx).
+	return conn(ctx, c.db).
 		Model(&ConnectionEntity{}).
 		Where("id = ?", req.ConnectionID).
 		Updates(&ConnectionEntity{
@@ -83,8 +80,7 @@ func (c ConnectionRepository) Disconnect(ctx context.Context, req model.Disconne
 func (c ConnectionRepository) DisconnectID(ctx context.Context, id int) (string, error) {
 	req := ConnectionEntity{Status: model.ConnectionStatusDisConnected}
 
-	err := c.db.
-		WithContext(ctx).
+	err := conn(ctx, c.db).
 		Clauses(clause.Returning{Columns: []clause.Column{{Name: "external_id"}}}).
 		Where("id = ?", id).
 		Updates(&req).Error
@@ -98,8 +94,7 @@ func (c ConnectionRepository) DisconnectID(ctx context.Context, id int) (string,
 func (c ConnectionRepository) GetSystemStatus(ctx context.Context) (model.GetSystemStatusResponse, error) {
 	var result model.GetSystemStatusResponse
 
-	err := c.db.
-		WithContext(ctx).
+	err := conn(ctx, c.db).
 		Model(&ConnectionEntity{}).
 		Select("count(*) as total_active_connections, count(distinct username) as online_users").
 		Where("status = ?", ConnectionsStatusConnected).
@@ -108,8 +103,7 @@ func (c ConnectionRepository) GetSystemStatus(ctx context.Context) (model.GetSys
 		return model.GetSystemStatusResponse{}, err
 	}
 
-	err = c.db.
-		WithContext(ctx).
+	err = conn(ctx, c.db).
 		Model(&ConnectionEntity{}).
 		Select("sum(download_traffic_usage) as total_download_usage, sum(upload_traffic_usage) as total_upload_usage").
 		Find(&result).Error
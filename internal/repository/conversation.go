@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ConversationRepository struct {
+	db *gorm.DB
+}
+
+func NewConversationRepository(db *gorm.DB) *ConversationRepository {
+	return &ConversationRepository{db: db}
+}
+
+// SaveConversation creates or replaces the conversation of the chat, a chat has at most one conversation.
+func (c ConversationRepository) SaveConversation(ctx context.Context, req model.SaveConversationRequest) error {
+	conversation, err := toConversationEntity(req)
+	if err != nil {
+		return err
+	}
+
+	return conn(ctx, c.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bot_id"}, {Name: "chat_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "step", "data", "expire_at", "updated_at"}),
+	}).Create(&conversation).Error
+}
+
+func (c ConversationRepository) GetConversation(ctx context.Context, botID, chatID int) (model.ConversationEntity, error) {
+	var conversation ConversationEntity
+
+	err := conn(ctx, c.db).First(&conversation, "bot_id = ? and chat_id = ?", botID, chatID).Error
+	if err != nil {
+		return model.ConversationEntity{}, err
+	}
+
+	return toModelConversationEntity(conversation)
+}
+
+func (c ConversationRepository) DeleteConversation(ctx context.Context, botID, chatID int) error {
+	return conn(ctx, c.db).Where("bot_id = ? and chat_id = ?", botID, chatID).Delete(&ConversationEntity{}).Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"encoding/json"
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type ConversationEntity struct {
+	BotID     int
+	ChatID    int
+	Name      string
+	Step      string
+	Data      string
+	ExpireAt  time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (ConversationEntity) TableName() string {
+	return "conversation"
+}
+
+func toConversationEntity(req model.SaveConversationRequest) (ConversationEntity, error) {
+	data, err := json.Marshal(req.Data)
+	if err != nil {
+		return ConversationEntity{}, err
+	}
+
+	return ConversationEntity{
+		BotID:     req.BotID,
+		ChatID:    req.ChatID,
+		Name:      req.Name,
+		Step:      req.Step,
+		Data:      string(data),
+		ExpireAt:  req.ExpireAt,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+func toModelConversationEntity(req ConversationEntity) (model.ConversationEntity, error) {
+	data := make(map[string]string)
+	if err := json.Unmarshal([]byte(req.Data), &data); err != nil {
+		return model.ConversationEntity{}, err
+	}
+
+	return model.ConversationEntity{
+		BotID:     req.BotID,
+		ChatID:    req.ChatID,
+		Name:      req.Name,
+		Step:      req.Step,
+		Data:      data,
+		ExpireAt:  req.ExpireAt,
+		CreatedAt: req.CreatedAt,
+		UpdatedAt: req.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type CredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewCredentialRepository(db *gorm.DB) *CredentialRepository {
+	return &CredentialRepository{db: db}
+}
+
+func (c CredentialRepository) ScheduleMessageDeletion(ctx context.Context, req model.ScheduleMessageDeletionRequest) error {
+	return conn(ctx, c.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "bot_id"}, {Name: "chat_id"}, {Name: "message_id"}},
+			DoNothing: true,
+		}).
+		Create(&MessageDeletionEntity{
+			BotID:     req.BotID,
+			ChatID:    req.ChatID,
+			MessageID: req.MessageID,
+			DeleteAt:  req.DeleteAt,
+		}).Error
+}
+
+func (c CredentialRepository) GetDueMessageDeletions(ctx context.Context, before time.Time, limit int) ([]model.MessageDeletionEntity, error) {
+	var deletions []MessageDeletionEntity
+
+	err := conn(ctx, c.db).
+		Where("delete_at <= ?", before).
+		Order("delete_at").
+		Limit(limit).
+		Find(&deletions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelMessageDeletionEntities(deletions), nil
+}
+
+func (c CredentialRepository) DeleteMessageDeletion(ctx context.Context, id int) error {
+	return conn(ctx, c.db).Delete(&MessageDeletionEntity{}, id).Error
+}
+
+func (c CredentialRepository) IncrementMessageDeletionAttempts(ctx context.Context, id int) error {
+	return conn(ctx, c.db).
+		Model(&MessageDeletionEntity{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+func (c CredentialRepository) CreateCredentialReveal(ctx context.Context, req model.CreateCredentialRevealRequest) error {
+	return conn(ctx, c.db).Create(&CredentialRevealEntity{
+		TokenHash:      req.TokenHash,
+		ChatID:         req.ChatID,
+		Username:       req.Username,
+		SealedPassword: req.SealedPassword,
+		ExpireAt:       req.ExpireAt,
+	}).Error
+}
+
+// TakeCredentialReveal deletes the reveal and returns it, so each reveal can be taken only once.
+func (c CredentialRepository) TakeCredentialReveal(ctx context.Context, tokenHash string) (model.CredentialRevealEntity, error) {
+	var reveals []CredentialRevealEntity
+
+	result := conn(ctx, c.db).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? and expire_at > now()", tokenHash).
+		Delete(&reveals)
+	if result.Error != nil {
+		return model.CredentialRevealEntity{}, result.Error
+	}
+
+	if len(reveals) == 0 {
+		return model.CredentialRevealEntity{}, errorext.NewNotFoundError(errorext.ErrCredentialRevealNotFound)
+	}
+
+	return toModelCredentialRevealEntity(reveals[0]), nil
+}
+
+func (c CredentialRepository) DeleteExpiredCredentialReveals(ctx context.Context) error {
+	return conn(ctx, c.db).
+		Where("expire_at <= now()").
+		Delete(&CredentialRevealEntity{}).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type MessageDeletionEntity struct {
+	ID        int
+	BotID     int
+	ChatID    int
+	MessageID int
+	DeleteAt  time.Time
+	Attempts  int
+}
+
+func (MessageDeletionEntity) TableName() string {
+	return "scheduled_message_deletion"
+}
+
+type CredentialRevealEntity struct {
+	ID             int
+	TokenHash      string
+	ChatID         int
+	Username       string
+	SealedPassword string
+	ExpireAt       time.Time
+	CreatedAt      time.Time
+}
+
+func (CredentialRevealEntity) TableName() string {
+	return "credential_reveal"
+}
+
+func toModelMessageDeletionEntity(deletion MessageDeletionEntity) model.MessageDeletionEntity {
+	return model.MessageDeletionEntity{
+		ID:        deletion.ID,
+		BotID:     deletion.BotID,
+		ChatID:    deletion.ChatID,
+		MessageID: deletion.MessageID,
+		DeleteAt:  deletion.DeleteAt,
+		Attempts:  deletion.Attempts,
+	}
+}
+
+func toModelMessageDeletionEntities(deletions []MessageDeletionEntity) []model.MessageDeletionEntity {
+	result := make([]model.MessageDeletionEntity, 0, len(deletions))
+
+	for _, deletion := range deletions {
+		result = append(result, toModelMessageDeletionEntity(deletion))
+	}
+
+	return result
+}
+
+func toModelCredentialRevealEntity(reveal CredentialRevealEntity) model.CredentialRevealEntity {
+	return model.CredentialRevealEntity{
+		ID:             reveal.ID,
+		TokenHash:      reveal.TokenHash,
+		ChatID:         reveal.ChatID,
+		Username:       reveal.Username,
+		SealedPassword: reveal.SealedPassword,
+		ExpireAt:       reveal.ExpireAt,
+		CreatedAt:      reveal.CreatedAt,
+	}
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (u UserRepository) GetUserByIdentity(ctx context.Context, provider, externalID string) (model.UserEntity, error) {
+	var user UserEntity
+
+	err := conn(ctx, u.db).
+		Joins(`JOIN "user_identity" ON "user_identity".user_id = "user".id`).
+		Where(`"user_identity".provider = ? AND "user_identity".external_id = ?`, provider, externalID).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.UserEntity{}, errorext.NewNotFoundError(errorext.ErrUserNotFound)
+		}
+
+		return model.UserEntity{}, err
+	}
+
+	return toModelUserEntity(user), nil
+}
+
+func (u UserRepository) GetUserIdentities(ctx context.Context, userID int) ([]model.UserIdentityEntity, error) {
+	var identities []UserIdentityEntity
+
+	err := conn(ctx, u.db).Where("user_id = ?", userID).Order("id").Find(&identities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelUserIdentityEntities(identities), nil
+}
+
+func (u UserRepository) CreateLinkCode(ctx context.Context, req model.CreateLinkCodeRequest) error {
+	return conn(ctx, u.db).Create(&LinkCodeEntity{
+		CodeHash: req.CodeHash,
+		UserID:   req.UserID,
+		ExpireAt: req.ExpireAt,
+	}).Error
+}
+
+// LinkIdentity takes the link code and adds the account to the user of the code, users that don't have an
+// account yet get it as their primary one, which is where the notifications are sent to.
+func (u UserRepository) LinkIdentity(ctx context.Context, req model.LinkIdentityRequest) (model.UserEntity, error) {
+	var user UserEntity
+
+	err := conn(ctx, u.db).Transaction(func(tx *gorm.DB) error {
+		var codes []LinkCodeEntity
+
+		err := tx.
+			Clauses(clause.Returning{}).
+			Where("code_hash = ? and expire_at > now()", req.CodeHash).
+			Delete(&codes).Error
+		if err != nil {
+			return err
+		}
+
+		if len(codes) == 0 {
+			return errorext.NewNotFoundError(errorext.ErrInvalidLinkCode)
+		}
+
+		if err := tx.First(&user, codes[0].UserID).Error; err != nil {
+			return err
+		}
+
+		if user.BotID != req.BotID {
+			return errorext.NewNotFoundError(errorext.ErrInvalidLinkCode)
+		}
+
+		err = tx.Create(&UserIdentityEntity{
+			UserID:     codes[0].UserID,
+			Provider:   req.Provider,
+			ExternalID: req.ExternalID,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Model(&UserEntity{}).
+			Where("id = ? and (external_id is null or external_id = '')", codes[0].UserID).
+			Updates(map[string]any{"external_id": req.ExternalID, "user_type": req.Provider}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.First(&user, codes[0].UserID).Error
+	})
+	if err != nil {
+		return model.UserEntity{}, err
+	}
+
+	return toModelUserEntity(user), nil
+}
+
+func (u UserRepository) DeleteExpiredLinkCodes(ctx context.Context) error {
+	return conn(ctx, u.db).
+		Where("expire_at <= now()").
+		Delete(&LinkCodeEntity{}).Error
+}
+
+// GetActiveAccount returns the account the identity has switched to, which is the user of the identity
+// unless it has switched to one of the accounts it owns.
+func (u UserRepository) GetActiveAccount(ctx context.Context, provider, externalID string) (model.UserEntity, error) {
+	var user UserEntity
+
+	err := conn(ctx, u.db).
+		Joins(`JOIN "user_identity" ON coalesce("user_identity".active_user_id, "user_identity".user_id) = "user".id`).
+		Where(`"user_identity".provider = ? AND "user_identity".external_id = ?`, provider, externalID).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.UserEntity{}, errorext.NewNotFoundError(errorext.ErrUserNotFound)
+		}
+
+		return model.UserEntity{}, err
+	}
+
+	return toModelUserEntity(user), nil
+}
+
+func (u UserRepository) SetActiveAccount(ctx context.Context, provider, externalID string, userID int) error {
+	return conn(ctx, u.db).
+		Model(&UserIdentityEntity{}).
+		Where("provider = ? and external_id = ?", provider, externalID).
+		UpdateColumn("active_user_id", userID).Error
+}
+
+// GetOwnedAccounts returns the owner along with the accounts it owns, the owner comes first.
+func (u UserRepository) GetOwnedAccounts(ctx context.Context, ownerID int) ([]model.UserEntity, error) {
+	var users []UserEntity
+
+	err := conn(ctx, u.db).
+		Where("(id = ? or owner_id = ?) and deleted_at is null", ownerID, ownerID).
+		Order("id").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelUserEntities(users), nil
+}
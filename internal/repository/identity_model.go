@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type UserIdentityEntity struct {
+	ID           int
+	UserID       int
+	Provider     string
+	ExternalID   string
+	ActiveUserID *int
+	CreatedAt    time.Time
+}
+
+func (UserIdentityEntity) TableName() string {
+	return "user_identity"
+}
+
+type LinkCodeEntity struct {
+	CodeHash  string `gorm:"primaryKey"`
+	UserID    int
+	ExpireAt  time.Time
+	CreatedAt time.Time
+}
+
+func (LinkCodeEntity) TableName() string {
+	return "link_code"
+}
+
+func toModelUserIdentityEntity(req UserIdentityEntity) model.UserIdentityEntity {
+	return model.UserIdentityEntity{
+		ID:         req.ID,
+		UserID:     req.UserID,
+		Provider:   req.Provider,
+		ExternalID: req.ExternalID,
+		CreatedAt:  req.CreatedAt,
+	}
+}
+
+func toModelUserIdentityEntities(req []UserIdentityEntity) []model.UserIdentityEntity {
+	result := make([]model.UserIdentityEntity, 0, len(req))
+
+	for _, identity := range req {
+		result = append(result, toModelUserIdentityEntity(identity))
+	}
+
+	return result
+}
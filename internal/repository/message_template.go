@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type MessageTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewMessageTemplateRepository(db *gorm.DB) *MessageTemplateRepository {
+	return &MessageTemplateRepository{db: db}
+}
+
+func (m MessageTemplateRepository) GetMessageTemplates(ctx context.Context) ([]model.MessageTemplateEntity, error) {
+	var templates []MessageTemplateEntity
+
+	if err := conn(ctx, m.db).Order("key, language").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+
+	return toModelMessageTemplateEntities(templates), nil
+}
+
+// SaveMessageTemplate creates the template of the message in the language, or replaces it if there is one.
+func (m MessageTemplateRepository) SaveMessageTemplate(ctx context.Context, req model.SaveMessageTemplateRequest) (int, error) {
+	tmpl := MessageTemplateEntity{
+		Key:       req.Key,
+		Language:  req.Language,
+		ParseMode: req.ParseMode,
+		Body:      req.Body,
+		UpdatedBy: req.Actor,
+		UpdatedAt: time.Now(),
+	}
+
+	err := conn(ctx, m.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}, {Name: "language"}},
+			DoUpdates: clause.AssignmentColumns([]string{"parse_mode", "body", "updated_by", "updated_at"}),
+		}, clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Create(&tmpl).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return tmpl.ID, nil
+}
+
+func (m MessageTemplateRepository) DeleteMessageTemplate(ctx context.Context, key, language string) error {
+	return conn(ctx, m.db).
+		Where("key = ? and language = ?", key, language).
+		Delete(&MessageTemplateEntity{}).Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type MessageTemplateEntity struct {
+	ID        int
+	Key       string
+	Language  string
+	ParseMode string
+	Body      string
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+func (MessageTemplateEntity) TableName() string {
+	return "message_template"
+}
+
+func toModelMessageTemplateEntity(req MessageTemplateEntity) model.MessageTemplateEntity {
+	return model.MessageTemplateEntity{
+		ID:        req.ID,
+		Key:       req.Key,
+		Language:  req.Language,
+		ParseMode: req.ParseMode,
+		Body:      req.Body,
+		UpdatedBy: req.UpdatedBy,
+		UpdatedAt: req.UpdatedAt,
+	}
+}
+
+func toModelMessageTemplateEntities(templates []MessageTemplateEntity) []model.MessageTemplateEntity {
+	result := make([]model.MessageTemplateEntity, 0, len(templates))
+
+	for _, tmpl := range templates {
+		result = append(result, toModelMessageTemplateEntity(tmpl))
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateNotification records the notification, it returns false if it has already been recorded.
+func (n NotificationRepository) CreateNotification(ctx context.Context, req model.CreateNotificationRequest) (bool, error) {
+	notification := toNotificationEntity(req)
+
+	result := conn(ctx, n.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "kind"}, {Name: "reference"}},
+			DoNothing: true,
+		}).
+		Create(&notification)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (n NotificationRepository) DeleteNotification(ctx context.Context, req model.CreateNotificationRequest) error {
+	return conn(ctx, n.db).
+		Where("user_id = ? and kind = ? and reference = ?", req.UserID, req.Kind, req.Reference).
+		Delete(&NotificationEntity{}).Error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type NotificationEntity struct {
+	ID        int
+	UserID    int
+	Kind      string
+	Reference string
+	CreatedAt time.Time
+}
+
+func (NotificationEntity) TableName() string {
+	return "notification"
+}
+
+func toNotificationEntity(req model.CreateNotificationRequest) NotificationEntity {
+	return NotificationEntity{
+		UserID:    req.UserID,
+		Kind:      req.Kind,
+		Reference: req.Reference,
+	}
+}
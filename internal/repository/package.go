@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
 	"github.com/alir32a/jupiter/internal/model"
 	"golang.org/x/exp/maps"
 	"gorm.io/gorm"
@@ -17,27 +18,55 @@ func NewPackageRepository(db *gorm.DB) *PackageRepository {
 	return &PackageRepository{db: db}
 }
 
-func (p PackageRepository) CreatePackage(ctx context.Context, req model.CreatePackageRequest) error {
+func (p PackageRepository) CreatePackage(ctx context.Context, req model.CreatePackageRequest) (model.PackageEntity, error) {
 	pack := PackageEntity{
 		UserID:           req.UserID,
+		PackageType:      req.PackageType,
 		TrafficLimit:     req.Traffic,
 		MaxConnections:   req.MaxConnections,
 		IsTrial:          req.IsTrial,
 		ExpirationInDays: req.ExpirationInDays,
+		FairUseCap:       req.FairUseCap,
+		ThrottleRate:     req.ThrottleRate,
 	}
 
-	activePack, err := p.getUserActivePackage(ctx, req.UserID)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
+	if req.PlanID > 0 {
+		pack.PlanID = &req.PlanID
+	}
+
+	if pack.PackageType == "" {
+		pack.PackageType = model.PackageTypeStandard
 	}
 
-	if activePack.ID != 0 {
-		expireAt := time.Now().Add(time.Duration(req.ExpirationInDays) * 24 * time.Hour)
+	var usablePackages int64
+
+	err := conn(ctx, p.db).
+		Model(&PackageEntity{}).
+		Scopes(UsablePackages).
+		Where("user_id = ?", req.UserID).
+		Count(&usablePackages).Error
+	if err != nil {
+		return model.PackageEntity{}, err
+	}
+
+	// the package is activated right away only if it's the only package of the user, otherwise it's reserved
+	// and the services activate the user's packages in their consumption order.
+	if usablePackages == 0 {
+		now := time.Now()
+		pack.ActivatedAt = &now
+
+		if pack.PackageType != model.PackageTypeTrafficOnly {
+			expireAt := now.Add(time.Duration(pack.ExpirationInDays) * 24 * time.Hour)
+
+			pack.ExpireAt = &expireAt
+		}
+	}
 
-		pack.ExpireAt = &expireAt
+	if err := conn(ctx, p.db).Create(&pack).Error; err != nil {
+		return model.PackageEntity{}, err
 	}
 
-	return p.db.WithContext(ctx).Create(&pack).Error
+	return toModelPackageEntity(pack), nil
 }
 
 func (p PackageRepository) GetUsersActiveAndReservedPackages(ctx context.Context, userIDs ...int) (model.GetUsersActivePackagesResponse, error) {
@@ -46,11 +75,11 @@ func (p PackageRepository) GetUsersActiveAndReservedPackages(ctx context.Context
 		packMap  = map[int]model.GetUserPackages{}
 	)
 
-	err := p.db.
-		WithContext(ctx).
-		Raw(`with packages as (select * from package 
-			 where download_traffic_usage + upload_traffic_usage < traffic_limit and (expire_at > now() or expire_at is null)) 
-			 select * from packages where user_id in ?`, userIDs).Scan(&packages).Error
+	err := conn(ctx, p.db).
+		Model(&PackageEntity{}).
+		Scopes(UsablePackages).
+		Where("user_id in ?", userIDs).
+		Find(&packages).Error
 	if err != nil {
 		return model.GetUsersActivePackagesResponse{}, err
 	}
@@ -59,13 +88,12 @@ func (p PackageRepository) GetUsersActiveAndReservedPackages(ctx context.Context
 		userPacks := packMap[pack.UserID]
 		userPacks.UserID = pack.UserID
 
-		if pack.ExpireAt != nil {
+		if pack.ActivatedAt != nil {
 			userPacks.ActivePackage = toModelPackageEntity(pack)
-
-			continue
+		} else {
+			userPacks.ReservedPackages = append(userPacks.ReservedPackages, toModelPackageEntity(pack))
 		}
 
-		userPacks.ReservedPackages = append(userPacks.ReservedPackages, toModelPackageEntity(pack))
 		packMap[pack.UserID] = userPacks
 	}
 
@@ -78,10 +106,9 @@ func (p PackageRepository) GetUserActiveAndReservedPackages(ctx context.Context,
 		result   model.GetUserPackages
 	)
 
-	err := p.db.
-		WithContext(ctx).
+	err := conn(ctx, p.db).
 		Model(&PackageEntity{}).
-		Where("download_traffic_usage + upload_traffic_usage < traffic_limit and (expire_at > now() or expire_at is null)").
+		Scopes(UsablePackages).
 		Where("user_id = ?", userID).
 		Scan(&packages).Error
 	if err != nil {
@@ -91,7 +118,7 @@ func (p PackageRepository) GetUserActiveAndReservedPackages(ctx context.Context,
 	for _, pack := range packages {
 		result.UserID = pack.UserID
 
-		if pack.ExpireAt != nil {
+		if pack.ActivatedAt != nil {
 			result.ActivePackage = toModelPackageEntity(pack)
 
 			continue
@@ -103,20 +130,24 @@ func (p PackageRepository) GetUserActiveAndReservedPackages(ctx context.Context,
 	return result, nil
 }
 
-func (p PackageRepository) getUserActivePackage(ctx context.Context, userID int) (PackageEntity, error) {
-	var pack PackageEntity
+// GetExpiringPackages returns the active packages that expire before the given time.
+func (p PackageRepository) GetExpiringPackages(ctx context.Context, before time.Time) ([]model.PackageEntity, error) {
+	var packages []PackageEntity
 
-	return pack, p.db.
-		WithContext(ctx).
+	err := conn(ctx, p.db).
 		Model(&PackageEntity{}).
-		Where("user_id = ?", userID).
-		Where("download_traffic_usage + upload_traffic_usage < traffic_limit and expire_at > now()").
-		First(&pack).Error
+		Scopes(UsablePackages).
+		Where("activated_at is not null and expire_at < ?", before).
+		Find(&packages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelPackageEntities(packages), nil
 }
 
 func (p PackageRepository) UpdateTrafficUsage(ctx context.Context, req model.UpdateTrafficUsageRequest) error {
-	err := p.db.
-		WithContext(ctx).
+	err := conn(ctx, p.db).
 		Model(&PackageEntity{}).
 		Where("id = ?", req.ID).
 		UpdateColumn("download_traffic_usage", gorm.Expr("download_traffic_usage + ?", req.DownloadTrafficUsage)).
@@ -128,10 +159,65 @@ func (p PackageRepository) UpdateTrafficUsage(ctx context.Context, req model.Upd
 	return nil
 }
 
+// ActivatePackage marks a reserved package as active, its expiry date starts counting from now.
+func (p PackageRepository) ActivatePackage(ctx context.Context, id int) error {
+	return conn(ctx, p.db).
+		Model(&PackageEntity{}).
+		Where("id = ? and activated_at is null", id).
+		UpdateColumns(map[string]any{
+			"activated_at": gorm.Expr("now()"),
+			"expire_at": gorm.Expr("case when package_type = ? then null else now() + make_interval(days => expiration_in_days) end",
+				model.PackageTypeTrafficOnly),
+		}).Error
+}
+
+func (p PackageRepository) ExtendPackage(ctx context.Context, req model.ExtendPackageRequest) error {
+	return conn(ctx, p.db).
+		Model(&PackageEntity{}).
+		Where("id = ?", req.ID).
+		UpdateColumns(map[string]any{
+			"traffic_limit":      gorm.Expr("traffic_limit + ?", req.Traffic),
+			"expiration_in_days": gorm.Expr("expiration_in_days + ?", req.Days),
+			"expire_at":          gorm.Expr("expire_at + make_interval(days => ?)", req.Days),
+		}).Error
+}
+
+func (p PackageRepository) GetPackageByID(ctx context.Context, id int) (model.PackageEntity, error) {
+	var pack PackageEntity
+
+	err := conn(ctx, p.db).First(&pack, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.PackageEntity{}, errorext.NewNotFoundError(errorext.ErrPackageNotFound)
+		}
+
+		return model.PackageEntity{}, err
+	}
+
+	return toModelPackageEntity(pack), nil
+}
+
+func (p PackageRepository) TransferPackage(ctx context.Context, id, userID int) error {
+	return conn(ctx, p.db).
+		Model(&PackageEntity{}).
+		Where("id = ?", id).
+		UpdateColumn("user_id", userID).Error
+}
+
+// CancelPackage cancels the package, it returns false if the package has already been canceled.
+func (p PackageRepository) CancelPackage(ctx context.Context, id int) (bool, error) {
+	result := conn(ctx, p.db).
+		Model(&PackageEntity{}).
+		Where("id = ? and canceled_at is null", id).
+		UpdateColumn("canceled_at", time.Now())
+
+	return result.RowsAffected == 1, result.Error
+}
+
 func (p PackageRepository) GetPackages(ctx context.Context, req model.GetPackagesRequest) (model.GetPackagesResponse, error) {
 	var packages []PackageEntity
 
-	query := p.db.WithContext(ctx).Model(&PackageEntity{})
+	query := conn(ctx, p.db).Model(&PackageEntity{})
 
 	if req.UserID > 0 {
 		query = query.Where("user_id = ?", req.UserID)
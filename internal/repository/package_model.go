@@ -8,13 +8,19 @@ import (
 type PackageEntity struct {
 	ID                   int
 	UserID               int
+	PlanID               *int
+	PackageType          string
 	TrafficLimit         int
 	DownloadTrafficUsage int
 	UploadTrafficUsage   int
 	MaxConnections       int
 	IsTrial              bool
 	ExpirationInDays     int
+	FairUseCap           int
+	ThrottleRate         int
+	ActivatedAt          *time.Time
 	ExpireAt             *time.Time
+	CanceledAt           *time.Time
 	CreatedAt            time.Time
 }
 
@@ -26,12 +32,19 @@ func toModelPackageEntity(req PackageEntity) model.PackageEntity {
 	return model.PackageEntity{
 		ID:                   req.ID,
 		UserID:               req.UserID,
+		PlanID:               req.PlanID,
+		PackageType:          req.PackageType,
 		TrafficLimit:         req.TrafficLimit,
 		DownloadTrafficUsage: req.DownloadTrafficUsage,
 		UploadTrafficUsage:   req.UploadTrafficUsage,
 		MaxConnections:       req.MaxConnections,
+		IsTrial:              req.IsTrial,
 		ExpirationInDays:     req.ExpirationInDays,
+		FairUseCap:           req.FairUseCap,
+		ThrottleRate:         req.ThrottleRate,
+		ActivatedAt:          req.ActivatedAt,
 		ExpireAt:             req.ExpireAt,
+		CanceledAt:           req.CanceledAt,
 		CreatedAt:            req.CreatedAt,
 	}
 }
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+)
+
+type PlanRepository struct {
+	db *gorm.DB
+}
+
+func NewPlanRepository(db *gorm.DB) *PlanRepository {
+	return &PlanRepository{db: db}
+}
+
+func (p PlanRepository) CreatePlan(ctx context.Context, req model.CreatePlanRequest) error {
+	return conn(ctx, p.db).Create(&PlanEntity{
+		BotID:            req.BotID,
+		Name:             req.Name,
+		PackageType:      req.PackageType,
+		TrafficLimit:     req.TrafficLimit,
+		MaxConnections:   req.MaxConnections,
+		ExpirationInDays: req.ExpirationInDays,
+		FairUseCap:       req.FairUseCap,
+		ThrottleRate:     req.ThrottleRate,
+		Price:            req.Price,
+		IsActive:         true,
+	}).Error
+}
+
+func (p PlanRepository) GetPlanByID(ctx context.Context, id int) (model.PlanEntity, error) {
+	var plan PlanEntity
+
+	err := conn(ctx, p.db).First(&plan, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.PlanEntity{}, errorext.NewNotFoundError(errorext.ErrPlanNotFound)
+		}
+
+		return model.PlanEntity{}, err
+	}
+
+	return toModelPlanEntity(plan), nil
+}
+
+func (p PlanRepository) GetPlans(ctx context.Context, req model.GetPlansRequest) (model.GetPlansResponse, error) {
+	var plans []PlanEntity
+
+	query := conn(ctx, p.db).Model(&PlanEntity{})
+
+	if req.BotID != nil {
+		query = query.Where("bot_id = ?", *req.BotID)
+	}
+
+	if req.ActiveOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	if err := query.Scopes(Paginate(&req.Pagination)).Order("created_at desc").Find(&plans).Error; err != nil {
+		return model.GetPlansResponse{}, err
+	}
+
+	return model.GetPlansResponse{
+		Plans:      toModelPlanEntities(plans),
+		Pagination: req.Pagination,
+	}, nil
+}
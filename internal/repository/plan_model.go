@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type PlanEntity struct {
+	ID               int
+	BotID            int
+	Name             string
+	PackageType      string
+	TrafficLimit     int
+	MaxConnections   int
+	ExpirationInDays int
+	FairUseCap       int
+	ThrottleRate     int
+	Price            int
+	IsActive         bool
+	CreatedAt        time.Time
+}
+
+func (PlanEntity) TableName() string {
+	return "plan"
+}
+
+func toModelPlanEntity(req PlanEntity) model.PlanEntity {
+	return model.PlanEntity{
+		ID:               req.ID,
+		BotID:            req.BotID,
+		Name:             req.Name,
+		PackageType:      req.PackageType,
+		TrafficLimit:     req.TrafficLimit,
+		MaxConnections:   req.MaxConnections,
+		ExpirationInDays: req.ExpirationInDays,
+		FairUseCap:       req.FairUseCap,
+		ThrottleRate:     req.ThrottleRate,
+		Price:            req.Price,
+		IsActive:         req.IsActive,
+		CreatedAt:        req.CreatedAt,
+	}
+}
+
+func toModelPlanEntities(req []PlanEntity) []model.PlanEntity {
+	result := make([]model.PlanEntity, 0, len(req))
+
+	for _, plan := range req {
+		result = append(result, toModelPlanEntity(plan))
+	}
+
+	return result
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReferralRepository struct {
+	db *gorm.DB
+}
+
+func NewReferralRepository(db *gorm.DB) *ReferralRepository {
+	return &ReferralRepository{db: db}
+}
+
+func (r ReferralRepository) SaveInvite(ctx context.Context, externalID, referralCode string) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "external_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"referral_code", "created_at"}),
+	}).Create(&ReferralInviteEntity{ExternalID: externalID, ReferralCode: referralCode}).Error
+}
+
+func (r ReferralRepository) GetInvite(ctx context.Context, externalID string) (model.ReferralInviteEntity, error) {
+	var invite ReferralInviteEntity
+
+	err := conn(ctx, r.db).First(&invite, "external_id = ?", externalID).Error
+	if err != nil {
+		return model.ReferralInviteEntity{}, err
+	}
+
+	return toModelReferralInviteEntity(invite), nil
+}
+
+// CreateReward records the reward of a referee, it returns false if the referee has already been rewarded.
+func (r ReferralRepository) CreateReward(ctx context.Context, req model.CreateReferralRewardRequest) (bool, error) {
+	reward := toReferralRewardEntity(req)
+
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "referee_id"}}, DoNothing: true}).
+		Create(&reward)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r ReferralRepository) GetReferralStats(ctx context.Context, user model.UserEntity) (model.ReferralStats, error) {
+	var invited int64
+
+	result := model.ReferralStats{
+		ReferralCode:  user.ReferralCode,
+		WalletBalance: user.WalletBalance,
+	}
+
+	err := conn(ctx, r.db).
+		Model(&UserEntity{}).
+		Where("referral = ?", user.ReferralCode).
+		Count(&invited).Error
+	if err != nil {
+		return model.ReferralStats{}, err
+	}
+	result.InvitedUsers = int(invited)
+
+	err = conn(ctx, r.db).
+		Model(&ReferralRewardEntity{}).
+		Select(`count(*) as rewarded_users, coalesce(sum(referrer_traffic), 0) as total_traffic, 
+			coalesce(sum(referrer_days), 0) as total_days, coalesce(sum(referrer_credit), 0) as total_credit`).
+		Where("referrer_id = ?", user.ID).
+		Scan(&result).Error
+	if err != nil {
+		return model.ReferralStats{}, err
+	}
+
+	return result, nil
+}
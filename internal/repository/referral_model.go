@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type ReferralInviteEntity struct {
+	ExternalID   string
+	ReferralCode string
+	CreatedAt    time.Time
+}
+
+func (ReferralInviteEntity) TableName() string {
+	return "referral_invite"
+}
+
+type ReferralRewardEntity struct {
+	ID              int
+	ReferrerID      int
+	RefereeID       int
+	ReferrerTraffic int
+	ReferrerDays    int
+	ReferrerCredit  int
+	RefereeTraffic  int
+	RefereeDays     int
+	RefereeCredit   int
+	CreatedAt       time.Time
+}
+
+func (ReferralRewardEntity) TableName() string {
+	return "referral_reward"
+}
+
+func toModelReferralInviteEntity(req ReferralInviteEntity) model.ReferralInviteEntity {
+	return model.ReferralInviteEntity{
+		ExternalID:   req.ExternalID,
+		ReferralCode: req.ReferralCode,
+		CreatedAt:    req.CreatedAt,
+	}
+}
+
+func toReferralRewardEntity(req model.CreateReferralRewardRequest) ReferralRewardEntity {
+	return ReferralRewardEntity{
+		ReferrerID:      req.ReferrerID,
+		RefereeID:       req.RefereeID,
+		ReferrerTraffic: req.ReferrerReward.Traffic,
+		ReferrerDays:    req.ReferrerReward.Days,
+		ReferrerCredit:  req.ReferrerReward.Credit,
+		RefereeTraffic:  req.RefereeReward.Traffic,
+		RefereeDays:     req.RefereeReward.Days,
+		RefereeCredit:   req.RefereeReward.Credit,
+	}
+}
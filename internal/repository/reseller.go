@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ResellerRepository struct {
+	db *gorm.DB
+}
+
+func NewResellerRepository(db *gorm.DB) *ResellerRepository {
+	return &ResellerRepository{db: db}
+}
+
+func (r ResellerRepository) CreateReseller(ctx context.Context, req model.CreateResellerRequest) (model.AdminEntity, error) {
+	reseller := AdminEntity{
+		Username:       req.Username,
+		Password:       req.Password,
+		Role:           model.AdminRoleReseller,
+		UserQuota:      req.UserQuota,
+		TrafficQuota:   req.TrafficQuota,
+		CommissionRate: req.CommissionRate,
+		IsActive:       true,
+	}
+
+	if err := conn(ctx, r.db).Create(&reseller).Error; err != nil {
+		return model.AdminEntity{}, err
+	}
+
+	return toModelAdminEntity(reseller), nil
+}
+
+func (r ResellerRepository) GetResellerByID(ctx context.Context, id int) (model.AdminEntity, error) {
+	var reseller AdminEntity
+
+	err := conn(ctx, r.db).Where("role = ?", model.AdminRoleReseller).First(&reseller, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.AdminEntity{}, errorext.NewNotFoundError(errorext.ErrResellerNotFound)
+		}
+
+		return model.AdminEntity{}, err
+	}
+
+	return toModelAdminEntity(reseller), nil
+}
+
+// GetResellerByTokenHash returns the active reseller the api token was issued for.
+func (r ResellerRepository) GetResellerByTokenHash(ctx context.Context, hash string) (model.AdminEntity, error) {
+	var reseller AdminEntity
+
+	err := conn(ctx, r.db).
+		Where("role = ? and is_active and api_token_hash = ?", model.AdminRoleReseller, hash).
+		First(&reseller).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.AdminEntity{}, errorext.NewNotFoundError(errorext.ErrInvalidAPIToken)
+		}
+
+		return model.AdminEntity{}, err
+	}
+
+	return toModelAdminEntity(reseller), nil
+}
+
+func (r ResellerRepository) GetResellers(ctx context.Context, req model.GetResellersRequest) (model.GetResellersResponse, error) {
+	var resellers []AdminEntity
+
+	err := conn(ctx, r.db).
+		Model(&AdminEntity{}).
+		Where("role = ?", model.AdminRoleReseller).
+		Scopes(Paginate(&req.Pagination)).
+		Order("created_at desc").
+		Find(&resellers).Error
+	if err != nil {
+		return model.GetResellersResponse{}, err
+	}
+
+	return model.GetResellersResponse{
+		Resellers:  toModelAdminEntities(resellers),
+		Pagination: req.Pagination,
+	}, nil
+}
+
+func (r ResellerRepository) SetAPITokenHash(ctx context.Context, id int, hash string) error {
+	return conn(ctx, r.db).
+		Model(&AdminEntity{}).
+		Where("id = ? and role = ?", id, model.AdminRoleReseller).
+		UpdateColumn("api_token_hash", hash).Error
+}
+
+func (r ResellerRepository) AddQuota(ctx context.Context, id, users, traffic int) error {
+	return conn(ctx, r.db).
+		Model(&AdminEntity{}).
+		Where("id = ? and role = ?", id, model.AdminRoleReseller).
+		UpdateColumns(map[string]any{
+			"user_quota":    gorm.Expr("user_quota + ?", users),
+			"traffic_quota": gorm.Expr("traffic_quota + ?", traffic),
+		}).Error
+}
+
+// ReserveUser takes a user from the reseller's quota, it's false if the quota is used up.
+func (r ResellerRepository) ReserveUser(ctx context.Context, id int) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&AdminEntity{}).
+		Where("id = ? and role = ? and used_users < user_quota", id, model.AdminRoleReseller).
+		UpdateColumn("used_users", gorm.Expr("used_users + 1"))
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r ResellerRepository) ReleaseUser(ctx context.Context, id int) error {
+	return conn(ctx, r.db).
+		Model(&AdminEntity{}).
+		Where("id = ? and used_users > 0", id).
+		UpdateColumn("used_users", gorm.Expr("used_users - 1")).Error
+}
+
+// ReserveTraffic takes the traffic from the reseller's quota, it's false if there isn't enough traffic left.
+func (r ResellerRepository) ReserveTraffic(ctx context.Context, id, traffic int) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&AdminEntity{}).
+		Where("id = ? and role = ? and used_traffic + ? <= traffic_quota", id, model.AdminRoleReseller, traffic).
+		UpdateColumn("used_traffic", gorm.Expr("used_traffic + ?", traffic))
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r ResellerRepository) ReleaseTraffic(ctx context.Context, id, traffic int) error {
+	return conn(ctx, r.db).
+		Model(&AdminEntity{}).
+		Where("id = ?", id).
+		UpdateColumn("used_traffic", gorm.Expr("greatest(used_traffic - ?, 0)", traffic)).Error
+}
+
+func (r ResellerRepository) CreateCommission(ctx context.Context, req model.CreateResellerCommissionRequest) error {
+	return conn(ctx, r.db).Create(&ResellerCommissionEntity{
+		ResellerID: req.ResellerID,
+		UserID:     req.UserID,
+		PlanID:     req.PlanID,
+		Amount:     req.Amount,
+		Note:       req.Note,
+	}).Error
+}
+
+func (r ResellerRepository) GetCommissions(ctx context.Context, req model.GetResellerCommissionsRequest) (model.GetResellerCommissionsResponse, error) {
+	var commissions []ResellerCommissionEntity
+
+	query := conn(ctx, r.db).Model(&ResellerCommissionEntity{}).Where("reseller_id = ?", req.ResellerID)
+
+	err := query.Scopes(Paginate(&req.Pagination)).Order("created_at desc").Find(&commissions).Error
+	if err != nil {
+		return model.GetResellerCommissionsResponse{}, err
+	}
+
+	balance, err := r.GetCommissionBalance(ctx, req.ResellerID)
+	if err != nil {
+		return model.GetResellerCommissionsResponse{}, err
+	}
+
+	return model.GetResellerCommissionsResponse{
+		Commissions: toModelResellerCommissionEntities(commissions),
+		Balance:     balance,
+		Pagination:  req.Pagination,
+	}, nil
+}
+
+// LockReseller locks the reseller's row until the end of the transaction of the context, so the changes
+// to their commissions don't race each other.
+func (r ResellerRepository) LockReseller(ctx context.Context, id int) error {
+	var reseller AdminEntity
+
+	return conn(ctx, r.db).
+		Select("id").
+		Where("role = ?", model.AdminRoleReseller).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&reseller, id).Error
+}
+
+// GetCommissionBalance returns the commission that isn't paid out yet.
+func (r ResellerRepository) GetCommissionBalance(ctx context.Context, resellerID int) (int, error) {
+	var balance int
+
+	err := conn(ctx, r.db).
+		Model(&ResellerCommissionEntity{}).
+		Select("coalesce(sum(amount), 0)").
+		Where("reseller_id = ?", resellerID).
+		Scan(&balance).Error
+
+	return balance, err
+}
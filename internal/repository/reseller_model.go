@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type ResellerCommissionEntity struct {
+	ID         int
+	ResellerID int
+	UserID     *int
+	PlanID     *int
+	Amount     int
+	Note       string
+	CreatedAt  time.Time
+}
+
+func (ResellerCommissionEntity) TableName() string {
+	return "reseller_commission"
+}
+
+func toModelResellerCommissionEntity(req ResellerCommissionEntity) model.ResellerCommissionEntity {
+	return model.ResellerCommissionEntity{
+		ID:         req.ID,
+		ResellerID: req.ResellerID,
+		UserID:     req.UserID,
+		PlanID:     req.PlanID,
+		Amount:     req.Amount,
+		Note:       req.Note,
+		CreatedAt:  req.CreatedAt,
+	}
+}
+
+func toModelResellerCommissionEntities(req []ResellerCommissionEntity) []model.ResellerCommissionEntity {
+	result := make([]model.ResellerCommissionEntity, 0, len(req))
+
+	for _, commission := range req {
+		result = append(result, toModelResellerCommissionEntity(commission))
+	}
+
+	return result
+}
@@ -29,3 +29,16 @@ func Paginate(req *model.Pagination) func(tx *gorm.DB) *gorm.DB {
 		return tx.Offset(offset).Limit(req.PageSize)
 	}
 }
+
+// UsablePackages filters packages that still have traffic left and are neither expired nor canceled,
+// it includes both active and reserved packages. unlimited and fair use packages never run out of traffic
+// and traffic only packages never get an expiry date.
+func UsablePackages(tx *gorm.DB) *gorm.DB {
+	return tx.Where(`(package.package_type = ? or (`+fairUsePackage+`) or 
+		package.download_traffic_usage + package.upload_traffic_usage < package.traffic_limit) and 
+		(package.expire_at > now() or package.expire_at is null) and package.canceled_at is null`,
+		model.PackageTypeUnlimited)
+}
+
+// fairUsePackage matches the packages model.PackageEntity.HasFairUse reports as fair use packages.
+const fairUsePackage = "package.fair_use_cap > 0 and package.throttle_rate > 0"
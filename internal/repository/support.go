@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SupportRepository struct {
+	db *gorm.DB
+}
+
+func NewSupportRepository(db *gorm.DB) *SupportRepository {
+	return &SupportRepository{db: db}
+}
+
+// CreateUserMessage adds the message to the chat's ticket that isn't closed yet, or opens a new ticket if
+// there isn't any, chats of different bots have separate tickets.
+func (s SupportRepository) CreateUserMessage(ctx context.Context, req model.CreateSupportMessageRequest) (model.CreateSupportMessageResponse, error) {
+	var (
+		ticket  SupportTicketEntity
+		message SupportMessageEntity
+	)
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("bot_id = ? and chat_id = ? and status <> ?", req.BotID, req.ChatID, model.SupportTicketStatusClosed).
+			Order("id desc").
+			First(&ticket).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if ticket.ID == 0 {
+			ticket = SupportTicketEntity{
+				UserID: req.UserID,
+				BotID:  req.BotID,
+				ChatID: req.ChatID,
+				Status: model.SupportTicketStatusOpen,
+			}
+
+			if err := tx.Create(&ticket).Error; err != nil {
+				return err
+			}
+		}
+
+		message = SupportMessageEntity{
+			TicketID: ticket.ID,
+			Sender:   model.SupportSenderUser,
+			Text:     req.Text,
+		}
+
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+
+		ticket.Status = model.SupportTicketStatusOpen
+
+		return tx.Model(&ticket).UpdateColumns(map[string]any{
+			"status":     ticket.Status,
+			"updated_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return model.CreateSupportMessageResponse{}, err
+	}
+
+	return model.CreateSupportMessageResponse{
+		Ticket:  toModelSupportTicketEntity(ticket),
+		Message: toModelSupportMessageEntity(message),
+	}, nil
+}
+
+// CreateAdminMessage adds the admin's reply to the ticket and marks it as answered.
+func (s SupportRepository) CreateAdminMessage(ctx context.Context, req model.ReplySupportTicketRequest) (model.SupportMessageEntity, error) {
+	message := SupportMessageEntity{
+		TicketID:      req.TicketID,
+		Sender:        model.SupportSenderAdmin,
+		AdminUsername: req.AdminUsername,
+		Text:          req.Text,
+	}
+
+	err := conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+
+		return tx.
+			Model(&SupportTicketEntity{}).
+			Where("id = ?", req.TicketID).
+			UpdateColumns(map[string]any{
+				"status":     model.SupportTicketStatusAnswered,
+				"updated_at": time.Now(),
+			}).Error
+	})
+	if err != nil {
+		return model.SupportMessageEntity{}, err
+	}
+
+	return toModelSupportMessageEntity(message), nil
+}
+
+// SetForwardedMessage records the id of the message's copy in the support chat, admins reply to that copy.
+func (s SupportRepository) SetForwardedMessage(ctx context.Context, messageID, forwardedMessageID int) error {
+	return conn(ctx, s.db).
+		Model(&SupportMessageEntity{}).
+		Where("id = ?", messageID).
+		UpdateColumn("forwarded_message_id", forwardedMessageID).Error
+}
+
+func (s SupportRepository) GetTicketByID(ctx context.Context, id int) (model.SupportTicketEntity, error) {
+	var ticket SupportTicketEntity
+
+	err := conn(ctx, s.db).
+		Scopes(withTicketUsername).
+		Where("support_ticket.id = ?", id).
+		First(&ticket).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.SupportTicketEntity{}, errorext.NewNotFoundError(errorext.ErrTicketNotFound)
+		}
+
+		return model.SupportTicketEntity{}, err
+	}
+
+	return toModelSupportTicketEntity(ticket), nil
+}
+
+func (s SupportRepository) GetTicketByForwardedMessage(ctx context.Context, forwardedMessageID int) (model.SupportTicketEntity, error) {
+	var message SupportMessageEntity
+
+	err := conn(ctx, s.db).
+		Where("forwarded_message_id = ?", forwardedMessageID).
+		First(&message).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.SupportTicketEntity{}, errorext.NewNotFoundError(errorext.ErrTicketNotFound)
+		}
+
+		return model.SupportTicketEntity{}, err
+	}
+
+	return s.GetTicketByID(ctx, message.TicketID)
+}
+
+func (s SupportRepository) GetTickets(ctx context.Context, req model.GetSupportTicketsRequest) (model.GetSupportTicketsResponse, error) {
+	var tickets []SupportTicketEntity
+
+	query := conn(ctx, s.db).Model(&SupportTicketEntity{})
+	if req.Status != "" {
+		query = query.Where("support_ticket.status = ?", req.Status)
+	}
+
+	err := query.
+		Scopes(Paginate(&req.Pagination), withTicketUsername).
+		Order("support_ticket.updated_at desc").
+		Find(&tickets).Error
+	if err != nil {
+		return model.GetSupportTicketsResponse{}, err
+	}
+
+	return model.GetSupportTicketsResponse{
+		Tickets:    toModelSupportTicketEntities(tickets),
+		Pagination: req.Pagination,
+	}, nil
+}
+
+func (s SupportRepository) GetTicketMessages(ctx context.Context, ticketID int) ([]model.SupportMessageEntity, error) {
+	var messages []SupportMessageEntity
+
+	if err := conn(ctx, s.db).Where("ticket_id = ?", ticketID).Order("id").Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	return toModelSupportMessageEntities(messages), nil
+}
+
+func (s SupportRepository) CloseTicket(ctx context.Context, id int) error {
+	now := time.Now()
+
+	return conn(ctx, s.db).
+		Model(&SupportTicketEntity{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"status":     model.SupportTicketStatusClosed,
+			"updated_at": now,
+			"closed_at":  now,
+		}).Error
+}
+
+func withTicketUsername(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Select(`support_ticket.*, "user".username`).
+		Joins(`LEFT JOIN "user" ON "user".id = support_ticket.user_id`)
+}
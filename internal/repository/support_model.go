@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"github.com/alir32a/jupiter/internal/model"
+	"time"
+)
+
+type SupportTicketEntity struct {
+	ID     int
+	UserID *int
+	// Username is joined from the user of the ticket, it's never written.
+	Username  string `gorm:"->"`
+	BotID     int
+	ChatID    string
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  *time.Time
+}
+
+func (SupportTicketEntity) TableName() string {
+	return "support_ticket"
+}
+
+type SupportMessageEntity struct {
+	ID                 int
+	TicketID           int
+	Sender             string
+	AdminUsername      string
+	Text               string
+	ForwardedMessageID *int
+	CreatedAt          time.Time
+}
+
+func (SupportMessageEntity) TableName() string {
+	return "support_message"
+}
+
+func toModelSupportTicketEntity(req SupportTicketEntity) model.SupportTicketEntity {
+	return model.SupportTicketEntity{
+		ID:        req.ID,
+		UserID:    req.UserID,
+		Username:  req.Username,
+		BotID:     req.BotID,
+		ChatID:    req.ChatID,
+		Status:    req.Status,
+		CreatedAt: req.CreatedAt,
+		UpdatedAt: req.UpdatedAt,
+		ClosedAt:  req.ClosedAt,
+	}
+}
+
+func toModelSupportTicketEntities(tickets []SupportTicketEntity) []model.SupportTicketEntity {
+	result := make([]model.SupportTicketEntity, 0, len(tickets))
+
+	for _, ticket := range tickets {
+		result = append(result, toModelSupportTicketEntity(ticket))
+	}
+
+	return result
+}
+
+func toModelSupportMessageEntity(req SupportMessageEntity) model.SupportMessageEntity {
+	return model.SupportMessageEntity{
+		ID:                 req.ID,
+		TicketID:           req.TicketID,
+		Sender:             req.Sender,
+		AdminUsername:      req.AdminUsername,
+		Text:               req.Text,
+		ForwardedMessageID: req.ForwardedMessageID,
+		CreatedAt:          req.CreatedAt,
+	}
+}
+
+func toModelSupportMessageEntities(messages []SupportMessageEntity) []model.SupportMessageEntity {
+	result := make([]model.SupportMessageEntity, 0, len(messages))
+
+	for _, message := range messages {
+		result = append(result, toModelSupportMessageEntity(message))
+	}
+
+	return result
+}
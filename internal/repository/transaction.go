@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+// Transactor runs a function in a database transaction, the repositories pick the transaction up from
+// the context, so the services can group their calls to several repositories in a single transaction.
+type Transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// WithTransaction commits the transaction if fn succeeds and rolls it back otherwise, it joins the
+// transaction of the context if there's already one.
+func (t Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+}
+
+// conn returns the transaction of the context if there's one, or db otherwise.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
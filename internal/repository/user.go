@@ -18,19 +18,41 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// CreateUser creates the user along with the identity of the account it's created from, if there's one,
+// owned accounts share the identity of their owner instead.
 func (u UserRepository) CreateUser(ctx context.Context, req model.CreateUserRequest) (model.UserEntity, error) {
 	user := UserEntity{
-		Username:     req.Username,
-		ExternalID:   req.ExternalID,
-		UserType:     req.UserType,
-		ReferralCode: req.ReferralCode,
-		Referral:     req.Referral,
+		Username:             req.Username,
+		ExternalID:           req.ExternalID,
+		UserType:             req.UserType,
+		ReferralCode:         req.ReferralCode,
+		Referral:             req.Referral,
+		NotificationsEnabled: true,
+		Language:             req.Language,
+		OwnerID:              req.OwnerID,
+		BotID:                req.BotID,
+		ResellerID:           req.ResellerID,
+		TrialPending:         req.DeferTrial,
 	}
 
-	err := u.db.
-		WithContext(ctx).
-		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
-		Create(&user).Error
+	err := conn(ctx, u.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Create(&user).Error
+		if err != nil {
+			return err
+		}
+
+		if req.ExternalID == "" || req.OwnerID != nil {
+			return nil
+		}
+
+		return tx.Create(&UserIdentityEntity{
+			UserID:     user.ID,
+			Provider:   req.UserType,
+			ExternalID: req.ExternalID,
+		}).Error
+	})
 	if err != nil {
 		return model.UserEntity{}, err
 	}
@@ -41,7 +63,7 @@ func (u UserRepository) CreateUser(ctx context.Context, req model.CreateUserRequ
 func (u UserRepository) GetUsersByUsernames(ctx context.Context, usernames ...string) ([]model.UserEntity, error) {
 	var users []UserEntity
 
-	err := u.db.WithContext(ctx).Where("username in ?", usernames).Find(&users).Error
+	err := conn(ctx, u.db).Where("username in ?", usernames).Find(&users).Error
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +74,7 @@ func (u UserRepository) GetUsersByUsernames(ctx context.Context, usernames ...st
 func (u UserRepository) GetUserByID(ctx context.Context, id int) (model.UserEntity, error) {
 	var user UserEntity
 
-	err := u.db.WithContext(ctx).First(&user, id).Error
+	err := conn(ctx, u.db).First(&user, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return model.UserEntity{}, errorext.NewNotFoundError(errorext.ErrUserNotFound)
@@ -67,7 +89,7 @@ func (u UserRepository) GetUserByID(ctx context.Context, id int) (model.UserEnti
 func (u UserRepository) GetUsersByIDs(ctx context.Context, ids []int) ([]model.UserEntity, error) {
 	var users []UserEntity
 
-	err := u.db.WithContext(ctx).Where("id in ?", ids).Find(&users).Error
+	err := conn(ctx, u.db).Where("id in ?", ids).Find(&users).Error
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +100,7 @@ func (u UserRepository) GetUsersByIDs(ctx context.Context, ids []int) ([]model.U
 func (u UserRepository) GetUserByUsername(ctx context.Context, username string) (model.UserEntity, error) {
 	var user UserEntity
 
-	err := u.db.WithContext(ctx).First(&user, "username = ?", username).Error
+	err := conn(ctx, u.db).First(&user, "username = ?", username).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return model.UserEntity{}, errorext.NewNotFoundError(errorext.ErrUserNotFound)
@@ -90,18 +112,118 @@ func (u UserRepository) GetUserByUsername(ctx context.Context, username string)
 	return toModelUserEntity(user), nil
 }
 
+func (u UserRepository) GetUserByReferralCode(ctx context.Context, referralCode string) (model.UserEntity, error) {
+	var user UserEntity
+
+	err := conn(ctx, u.db).First(&user, "referral_code = ?", referralCode).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.UserEntity{}, errorext.NewNotFoundError(errorext.ErrInvalidReferralCode)
+		}
+
+		return model.UserEntity{}, err
+	}
+
+	return toModelUserEntity(user), nil
+}
+
+func (u UserRepository) AddWalletBalance(ctx context.Context, id, amount int) error {
+	return conn(ctx, u.db).
+		Model(&UserEntity{}).
+		Where("id = ?", id).
+		UpdateColumn("wallet_balance", gorm.Expr("wallet_balance + ?", amount)).Error
+}
+
+// ClaimTrial marks the pending trial of the user as granted, it returns false if the user doesn't have one.
+func (u UserRepository) ClaimTrial(ctx context.Context, id int) (bool, error) {
+	result := conn(ctx, u.db).
+		Model(&UserEntity{}).
+		Where("id = ? and trial_pending", id).
+		UpdateColumn("trial_pending", false)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddCaptchaFailure counts a wrong captcha answer of the user, the pending trial is dropped once the user
+// reaches the max failures.
+func (u UserRepository) AddCaptchaFailure(ctx context.Context, id, maxFailures int) error {
+	return conn(ctx, u.db).
+		Model(&UserEntity{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"captcha_failures": gorm.Expr("captcha_failures + 1"),
+			"trial_pending":    gorm.Expr("trial_pending and captcha_failures + 1 < ?", maxFailures),
+		}).Error
+}
+
+// AddBonusDays holds the bonus days of a user that doesn't have any package, they're added to the next
+// package the user gets.
+func (u UserRepository) AddBonusDays(ctx context.Context, id, days int) error {
+	return conn(ctx, u.db).
+		Model(&UserEntity{}).
+		Where("id = ?", id).
+		UpdateColumn("bonus_days", gorm.Expr("bonus_days + ?", days)).Error
+}
+
+// TakeBonusDays returns the bonus days held for the user and resets them, the user's row stays locked until
+// the end of the transaction of the context.
+func (u UserRepository) TakeBonusDays(ctx context.Context, id int) (int, error) {
+	var bonusDays int
+
+	err := conn(ctx, u.db).
+		Model(&UserEntity{}).
+		Select("bonus_days").
+		Where("id = ?", id).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scan(&bonusDays).Error
+	if err != nil || bonusDays <= 0 {
+		return 0, err
+	}
+
+	err = conn(ctx, u.db).Model(&UserEntity{}).Where("id = ?", id).UpdateColumn("bonus_days", 0).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return bonusDays, nil
+}
+
 func (u UserRepository) BanUser(ctx context.Context, id int) error {
-	return u.db.WithContext(ctx).Model(&UserEntity{}).Where("id = ?", id).UpdateColumn("banned_at", time.Now()).Error
+	return conn(ctx, u.db).Model(&UserEntity{}).Where("id = ?", id).UpdateColumn("banned_at", time.Now()).Error
 }
 
 func (u UserRepository) UnbanUser(ctx context.Context, id int) error {
-	return u.db.WithContext(ctx).Model(&UserEntity{}).Where("id = ?", id).UpdateColumn("banned_at", nil).Error
+	return conn(ctx, u.db).Model(&UserEntity{}).Where("id = ?", id).UpdateColumn("banned_at", nil).Error
+}
+
+// SetNotificationsEnabled sets the notifications of the user and the accounts it owns.
+func (u UserRepository) SetNotificationsEnabled(ctx context.Context, id int, enabled bool) error {
+	return conn(ctx, u.db).
+		Model(&UserEntity{}).
+		Where("id = ? or owner_id = ?", id, id).
+		UpdateColumn("notifications_enabled", enabled).Error
+}
+
+// SetLanguage sets the language of the user and the accounts it owns.
+func (u UserRepository) SetLanguage(ctx context.Context, id int, language string) error {
+	return conn(ctx, u.db).
+		Model(&UserEntity{}).
+		Where("id = ? or owner_id = ?", id, id).
+		UpdateColumn("language", language).Error
+}
+
+func (u UserRepository) ThrottleUser(ctx context.Context, id int) error {
+	return conn(ctx, u.db).Model(&UserEntity{}).Where("id = ?", id).UpdateColumn("throttled_at", time.Now()).Error
+}
+
+func (u UserRepository) UnthrottleUser(ctx context.Context, id int) error {
+	return conn(ctx, u.db).Model(&UserEntity{}).Where("id = ?", id).UpdateColumn("throttled_at", nil).Error
 }
 
 func (u UserRepository) GetUsersStat(ctx context.Context) (model.GetUsersStatResponse, error) {
 	var result = model.GetUsersStatResponse{}
 
-	query := u.db.WithContext(ctx).Model(&UserEntity{})
+	query := conn(ctx, u.db).Model(&UserEntity{})
 
 	if err := query.Select("count(*)").Scan(&result.TotalUsers).Error; err != nil {
 		return model.GetUsersStatResponse{}, err
@@ -111,11 +233,10 @@ func (u UserRepository) GetUsersStat(ctx context.Context) (model.GetUsersStatRes
 		return model.GetUsersStatResponse{}, err
 	}
 
-	err := u.db.WithContext(ctx).Model(&UserEntity{}).
+	err := conn(ctx, u.db).Model(&UserEntity{}).
 		Select("count(distinct \"user\".id)").
 		Joins("inner join package on package.user_id = \"user\".id").
-		Where(`package.download_traffic_usage + package.upload_traffic_usage < package.traffic_limit and 
-        (package.expire_at > now() or package.expire_at is null)`).
+		Scopes(UsablePackages).
 		Scan(&result.TotalActiveUsers).Error
 	if err != nil {
 		return model.GetUsersStatResponse{}, err
@@ -127,12 +248,20 @@ func (u UserRepository) GetUsersStat(ctx context.Context) (model.GetUsersStatRes
 func (u UserRepository) GetAllUsers(ctx context.Context, req model.GetAllUsersRequest) (model.GetAllUsersResponse, error) {
 	var users []UserEntity
 
-	query := u.db.WithContext(ctx).Model(&UserEntity{})
+	query := conn(ctx, u.db).Model(&UserEntity{})
 
 	if req.Username != "" {
 		query = query.Where("username = ?", req.Username)
 	}
 
+	if req.BotID != nil {
+		query = query.Where("bot_id = ?", *req.BotID)
+	}
+
+	if req.ResellerID != nil {
+		query = query.Where("reseller_id = ?", *req.ResellerID)
+	}
+
 	err := query.Scopes(Paginate(&req.Pagination)).Order("created_at desc").Find(&users).Error
 	if err != nil {
 		return model.GetAllUsersResponse{}, err
@@ -147,7 +276,7 @@ func (u UserRepository) GetAllUsers(ctx context.Context, req model.GetAllUsersRe
 func (u UserRepository) GetTotalUsersCount(ctx context.Context) (int, error) {
 	var total int64
 
-	if err := u.db.WithContext(ctx).Model(&UserEntity{}).Count(&total).Error; err != nil {
+	if err := conn(ctx, u.db).Model(&UserEntity{}).Count(&total).Error; err != nil {
 		return 0, err
 	}
 
@@ -6,15 +6,24 @@ import (
 )
 
 type UserEntity struct {
-	ID           int
-	Username     string
-	ExternalID   string
-	UserType     string
-	ReferralCode string
-	Referral     *string
-	BannedAt     *time.Time
-	CreatedAt    time.Time
-	DeletedAt    *time.Time
+	ID                   int
+	Username             string
+	ExternalID           string
+	UserType             string
+	ReferralCode         string
+	Referral             *string
+	WalletBalance        int
+	NotificationsEnabled bool
+	Language             string
+	OwnerID              *int
+	BotID                int
+	ResellerID           *int
+	TrialPending         bool
+	CaptchaFailures      int
+	ThrottledAt          *time.Time
+	BannedAt             *time.Time
+	CreatedAt            time.Time
+	DeletedAt            *time.Time
 }
 
 func (UserEntity) TableName() string {
@@ -23,14 +32,23 @@ func (UserEntity) TableName() string {
 
 func toModelUserEntity(req UserEntity) model.UserEntity {
 	return model.UserEntity{
-		ID:           req.ID,
-		Username:     req.Username,
-		ExternalID:   req.ExternalID,
-		UserType:     req.UserType,
-		ReferralCode: req.ReferralCode,
-		Referral:     req.Referral,
-		BannedAt:     req.BannedAt,
-		CreatedAt:    req.CreatedAt,
+		ID:                   req.ID,
+		Username:             req.Username,
+		ExternalID:           req.ExternalID,
+		UserType:             req.UserType,
+		ReferralCode:         req.ReferralCode,
+		Referral:             req.Referral,
+		WalletBalance:        req.WalletBalance,
+		NotificationsEnabled: req.NotificationsEnabled,
+		Language:             req.Language,
+		OwnerID:              req.OwnerID,
+		BotID:                req.BotID,
+		ResellerID:           req.ResellerID,
+		TrialPending:         req.TrialPending,
+		CaptchaFailures:      req.CaptchaFailures,
+		ThrottledAt:          req.ThrottledAt,
+		BannedAt:             req.BannedAt,
+		CreatedAt:            req.CreatedAt,
 	}
 }
 
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"slices"
+)
+
+// GetActiveAccount returns the account the telegram user has switched to, commands about the vpn account
+// act on it.
+func (u UserService) GetActiveAccount(ctx context.Context, externalID string) (model.UserEntity, error) {
+	return u.repo.GetActiveAccount(ctx, model.IdentityProviderTelegram, externalID)
+}
+
+// GetAccounts returns the accounts of the telegram user along with the id of the active one.
+func (u UserService) GetAccounts(ctx context.Context, externalID string) (model.UserAccounts, error) {
+	owner, err := u.GetUserByExternalID(ctx, externalID)
+	if err != nil {
+		return model.UserAccounts{}, err
+	}
+
+	active, err := u.GetActiveAccount(ctx, externalID)
+	if err != nil {
+		return model.UserAccounts{}, err
+	}
+
+	accounts, err := u.repo.GetOwnedAccounts(ctx, owner.ID)
+	if err != nil {
+		return model.UserAccounts{}, errorext.NewInternalError(u.logger, err)
+	}
+
+	return model.UserAccounts{Accounts: accounts, ActiveID: active.ID}, nil
+}
+
+// GetAccount returns the account only if it belongs to the telegram user.
+func (u UserService) GetAccount(ctx context.Context, externalID string, accountID int) (model.UserEntity, error) {
+	accounts, err := u.GetAccounts(ctx, externalID)
+	if err != nil {
+		return model.UserEntity{}, err
+	}
+
+	i := slices.IndexFunc(accounts.Accounts, func(account model.UserEntity) bool { return account.ID == accountID })
+	if i < 0 {
+		return model.UserEntity{}, errorext.NewNotFoundError(errorext.ErrAccountNotFound)
+	}
+
+	return accounts.Accounts[i], nil
+}
+
+func (u UserService) SwitchAccount(ctx context.Context, externalID string, accountID int) (model.UserEntity, error) {
+	account, err := u.GetAccount(ctx, externalID, accountID)
+	if err != nil {
+		return model.UserEntity{}, err
+	}
+
+	if err := u.repo.SetActiveAccount(ctx, model.IdentityProviderTelegram, externalID, account.ID); err != nil {
+		return model.UserEntity{}, errorext.NewInternalError(u.logger, err)
+	}
+
+	return account, nil
+}
+
+// CreateAccount creates another account for the telegram user and switches to it, the account is named
+// after the user with a number.
+func (u UserService) CreateAccount(ctx context.Context, externalID string) (model.CreateUserResponse, error) {
+	accounts, err := u.GetAccounts(ctx, externalID)
+	if err != nil {
+		return model.CreateUserResponse{}, err
+	}
+
+	if len(accounts.Accounts) >= u.cfg.MainBot.MaxAccounts {
+		return model.CreateUserResponse{}, errorext.NewBadRequestError(errorext.ErrTooManyAccounts)
+	}
+
+	owner := accounts.Accounts[0]
+
+	username := fmt.Sprintf("%s-%d", owner.Username, len(accounts.Accounts)+1)
+	for i := len(accounts.Accounts) + 2; ; i++ {
+		if _, err := u.repo.GetUserByUsername(ctx, username); err != nil {
+			break
+		}
+
+		username = fmt.Sprintf("%s-%d", owner.Username, i)
+	}
+
+	resp, err := u.CreateUser(ctx, model.CreateUserRequest{
+		Username:   username,
+		ExternalID: externalID,
+		UserType:   model.UserTypeTelegram,
+		Language:   owner.Language,
+		OwnerID:    &owner.ID,
+		BotID:      owner.BotID,
+	})
+	if err != nil {
+		return model.CreateUserResponse{}, err
+	}
+
+	if err := u.repo.SetActiveAccount(ctx, model.IdentityProviderTelegram, externalID, resp.ID); err != nil {
+		return model.CreateUserResponse{}, errorext.NewInternalError(u.logger, err)
+	}
+
+	return resp, nil
+}
@@ -3,25 +3,35 @@ package service
 import (
 	"context"
 	"errors"
+	"github.com/alir32a/jupiter/config"
 	"github.com/alir32a/jupiter/internal/errorext"
 	"github.com/alir32a/jupiter/internal/model"
 	"github.com/alir32a/jupiter/pkg/password"
 	clog "github.com/charmbracelet/log"
+	"gorm.io/gorm"
+	"strings"
+	"time"
 )
 
 type AdminRepository interface {
 	CreateAdmin(ctx context.Context, req model.CreateAdminRequest) error
 	GetAdminByUsername(ctx context.Context, username string) (model.AdminEntity, error)
 	ChangePassword(ctx context.Context, username, password string) error
+	GetAdminByTelegramID(ctx context.Context, telegramID string) (model.AdminEntity, error)
+	SetTelegramID(ctx context.Context, username string, telegramID *string) error
+	SetLinkCode(ctx context.Context, username, codeHash string, expireAt time.Time) error
+	LinkTelegram(ctx context.Context, codeHash, telegramID string) (model.AdminEntity, error)
 }
 
 type AdminService struct {
+	cfg    *config.MainBotConfig
 	repo   AdminRepository
 	logger *clog.Logger
 }
 
-func NewAdminService(repo AdminRepository, logger *clog.Logger) *AdminService {
+func NewAdminService(cfg *config.MainBotConfig, repo AdminRepository, logger *clog.Logger) *AdminService {
 	return &AdminService{
+		cfg:    cfg,
 		repo:   repo,
 		logger: logger,
 	}
@@ -44,6 +54,11 @@ func (a AdminService) Login(ctx context.Context, req model.AdminLoginRequest) er
 		return errorext.NewNotFoundError(errorext.ErrUserOrPasswordIsIncorrect)
 	}
 
+	// resellers only use the reseller api with their api token.
+	if admin.IsReseller() {
+		return errorext.NewNotFoundError(errorext.ErrUserOrPasswordIsIncorrect)
+	}
+
 	if err := password.ComparePasswords(admin.Password, req.Password); err != nil {
 		return errorext.NewNotFoundError(errorext.ErrUserOrPasswordIsIncorrect)
 	}
@@ -68,3 +83,60 @@ func (a AdminService) ChangePassword(ctx context.Context, req model.ChangePasswo
 
 	return a.repo.ChangePassword(ctx, req.Username, pass)
 }
+
+// CreateLinkCode returns a one time code the admin sends to the bot to link their telegram account, so they
+// can use the admin commands of the bot.
+func (a AdminService) CreateLinkCode(ctx context.Context, username string) (model.LinkCode, error) {
+	code, err := password.NewCode(linkCodeLength)
+	if err != nil {
+		return model.LinkCode{}, errorext.NewInternalError(a.logger, err)
+	}
+
+	expireAt := time.Now().Add(a.cfg.LinkCodeTTL)
+
+	if err := a.repo.SetLinkCode(ctx, username, password.HashToken(code), expireAt); err != nil {
+		return model.LinkCode{}, errorext.NewInternalError(a.logger, err)
+	}
+
+	return model.LinkCode{Code: code, ExpireAt: expireAt}, nil
+}
+
+// LinkTelegram links the telegram account to the admin the code was created for.
+func (a AdminService) LinkTelegram(ctx context.Context, telegramID, code string) (model.AdminEntity, error) {
+	admin, err := a.repo.LinkTelegram(ctx, password.HashToken(strings.ToUpper(strings.TrimSpace(code))), telegramID)
+	if err != nil {
+		if errorext.IsNotFound(err) {
+			return model.AdminEntity{}, err
+		}
+
+		return model.AdminEntity{}, errorext.NewInternalError(a.logger, err)
+	}
+
+	return admin, nil
+}
+
+func (a AdminService) UnlinkTelegram(ctx context.Context, username string) error {
+	if err := a.repo.SetTelegramID(ctx, username, nil); err != nil {
+		return errorext.NewInternalError(a.logger, err)
+	}
+
+	return nil
+}
+
+// GetTelegramAdmin returns the active admin the telegram account is linked to.
+func (a AdminService) GetTelegramAdmin(ctx context.Context, telegramID string) (model.AdminEntity, error) {
+	admin, err := a.repo.GetAdminByTelegramID(ctx, telegramID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.AdminEntity{}, errorext.ErrNotAdmin
+		}
+
+		return model.AdminEntity{}, errorext.NewInternalError(a.logger, err)
+	}
+
+	if !admin.IsActive || admin.IsReseller() {
+		return model.AdminEntity{}, errorext.ErrNotAdmin
+	}
+
+	return admin, nil
+}
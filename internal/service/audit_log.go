@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+)
+
+type AuditLogRepository interface {
+	GetAuditLogs(ctx context.Context, req model.GetAuditLogsRequest) (model.GetAuditLogsResponse, error)
+}
+
+type AuditLogService struct {
+	repo   AuditLogRepository
+	logger *clog.Logger
+}
+
+func NewAuditLogService(repo AuditLogRepository, logger *clog.Logger) *AuditLogService {
+	return &AuditLogService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (a AuditLogService) GetAuditLogs(ctx context.Context, req model.GetAuditLogsRequest) (model.GetAuditLogsResponse, error) {
+	return a.repo.GetAuditLogs(ctx, req)
+}
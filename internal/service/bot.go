@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/password"
+	"github.com/alir32a/jupiter/pkg/tg"
+	clog "github.com/charmbracelet/log"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// webhookSecretSize is the size of the secrets telegram sends the updates of the bots with.
+const webhookSecretSize = 32
+
+type BotRepository interface {
+	CreateBot(ctx context.Context, req model.BotEntity) (model.BotEntity, error)
+	GetBotByID(ctx context.Context, id int) (model.BotEntity, error)
+	GetBotByToken(ctx context.Context, token string) (model.BotEntity, error)
+	GetBots(ctx context.Context, req model.GetBotsRequest) (model.GetBotsResponse, error)
+	GetActiveBots(ctx context.Context) ([]model.BotEntity, error)
+	SetBotActive(ctx context.Context, id int, active bool) error
+}
+
+type BotAdminRepository interface {
+	GetAdminByUsername(ctx context.Context, username string) (model.AdminEntity, error)
+}
+
+type BotAuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, req model.CreateAuditLogRequest) error
+}
+
+// BotRunner starts and stops the bots in the process.
+type BotRunner interface {
+	Start(bot model.BotEntity)
+	Stop(id int)
+}
+
+// BotService keeps the bots of the resellers, active bots run next to the main bot and share its services.
+type BotService struct {
+	logger       *clog.Logger
+	repo         BotRepository
+	adminRepo    BotAdminRepository
+	auditLogRepo BotAuditLogRepository
+	runner       BotRunner
+}
+
+func NewBotService(logger *clog.Logger, repo BotRepository, adminRepo BotAdminRepository,
+	auditLogRepo BotAuditLogRepository, runner BotRunner) *BotService {
+	return &BotService{
+		logger:       logger,
+		repo:         repo,
+		adminRepo:    adminRepo,
+		auditLogRepo: auditLogRepo,
+		runner:       runner,
+	}
+}
+
+// CreateBot registers the bot for the reseller and starts it, the token is checked with telegram first.
+func (b BotService) CreateBot(ctx context.Context, req model.CreateBotRequest) (model.BotEntity, error) {
+	req.Token = strings.TrimSpace(req.Token)
+
+	if _, err := b.repo.GetBotByToken(ctx, req.Token); err == nil {
+		return model.BotEntity{}, errorext.NewBadRequestError(errorext.ErrBotAlreadyExists)
+	}
+
+	admin, err := b.adminRepo.GetAdminByUsername(ctx, req.AdminUsername)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.BotEntity{}, errorext.NewNotFoundError(errorext.ErrAdminNotFound)
+		}
+
+		return model.BotEntity{}, errorext.NewInternalError(b.logger, err)
+	}
+
+	me, err := tg.NewBot(req.Token).GetMe()
+	if err != nil {
+		return model.BotEntity{}, errorext.NewBadRequestError(errorext.ErrInvalidBotToken)
+	}
+
+	secret, err := password.NewToken(webhookSecretSize)
+	if err != nil {
+		return model.BotEntity{}, errorext.NewInternalError(b.logger, err)
+	}
+
+	bot, err := b.repo.CreateBot(ctx, model.BotEntity{
+		AdminID:       admin.ID,
+		AdminUsername: admin.Username,
+		Token:         req.Token,
+		Username:      me.Username,
+		WebhookSecret: secret,
+	})
+	if err != nil {
+		return model.BotEntity{}, errorext.NewInternalError(b.logger, err)
+	}
+
+	b.createAuditLog(ctx, req.Actor, model.AuditActionCreateBot, bot.ID,
+		fmt.Sprintf("username: %s, admin: %s", bot.Username, admin.Username))
+
+	b.runner.Start(bot)
+
+	return bot, nil
+}
+
+func (b BotService) GetBots(ctx context.Context, req model.GetBotsRequest) (model.GetBotsResponse, error) {
+	resp, err := b.repo.GetBots(ctx, req)
+	if err != nil {
+		return model.GetBotsResponse{}, errorext.NewInternalError(b.logger, err)
+	}
+
+	return resp, nil
+}
+
+// SetBotActive starts or stops the bot, the users of a stopped bot keep their packages.
+func (b BotService) SetBotActive(ctx context.Context, req model.SetBotActiveRequest) error {
+	bot, err := b.repo.GetBotByID(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := b.repo.SetBotActive(ctx, bot.ID, req.Active); err != nil {
+		return errorext.NewInternalError(b.logger, err)
+	}
+
+	if req.Active {
+		b.createAuditLog(ctx, req.Actor, model.AuditActionActivateBot, bot.ID, "")
+		b.runner.Start(bot)
+
+		return nil
+	}
+
+	b.createAuditLog(ctx, req.Actor, model.AuditActionDeactivateBot, bot.ID, "")
+	b.runner.Stop(bot.ID)
+
+	return nil
+}
+
+// StartBots starts the active bots, it's called once the main bot is started.
+func (b BotService) StartBots(ctx context.Context) error {
+	bots, err := b.repo.GetActiveBots(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, bot := range bots {
+		b.runner.Start(bot)
+	}
+
+	return nil
+}
+
+func (b BotService) createAuditLog(ctx context.Context, actor, action string, botID int, details string) {
+	err := b.auditLogRepo.CreateAuditLog(ctx, model.CreateAuditLogRequest{
+		Actor:      actor,
+		Action:     action,
+		Resource:   model.AuditResourceBot,
+		ResourceID: botID,
+		Details:    details,
+	})
+	if err != nil {
+		b.logger.Error(err.Error())
+	}
+}
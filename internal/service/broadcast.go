@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	clog "github.com/charmbracelet/log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const broadcastBatchSize = 100
+
+var broadcastFilters = []string{
+	model.BroadcastFilterAll,
+	model.BroadcastFilterActivePackage,
+	model.BroadcastFilterTrial,
+	model.BroadcastFilterBanned,
+}
+
+type BroadcastRepository interface {
+	CreateBroadcast(ctx context.Context, req model.CreateBroadcastRequest) (model.BroadcastEntity, error)
+	GetBroadcastByID(ctx context.Context, id int) (model.BroadcastEntity, error)
+	GetBroadcasts(ctx context.Context, req model.GetBroadcastsRequest) (model.GetBroadcastsResponse, error)
+	GetBroadcastsByStatus(ctx context.Context, status string) ([]model.BroadcastEntity, error)
+	GetDeliveries(ctx context.Context, broadcastID int, status string, limit int) ([]model.BroadcastDeliveryEntity, error)
+	UpdateDelivery(ctx context.Context, req model.UpdateBroadcastDeliveryRequest) error
+	FinishBroadcast(ctx context.Context, id int) error
+}
+
+type BroadcastSender interface {
+	SendMessage(req tg.SendMessageRequest) (tg.Message, error)
+}
+
+// BroadcastService sends the broadcasts in the background, the broadcasts take turns sending their due
+// deliveries, so the messages per second limit applies to all of them together, and the deliveries waiting
+// for a retry don't hold the other broadcasts back.
+type BroadcastService struct {
+	cfg    *config.BroadcastConfig
+	logger *clog.Logger
+	repo   BroadcastRepository
+	sender BroadcastSender
+	mu     *sync.Mutex
+}
+
+func NewBroadcastService(cfg *config.BroadcastConfig, logger *clog.Logger, repo BroadcastRepository,
+	sender BroadcastSender) *BroadcastService {
+	return &BroadcastService{
+		cfg:    cfg,
+		logger: logger,
+		repo:   repo,
+		sender: sender,
+		mu:     &sync.Mutex{},
+	}
+}
+
+func (b BroadcastService) CreateBroadcast(ctx context.Context, req model.CreateBroadcastRequest) (model.BroadcastEntity, error) {
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		return model.BroadcastEntity{}, errorext.NewBadRequestError(errorext.ErrBroadcastTextRequired)
+	}
+
+	if req.Filter == "" {
+		req.Filter = model.BroadcastFilterAll
+	}
+
+	if !slices.Contains(broadcastFilters, req.Filter) {
+		return model.BroadcastEntity{}, errorext.NewBadRequestError(errorext.ErrInvalidBroadcastFilter)
+	}
+
+	if req.ParseMode != "" && req.ParseMode != tg.ParseModeMarkdown && req.ParseMode != tg.ParseModeHTML {
+		return model.BroadcastEntity{}, errorext.NewBadRequestError(errorext.ErrInvalidParseMode)
+	}
+
+	if (req.ButtonText == "") != (req.ButtonUrl == "") {
+		return model.BroadcastEntity{}, errorext.NewBadRequestError(errorext.ErrBroadcastButtonIncomplete)
+	}
+
+	broadcast, err := b.repo.CreateBroadcast(ctx, req)
+	if err != nil {
+		return model.BroadcastEntity{}, errorext.NewInternalError(b.logger, err)
+	}
+
+	go b.run(broadcast)
+
+	return broadcast, nil
+}
+
+func (b BroadcastService) GetBroadcasts(ctx context.Context, req model.GetBroadcastsRequest) (model.GetBroadcastsResponse, error) {
+	return b.repo.GetBroadcasts(ctx, req)
+}
+
+// GetBroadcastReport returns the broadcast's progress along with the deliveries that have failed.
+func (b BroadcastService) GetBroadcastReport(ctx context.Context, id int) (model.GetBroadcastReportResponse, error) {
+	broadcast, err := b.repo.GetBroadcastByID(ctx, id)
+	if err != nil {
+		return model.GetBroadcastReportResponse{}, err
+	}
+
+	failed, err := b.repo.GetDeliveries(ctx, id, model.BroadcastDeliveryStatusFailed, 0)
+	if err != nil {
+		return model.GetBroadcastReportResponse{}, errorext.NewInternalError(b.logger, err)
+	}
+
+	return model.GetBroadcastReportResponse{
+		Broadcast:        broadcast,
+		FailedDeliveries: failed,
+	}, nil
+}
+
+// ResumeBroadcasts continues the broadcasts that were interrupted, e.g. by a restart.
+func (b BroadcastService) ResumeBroadcasts(ctx context.Context) error {
+	broadcasts, err := b.repo.GetBroadcastsByStatus(ctx, model.BroadcastStatusRunning)
+	if err != nil {
+		return err
+	}
+
+	for _, broadcast := range broadcasts {
+		go b.run(broadcast)
+	}
+
+	return nil
+}
+
+func (b BroadcastService) run(broadcast model.BroadcastEntity) {
+	ctx := context.Background()
+
+	for {
+		next, err := b.sendDueDeliveries(ctx, broadcast)
+		if err != nil {
+			b.logger.Error(err.Error())
+
+			return
+		}
+
+		if next.IsZero() {
+			break
+		}
+
+		// the other broadcasts are sent while the deliveries of this one wait for their retry.
+		time.Sleep(time.Until(next))
+	}
+
+	if err := b.repo.FinishBroadcast(ctx, broadcast.ID); err != nil {
+		b.logger.Error(err.Error())
+	}
+}
+
+// sendDueDeliveries sends the pending deliveries that are due, it returns when the next pending delivery is
+// due, or the zero time if there's none left.
+func (b BroadcastService) sendDueDeliveries(ctx context.Context, broadcast model.BroadcastEntity) (time.Time, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ticker := time.NewTicker(time.Second / time.Duration(max(b.cfg.MessagesPerSecond, 1)))
+	defer ticker.Stop()
+
+	for {
+		deliveries, err := b.repo.GetDeliveries(ctx, broadcast.ID, model.BroadcastDeliveryStatusPending, broadcastBatchSize)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if len(deliveries) <= 0 {
+			return time.Time{}, nil
+		}
+
+		if deliveries[0].NextAttemptAt.After(time.Now()) {
+			return deliveries[0].NextAttemptAt, nil
+		}
+
+		for _, delivery := range deliveries {
+			// deliveries are ordered by their next attempt, so the rest aren't due either.
+			if delivery.NextAttemptAt.After(time.Now()) {
+				break
+			}
+
+			<-ticker.C
+
+			b.deliver(ctx, broadcast, delivery)
+		}
+	}
+}
+
+// deliver sends the broadcast to a single user, failed deliveries stay pending and are retried with a backoff
+// until they run out of attempts, deliveries to the users that have blocked the bot fail right away.
+func (b BroadcastService) deliver(ctx context.Context, broadcast model.BroadcastEntity, delivery model.BroadcastDeliveryEntity) {
+	maxAttempts := max(b.cfg.MaxAttempts, 1)
+
+	req := model.UpdateBroadcastDeliveryRequest{
+		ID:          delivery.ID,
+		BroadcastID: broadcast.ID,
+		Status:      model.BroadcastDeliveryStatusSent,
+		Attempts:    delivery.Attempts + 1,
+	}
+
+	chatID, err := strconv.Atoi(delivery.ChatID)
+	if err == nil {
+		_, err = b.sender.SendMessage(toBroadcastMessage(broadcast, chatID))
+	} else {
+		req.Attempts = maxAttempts
+	}
+
+	if err != nil {
+		req.Status = model.BroadcastDeliveryStatusPending
+		req.Error = err.Error()
+		req.NextAttemptAt = time.Now().Add(b.cfg.RetryDelay * time.Duration(1<<(req.Attempts-1)))
+
+		if req.Attempts >= maxAttempts || tg.IsForbidden(err) {
+			req.Status = model.BroadcastDeliveryStatusFailed
+		}
+	}
+
+	if err := b.repo.UpdateDelivery(ctx, req); err != nil {
+		b.logger.Error(err.Error())
+	}
+}
+
+func toBroadcastMessage(broadcast model.BroadcastEntity, chatID int) tg.SendMessageRequest {
+	req := tg.SendMessageRequest{
+		ChatID:    chatID,
+		Text:      broadcast.Text,
+		ParseMode: broadcast.ParseMode,
+	}
+
+	if broadcast.ButtonText != "" {
+		req.ReplyMarkup = tg.NewInlineKeyboard(tg.InlineKeyboardButton{
+			Text: broadcast.ButtonText,
+			Url:  broadcast.ButtonUrl,
+		})
+	}
+
+	return req
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/password"
+	clog "github.com/charmbracelet/log"
+	"time"
+)
+
+// callbackKeySize keeps the keys at 12 characters, which leaves most of the 64 bytes of callback data
+// to the rest of the query.
+const callbackKeySize = 9
+
+type CallbackRepository interface {
+	SaveCallbackPayloads(ctx context.Context, reqs []model.SaveCallbackPayloadRequest) error
+	GetCallbackPayload(ctx context.Context, key string) (model.CallbackPayloadEntity, error)
+	DeleteExpiredCallbackPayloads(ctx context.Context) error
+}
+
+// CallbackService stores the payloads of inline keyboard buttons, so buttons can carry any state.
+type CallbackService struct {
+	repo   CallbackRepository
+	logger *clog.Logger
+}
+
+func NewCallbackService(repo CallbackRepository, logger *clog.Logger) *CallbackService {
+	return &CallbackService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// StorePayloads stores the payloads of the buttons of a chat and returns their keys in the same order.
+func (c CallbackService) StorePayloads(ctx context.Context, chatID int, payloads []map[string]string,
+	ttl time.Duration) ([]string, error) {
+	keys := make([]string, 0, len(payloads))
+	reqs := make([]model.SaveCallbackPayloadRequest, 0, len(payloads))
+
+	for _, payload := range payloads {
+		key, err := password.NewToken(callbackKeySize)
+		if err != nil {
+			return nil, errorext.NewInternalError(c.logger, err)
+		}
+
+		keys = append(keys, key)
+		reqs = append(reqs, model.SaveCallbackPayloadRequest{
+			Key:      key,
+			ChatID:   chatID,
+			Data:     payload,
+			ExpireAt: time.Now().Add(ttl),
+		})
+	}
+
+	if err := c.repo.SaveCallbackPayloads(ctx, reqs); err != nil {
+		return nil, errorext.NewInternalError(c.logger, err)
+	}
+
+	return keys, nil
+}
+
+// GetPayload returns the payload of the button, buttons can only be used in the chat they were sent to.
+func (c CallbackService) GetPayload(ctx context.Context, chatID int, key string) (map[string]string, error) {
+	payload, err := c.repo.GetCallbackPayload(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.ChatID != chatID || payload.IsExpired() {
+		return nil, errorext.NewNotFoundError(errorext.ErrMenuExpired)
+	}
+
+	return payload.Data, nil
+}
+
+func (c CallbackService) DeleteExpiredPayloads(ctx context.Context) error {
+	if err := c.repo.DeleteExpiredCallbackPayloads(ctx); err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	return nil
+}
@@ -1,8 +1,9 @@
 package service
 
 import (
-	"cmp"
 	"context"
+	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/errorext"
 	"github.com/alir32a/jupiter/internal/model"
 	"github.com/alir32a/jupiter/internal/util"
 	"github.com/alir32a/jupiter/pkg/ocserv"
@@ -24,29 +25,42 @@ type ConnectionRepository interface {
 type ConnectionPackageRepository interface {
 	GetUsersActiveAndReservedPackages(ctx context.Context, userIDs ...int) (model.GetUsersActivePackagesResponse, error)
 	UpdateTrafficUsage(ctx context.Context, req model.UpdateTrafficUsageRequest) error
+	ActivatePackage(ctx context.Context, id int) error
 }
 
 type ConnectionUserRepository interface {
 	GetUsersByUsernames(ctx context.Context, usernames ...string) ([]model.UserEntity, error)
 	GetTotalUsersCount(ctx context.Context) (int, error)
+	ThrottleUser(ctx context.Context, id int) error
+	UnthrottleUser(ctx context.Context, id int) error
+}
+
+type ConnectionNotifier interface {
+	NotifyUsage(ctx context.Context, user model.UserEntity, pack model.PackageEntity, usage int)
+	NotifyPackageActivated(ctx context.Context, user model.UserEntity, pack model.PackageEntity)
 }
 
 type ConnectionService struct {
+	cfg          *config.PackageConfig
 	logger       *clog.Logger
 	ocservClient *ocserv.Client
 	repo         ConnectionRepository
 	packageRepo  ConnectionPackageRepository
 	userRepo     ConnectionUserRepository
+	notifier     ConnectionNotifier
 }
 
-func NewConnectionService(logger *clog.Logger, ocservClient *ocserv.Client, repo ConnectionRepository,
-	packageRepo ConnectionPackageRepository, userRepo ConnectionUserRepository) *ConnectionService {
+func NewConnectionService(cfg *config.PackageConfig, logger *clog.Logger, ocservClient *ocserv.Client,
+	repo ConnectionRepository, packageRepo ConnectionPackageRepository,
+	userRepo ConnectionUserRepository, notifier ConnectionNotifier) *ConnectionService {
 	return &ConnectionService{
+		cfg:          cfg,
 		logger:       logger,
 		ocservClient: ocservClient,
 		repo:         repo,
 		packageRepo:  packageRepo,
 		userRepo:     userRepo,
+		notifier:     notifier,
 	}
 }
 
@@ -116,60 +130,49 @@ func (c ConnectionService) ManageActiveConnections(ctx context.Context, lastUpda
 		})
 
 		totalUploadUsage := util.SumFunc(connections, func(conn model.ConnectionEntity) int {
-			return conn.DownloadTrafficUsage
+			return conn.UploadTrafficUsage
 		})
 
+		c.manageFairUse(ctx, user, packs.ActivePackage, totalUsage)
+		c.notifier.NotifyUsage(ctx, user, packs.ActivePackage, totalUsage)
+
 		remainingTraffic := getRemainingTraffic(packs.ActivePackage, totalUsage)
 		if remainingTraffic < 0 {
+			// the active package (if any) is exhausted, charge what's left of it and move the overflow to the
+			// reserved packages.
 			if packs.ActivePackage.ID != 0 {
-				c.DisconnectUser(ctx, username, connections)
+				totalUsageDiff := totalUsage + remainingTraffic
 
-				continue
-			}
-
-			totalUsageDiff := totalUsage + remainingTraffic
+				downloadUsage, uploadUsage := calculateDownloadAndUploadByTotalUsage(totalDownloadUsage, totalUploadUsage, totalUsageDiff)
+				err = c.packageRepo.UpdateTrafficUsage(ctx, model.UpdateTrafficUsageRequest{
+					ID:                   packs.ActivePackage.ID,
+					DownloadTrafficUsage: downloadUsage,
+					UploadTrafficUsage:   uploadUsage,
+				})
+				if err != nil {
+					return err
+				}
 
-			downloadUsage, uploadUsage := calculateDownloadAndUploadByTotalUsage(totalDownloadUsage, totalUploadUsage, totalUsageDiff)
-			err = c.packageRepo.UpdateTrafficUsage(ctx, model.UpdateTrafficUsageRequest{
-				ID:                   packs.ActivePackage.ID,
-				DownloadTrafficUsage: downloadUsage,
-				UploadTrafficUsage:   uploadUsage,
-			})
-			if err != nil {
-				return err
+				totalDownloadUsage -= downloadUsage
+				totalUploadUsage -= uploadUsage
 			}
 
-			totalDownloadUsage -= downloadUsage
-			totalUploadUsage -= uploadUsage
-
 			if len(packs.ReservedPackages) <= 0 {
 				c.DisconnectUser(ctx, username, connections)
 
 				continue
 			}
 
-			slices.SortFunc(packs.ReservedPackages, func(a, b model.PackageEntity) int {
-				return cmp.Compare(a.ExpirationInDays, b.ExpirationInDays)
-			})
+			sortPackagesByConsumptionOrder(packs.ReservedPackages, c.cfg.ConsumptionOrder)
 
 			remainingTraffic = int(math.Abs(float64(remainingTraffic)))
 			for _, pack := range packs.ReservedPackages {
-				if pack.TrafficLimit > remainingTraffic {
-					downloadUsage, uploadUsage := calculateDownloadAndUploadByTotalUsage(totalDownloadUsage, totalUploadUsage, remainingTraffic)
-					err = c.packageRepo.UpdateTrafficUsage(ctx, model.UpdateTrafficUsageRequest{
-						ID:                   pack.ID,
-						DownloadTrafficUsage: downloadUsage,
-						UploadTrafficUsage:   uploadUsage,
-					})
-					if err != nil {
-						return err
-					}
-
-					remainingTraffic = 0
-					break
+				charge := pack.TrafficLimit
+				if pack.HasUnlimitedTraffic() || pack.HasFairUse() || pack.TrafficLimit > remainingTraffic {
+					charge = remainingTraffic
 				}
 
-				downloadUsage, uploadUsage := calculateDownloadAndUploadByTotalUsage(totalDownloadUsage, totalUploadUsage, pack.TrafficLimit)
+				downloadUsage, uploadUsage := calculateDownloadAndUploadByTotalUsage(totalDownloadUsage, totalUploadUsage, charge)
 				err = c.packageRepo.UpdateTrafficUsage(ctx, model.UpdateTrafficUsageRequest{
 					ID:                   pack.ID,
 					DownloadTrafficUsage: downloadUsage,
@@ -179,6 +182,17 @@ func (c ConnectionService) ManageActiveConnections(ctx context.Context, lastUpda
 					return err
 				}
 
+				if err := c.packageRepo.ActivatePackage(ctx, pack.ID); err != nil {
+					return err
+				}
+
+				c.notifier.NotifyPackageActivated(ctx, user, pack)
+
+				if charge == remainingTraffic {
+					remainingTraffic = 0
+					break
+				}
+
 				remainingTraffic -= downloadUsage + uploadUsage
 			}
 
@@ -207,6 +221,35 @@ func (c ConnectionService) ManageActiveConnections(ctx context.Context, lastUpda
 	return nil
 }
 
+// manageFairUse throttles the user when their fair use package has reached its cap, and lifts the throttle
+// when the user's active package is no longer over the cap (e.g. a new package has been activated).
+func (c ConnectionService) manageFairUse(ctx context.Context, user model.UserEntity, pack model.PackageEntity, usage int) {
+	overCap := pack.HasFairUse() && pack.DownloadTrafficUsage+pack.UploadTrafficUsage+usage >= pack.FairUseCap
+
+	switch {
+	case overCap && user.ThrottledAt == nil:
+		if err := c.ocservClient.ThrottleUser(ctx, user.Username, pack.ThrottleRate); err != nil {
+			c.logger.Error(err)
+
+			return
+		}
+
+		if err := c.userRepo.ThrottleUser(ctx, user.ID); err != nil {
+			c.logger.Error(err)
+		}
+	case !overCap && user.ThrottledAt != nil:
+		if err := c.ocservClient.UnthrottleUser(ctx, user.Username); err != nil {
+			c.logger.Error(err)
+
+			return
+		}
+
+		if err := c.userRepo.UnthrottleUser(ctx, user.ID); err != nil {
+			c.logger.Error(err)
+		}
+	}
+}
+
 func (c ConnectionService) DisconnectUser(ctx context.Context, username string, connections []model.ConnectionEntity) {
 	if err := c.ocservClient.DisconnectUser(ctx, username); err != nil {
 		c.logger.Error(err)
@@ -242,6 +285,51 @@ func (c ConnectionService) DisconnectID(ctx context.Context, id int) error {
 	return c.ocservClient.DisconnectID(ctx, externalID)
 }
 
+// DisconnectUserConnection disconnects the connection only if it's one of the user's active connections.
+func (c ConnectionService) DisconnectUserConnection(ctx context.Context, username string, id int) error {
+	connections, err := c.repo.GetUserActiveConnections(ctx, username)
+	if err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	if !slices.ContainsFunc(connections, func(conn model.ConnectionEntity) bool { return conn.ID == id }) {
+		return errorext.NewNotFoundError(errorext.ErrConnectionNotFound)
+	}
+
+	return c.DisconnectID(ctx, id)
+}
+
+// DisconnectAllUserConnections ends every session of the user, unlike DisconnectUser it doesn't lock the user.
+func (c ConnectionService) DisconnectAllUserConnections(ctx context.Context, username string) error {
+	connections, err := c.repo.GetUserActiveConnections(ctx, username)
+	if err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	// occtl fails to disconnect the users that aren't connected.
+	if len(connections) == 0 {
+		return nil
+	}
+
+	if err := c.ocservClient.DisconnectUser(ctx, username); err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	for _, conn := range connections {
+		err := c.repo.Disconnect(ctx, model.DisconnectRequest{
+			ConnectionID:         conn.ID,
+			DownloadTrafficUsage: conn.DownloadTrafficUsage,
+			UploadTrafficUsage:   conn.UploadTrafficUsage,
+			Username:             conn.Username,
+		})
+		if err != nil {
+			return errorext.NewInternalError(c.logger, err)
+		}
+	}
+
+	return nil
+}
+
 func (c ConnectionService) GetActiveConnections(ctx context.Context, req model.GetActiveConnectionsRequest) (model.GetActiveConnectionsResponse, error) {
 	return c.repo.GetActiveConnections(ctx, req)
 }
@@ -266,6 +354,10 @@ func (c ConnectionService) GetSystemStatus(ctx context.Context) (model.GetSystem
 }
 
 func getRemainingTraffic(pack model.PackageEntity, usage int) int {
+	if pack.HasUnlimitedTraffic() || pack.HasFairUse() {
+		return math.MaxInt
+	}
+
 	return pack.TrafficLimit - (pack.DownloadTrafficUsage + pack.UploadTrafficUsage + usage)
 }
 
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	clog "github.com/charmbracelet/log"
+	"gorm.io/gorm"
+)
+
+type ConversationRepository interface {
+	SaveConversation(ctx context.Context, req model.SaveConversationRequest) error
+	GetConversation(ctx context.Context, botID, chatID int) (model.ConversationEntity, error)
+	DeleteConversation(ctx context.Context, botID, chatID int) error
+}
+
+type ConversationService struct {
+	repo   ConversationRepository
+	logger *clog.Logger
+}
+
+func NewConversationService(repo ConversationRepository, logger *clog.Logger) *ConversationService {
+	return &ConversationService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (c ConversationService) SaveConversation(ctx context.Context, req model.SaveConversationRequest) error {
+	if err := c.repo.SaveConversation(ctx, req); err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	return nil
+}
+
+// GetConversation returns the conversation of the chat, or nil if the chat isn't in any conversation.
+func (c ConversationService) GetConversation(ctx context.Context, botID, chatID int) (*model.ConversationEntity, error) {
+	conversation, err := c.repo.GetConversation(ctx, botID, chatID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errorext.NewInternalError(c.logger, err)
+	}
+
+	return &conversation, nil
+}
+
+func (c ConversationService) EndConversation(ctx context.Context, botID, chatID int) error {
+	if err := c.repo.DeleteConversation(ctx, botID, chatID); err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	return nil
+}
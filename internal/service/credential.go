@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/password"
+	clog "github.com/charmbracelet/log"
+	"time"
+)
+
+const (
+	messageDeletionBatchSize   = 100
+	messageDeletionMaxAttempts = 3
+
+	revealTokenSize = 16
+)
+
+type CredentialRepository interface {
+	ScheduleMessageDeletion(ctx context.Context, req model.ScheduleMessageDeletionRequest) error
+	GetDueMessageDeletions(ctx context.Context, before time.Time, limit int) ([]model.MessageDeletionEntity, error)
+	DeleteMessageDeletion(ctx context.Context, id int) error
+	IncrementMessageDeletionAttempts(ctx context.Context, id int) error
+	CreateCredentialReveal(ctx context.Context, req model.CreateCredentialRevealRequest) error
+	TakeCredentialReveal(ctx context.Context, tokenHash string) (model.CredentialRevealEntity, error)
+	DeleteExpiredCredentialReveals(ctx context.Context) error
+}
+
+// MessageDeleter deletes the message through the bot that has sent it.
+type MessageDeleter interface {
+	DeleteMessage(botID, chatID, msgID int) error
+}
+
+// CredentialService keeps the passwords sent by the bot out of the chats, passwords are revealed once
+// on request and the messages about them are deleted even if the bot restarts in between.
+type CredentialService struct {
+	logger  *clog.Logger
+	repo    CredentialRepository
+	deleter MessageDeleter
+}
+
+func NewCredentialService(logger *clog.Logger, repo CredentialRepository, deleter MessageDeleter) *CredentialService {
+	return &CredentialService{
+		logger:  logger,
+		repo:    repo,
+		deleter: deleter,
+	}
+}
+
+func (c CredentialService) ScheduleDeletion(ctx context.Context, botID, chatID, messageID int, after time.Duration) error {
+	err := c.repo.ScheduleMessageDeletion(ctx, model.ScheduleMessageDeletionRequest{
+		BotID:     botID,
+		ChatID:    chatID,
+		MessageID: messageID,
+		DeleteAt:  time.Now().Add(after),
+	})
+	if err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	return nil
+}
+
+// DeleteDueMessages deletes the messages whose time has come, a deletion is given up after a few failed
+// attempts since telegram doesn't let bots delete messages older than 48 hours.
+func (c CredentialService) DeleteDueMessages(ctx context.Context) error {
+	deletions, err := c.repo.GetDueMessageDeletions(ctx, time.Now(), messageDeletionBatchSize)
+	if err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	for _, deletion := range deletions {
+		err := c.deleter.DeleteMessage(deletion.BotID, deletion.ChatID, deletion.MessageID)
+		if err != nil && deletion.Attempts+1 < messageDeletionMaxAttempts {
+			c.logger.Warn(err.Error(), "chat_id", deletion.ChatID, "message_id", deletion.MessageID)
+
+			if err := c.repo.IncrementMessageDeletionAttempts(ctx, deletion.ID); err != nil {
+				return errorext.NewInternalError(c.logger, err)
+			}
+
+			continue
+		}
+
+		if err := c.repo.DeleteMessageDeletion(ctx, deletion.ID); err != nil {
+			return errorext.NewInternalError(c.logger, err)
+		}
+	}
+
+	return nil
+}
+
+// CreateReveal stores the password sealed with a new token and returns the token, the password can be
+// revealed once with the token in the same chat until ttl passes.
+func (c CredentialService) CreateReveal(ctx context.Context, chatID int, username, pass string, ttl time.Duration) (string, error) {
+	token, err := password.NewToken(revealTokenSize)
+	if err != nil {
+		return "", errorext.NewInternalError(c.logger, err)
+	}
+
+	sealed, err := password.Seal(token, pass)
+	if err != nil {
+		return "", errorext.NewInternalError(c.logger, err)
+	}
+
+	err = c.repo.CreateCredentialReveal(ctx, model.CreateCredentialRevealRequest{
+		TokenHash:      password.HashToken(token),
+		ChatID:         chatID,
+		Username:       username,
+		SealedPassword: sealed,
+		ExpireAt:       time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", errorext.NewInternalError(c.logger, err)
+	}
+
+	return token, nil
+}
+
+func (c CredentialService) RevealPassword(ctx context.Context, chatID int, token string) (model.RevealedCredentials, error) {
+	reveal, err := c.repo.TakeCredentialReveal(ctx, password.HashToken(token))
+	if err != nil {
+		return model.RevealedCredentials{}, err
+	}
+
+	if reveal.ChatID != chatID {
+		return model.RevealedCredentials{}, errorext.NewNotFoundError(errorext.ErrCredentialRevealNotFound)
+	}
+
+	pass, err := password.Open(token, reveal.SealedPassword)
+	if err != nil {
+		return model.RevealedCredentials{}, errorext.NewInternalError(c.logger, err)
+	}
+
+	return model.RevealedCredentials{
+		Username: reveal.Username,
+		Password: pass,
+	}, nil
+}
+
+func (c CredentialService) DeleteExpiredReveals(ctx context.Context) error {
+	if err := c.repo.DeleteExpiredCredentialReveals(ctx); err != nil {
+		return errorext.NewInternalError(c.logger, err)
+	}
+
+	return nil
+}
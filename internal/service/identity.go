@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/password"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const linkCodeLength = 8
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{3,32}$`)
+
+// CreatePanelUser creates a user that isn't linked to any account, users link their accounts to it later
+// with a link code.
+func (u UserService) CreatePanelUser(ctx context.Context, req model.CreatePanelUserRequest) (model.CreateUserResponse, error) {
+	if !usernamePattern.MatchString(req.Username) {
+		return model.CreateUserResponse{}, errorext.NewBadRequestError(errorext.ErrInvalidUsername)
+	}
+
+	return u.CreateUser(ctx, model.CreateUserRequest{
+		Username:   req.Username,
+		UserType:   model.UserTypePanel,
+		BotID:      req.BotID,
+		ResellerID: req.ResellerID,
+	})
+}
+
+// CreateLinkCode returns a one time code for linking an account to the user, only the hash of the code
+// is stored.
+func (u UserService) CreateLinkCode(ctx context.Context, userID int) (model.LinkCode, error) {
+	if _, err := u.repo.GetUserByID(ctx, userID); err != nil {
+		return model.LinkCode{}, err
+	}
+
+	code, err := password.NewCode(linkCodeLength)
+	if err != nil {
+		return model.LinkCode{}, errorext.NewInternalError(u.logger, err)
+	}
+
+	expireAt := time.Now().Add(u.cfg.MainBot.LinkCodeTTL)
+
+	err = u.repo.CreateLinkCode(ctx, model.CreateLinkCodeRequest{
+		CodeHash: password.HashToken(code),
+		UserID:   userID,
+		ExpireAt: expireAt,
+	})
+	if err != nil {
+		return model.LinkCode{}, errorext.NewInternalError(u.logger, err)
+	}
+
+	return model.LinkCode{Code: code, ExpireAt: expireAt}, nil
+}
+
+// LinkTelegramAccount links the telegram account to the user the code was created for, the code must be used
+// in the bot the user belongs to.
+func (u UserService) LinkTelegramAccount(ctx context.Context, botID int, externalID, code string) (model.UserEntity, error) {
+	if _, err := u.GetUserByExternalID(ctx, externalID); err == nil {
+		return model.UserEntity{}, errorext.NewBadRequestError(errorext.ErrAccountAlreadyLinked)
+	}
+
+	return u.repo.LinkIdentity(ctx, model.LinkIdentityRequest{
+		CodeHash:   password.HashToken(strings.ToUpper(strings.TrimSpace(code))),
+		Provider:   model.IdentityProviderTelegram,
+		ExternalID: externalID,
+		BotID:      botID,
+	})
+}
+
+func (u UserService) GetUserIdentities(ctx context.Context, userID int) ([]model.UserIdentityEntity, error) {
+	identities, err := u.repo.GetUserIdentities(ctx, userID)
+	if err != nil {
+		return nil, errorext.NewInternalError(u.logger, err)
+	}
+
+	return identities, nil
+}
+
+func (u UserService) DeleteExpiredLinkCodes(ctx context.Context) error {
+	return u.repo.DeleteExpiredLinkCodes(ctx)
+}
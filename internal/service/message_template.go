@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	clog "github.com/charmbracelet/log"
+	"slices"
+	"sync"
+)
+
+type MessageTemplateRepository interface {
+	GetMessageTemplates(ctx context.Context) ([]model.MessageTemplateEntity, error)
+	SaveMessageTemplate(ctx context.Context, req model.SaveMessageTemplateRequest) (int, error)
+	DeleteMessageTemplate(ctx context.Context, key, language string) error
+}
+
+type MessageTemplateAuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, req model.CreateAuditLogRequest) error
+}
+
+// messageTemplateCache keeps the templates admins have saved, so rendering a message doesn't hit the database.
+type messageTemplateCache struct {
+	mu        sync.RWMutex
+	templates map[string]model.MessageTemplateEntity
+}
+
+// MessageTemplateService renders the bot messages with the templates admins have saved, or the default
+// templates of the message catalog.
+type MessageTemplateService struct {
+	repo         MessageTemplateRepository
+	auditLogRepo MessageTemplateAuditLogRepository
+	logger       *clog.Logger
+	cache        *messageTemplateCache
+}
+
+func NewMessageTemplateService(repo MessageTemplateRepository, auditLogRepo MessageTemplateAuditLogRepository,
+	logger *clog.Logger) *MessageTemplateService {
+	return &MessageTemplateService{
+		repo:         repo,
+		auditLogRepo: auditLogRepo,
+		logger:       logger,
+		cache:        &messageTemplateCache{},
+	}
+}
+
+// Render renders the message in the language, the default template is used if the saved template fails.
+func (m MessageTemplateService) Render(ctx context.Context, lang, key string, data tg.TemplateData) (model.RenderedMessage, error) {
+	templates, err := m.getTemplates(ctx)
+	if err != nil {
+		m.logger.Error(err.Error())
+	}
+
+	if tmpl, ok := templates[messageTemplateCacheKey(key, lang)]; ok {
+		text, err := tg.RenderTemplate(tmpl.Body, tmpl.ParseMode, data)
+		if err == nil {
+			return model.RenderedMessage{Text: text, ParseMode: tmpl.ParseMode}, nil
+		}
+
+		m.logger.Error("couldn't render message template", "key", key, "language", lang, "err", err)
+	}
+
+	body, ok := locale.Template(lang, key)
+	if !ok {
+		return model.RenderedMessage{}, fmt.Errorf("message %s has no template", key)
+	}
+
+	text, err := tg.RenderTemplate(body, locale.DefaultParseMode, data)
+	if err != nil {
+		return model.RenderedMessage{}, err
+	}
+
+	return model.RenderedMessage{Text: text, ParseMode: locale.DefaultParseMode}, nil
+}
+
+func (m MessageTemplateService) GetMessageTemplates(ctx context.Context) ([]model.MessageTemplate, error) {
+	templates, err := m.getTemplates(ctx)
+	if err != nil {
+		return nil, errorext.NewInternalError(m.logger, err)
+	}
+
+	result := make([]model.MessageTemplate, 0, len(locale.MessageKeys)*len(locale.Languages))
+
+	for _, key := range locale.MessageKeys {
+		for _, lang := range locale.Languages {
+			defaultBody, _ := locale.Template(lang, key)
+
+			tmpl := model.MessageTemplate{
+				Key:              key,
+				Language:         lang,
+				ParseMode:        locale.DefaultParseMode,
+				Body:             defaultBody,
+				DefaultParseMode: locale.DefaultParseMode,
+				DefaultBody:      defaultBody,
+				Variables:        templateVariables(locale.SampleData(key)),
+			}
+
+			if saved, ok := templates[messageTemplateCacheKey(key, lang)]; ok {
+				tmpl.ParseMode = saved.ParseMode
+				tmpl.Body = saved.Body
+				tmpl.Customized = true
+				tmpl.UpdatedBy = saved.UpdatedBy
+				tmpl.UpdatedAt = &saved.UpdatedAt
+			}
+
+			result = append(result, tmpl)
+		}
+	}
+
+	return result, nil
+}
+
+func (m MessageTemplateService) SaveMessageTemplate(ctx context.Context, req model.SaveMessageTemplateRequest) error {
+	if _, err := previewMessageTemplate(model.PreviewMessageTemplateRequest{
+		Key:       req.Key,
+		Language:  req.Language,
+		ParseMode: req.ParseMode,
+		Body:      req.Body,
+	}); err != nil {
+		return err
+	}
+
+	id, err := m.repo.SaveMessageTemplate(ctx, req)
+	if err != nil {
+		return errorext.NewInternalError(m.logger, err)
+	}
+
+	m.invalidate()
+	m.createAuditLog(ctx, req.Actor, model.AuditActionUpdateMessageTemplate, id,
+		fmt.Sprintf("key: %s, language: %s", req.Key, req.Language))
+
+	return nil
+}
+
+// ResetMessageTemplate removes the saved template, so the message is rendered with its default template.
+func (m MessageTemplateService) ResetMessageTemplate(ctx context.Context, req model.ResetMessageTemplateRequest) error {
+	templates, err := m.getTemplates(ctx)
+	if err != nil {
+		return errorext.NewInternalError(m.logger, err)
+	}
+
+	tmpl, ok := templates[messageTemplateCacheKey(req.Key, req.Language)]
+	if !ok {
+		return errorext.NewNotFoundError(errorext.ErrMessageTemplateNotFound)
+	}
+
+	if err := m.repo.DeleteMessageTemplate(ctx, req.Key, req.Language); err != nil {
+		return errorext.NewInternalError(m.logger, err)
+	}
+
+	m.invalidate()
+	m.createAuditLog(ctx, req.Actor, model.AuditActionResetMessageTemplate, tmpl.ID,
+		fmt.Sprintf("key: %s, language: %s", req.Key, req.Language))
+
+	return nil
+}
+
+// PreviewMessageTemplate renders the template with the sample data of the message.
+func (m MessageTemplateService) PreviewMessageTemplate(ctx context.Context, req model.PreviewMessageTemplateRequest) (model.RenderedMessage, error) {
+	return previewMessageTemplate(req)
+}
+
+func (m MessageTemplateService) getTemplates(ctx context.Context) (map[string]model.MessageTemplateEntity, error) {
+	m.cache.mu.RLock()
+	templates := m.cache.templates
+	m.cache.mu.RUnlock()
+
+	if templates != nil {
+		return templates, nil
+	}
+
+	saved, err := m.repo.GetMessageTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	templates = make(map[string]model.MessageTemplateEntity, len(saved))
+	for _, tmpl := range saved {
+		templates[messageTemplateCacheKey(tmpl.Key, tmpl.Language)] = tmpl
+	}
+
+	m.cache.mu.Lock()
+	m.cache.templates = templates
+	m.cache.mu.Unlock()
+
+	return templates, nil
+}
+
+func (m MessageTemplateService) invalidate() {
+	m.cache.mu.Lock()
+	m.cache.templates = nil
+	m.cache.mu.Unlock()
+}
+
+func (m MessageTemplateService) createAuditLog(ctx context.Context, actor, action string, id int, details string) {
+	err := m.auditLogRepo.CreateAuditLog(ctx, model.CreateAuditLogRequest{
+		Actor:      actor,
+		Action:     action,
+		Resource:   model.AuditResourceMessageTemplate,
+		ResourceID: id,
+		Details:    details,
+	})
+	if err != nil {
+		m.logger.Error(err.Error())
+	}
+}
+
+func previewMessageTemplate(req model.PreviewMessageTemplateRequest) (model.RenderedMessage, error) {
+	if _, ok := locale.Template(req.Language, req.Key); !ok {
+		return model.RenderedMessage{}, errorext.NewNotFoundError(errorext.ErrMessageTemplateNotFound)
+	}
+
+	if !locale.IsSupported(req.Language) {
+		return model.RenderedMessage{}, errorext.NewBadRequestError(errorext.ErrUnsupportedLanguage)
+	}
+
+	if req.ParseMode != "" && req.ParseMode != tg.ParseModeMarkdown && req.ParseMode != tg.ParseModeHTML {
+		return model.RenderedMessage{}, errorext.NewBadRequestError(errorext.ErrInvalidParseMode)
+	}
+
+	text, err := tg.RenderTemplate(req.Body, req.ParseMode, locale.SampleData(req.Key))
+	if err != nil {
+		return model.RenderedMessage{}, errorext.NewBadRequestError(fmt.Errorf("invalid template: %w", err))
+	}
+
+	if text == "" {
+		return model.RenderedMessage{}, errorext.NewBadRequestError(errorext.ErrEmptyMessageTemplate)
+	}
+
+	return model.RenderedMessage{Text: text, ParseMode: req.ParseMode}, nil
+}
+
+func templateVariables(data tg.TemplateData) []string {
+	variables := make([]string, 0, len(data))
+	for name := range data {
+		variables = append(variables, name)
+	}
+
+	slices.Sort(variables)
+
+	return variables
+}
+
+func messageTemplateCacheKey(key, lang string) string {
+	return key + ":" + lang
+}
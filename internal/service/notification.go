@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	"github.com/alir32a/jupiter/pkg/util"
+	clog "github.com/charmbracelet/log"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, req model.CreateNotificationRequest) (bool, error)
+	DeleteNotification(ctx context.Context, req model.CreateNotificationRequest) error
+}
+
+type NotificationUserRepository interface {
+	GetUsersByIDs(ctx context.Context, ids []int) ([]model.UserEntity, error)
+}
+
+type NotificationPackageRepository interface {
+	GetExpiringPackages(ctx context.Context, before time.Time) ([]model.PackageEntity, error)
+}
+
+type NotificationSender interface {
+	SendMessage(botID int, req tg.SendMessageRequest) (tg.Message, error)
+}
+
+type NotificationRenderer interface {
+	Render(ctx context.Context, lang, key string, data tg.TemplateData) (model.RenderedMessage, error)
+}
+
+// NotificationService sends telegram messages to the users about their account, each notification
+// is sent at most once.
+type NotificationService struct {
+	cfg         *config.NotificationConfig
+	logger      *clog.Logger
+	repo        NotificationRepository
+	userRepo    NotificationUserRepository
+	packageRepo NotificationPackageRepository
+	sender      NotificationSender
+	renderer    NotificationRenderer
+}
+
+func NewNotificationService(cfg *config.NotificationConfig, logger *clog.Logger, repo NotificationRepository,
+	userRepo NotificationUserRepository, packageRepo NotificationPackageRepository,
+	sender NotificationSender, renderer NotificationRenderer) *NotificationService {
+	return &NotificationService{
+		cfg:         cfg,
+		logger:      logger,
+		repo:        repo,
+		userRepo:    userRepo,
+		packageRepo: packageRepo,
+		sender:      sender,
+		renderer:    renderer,
+	}
+}
+
+// NotifyUsage notifies the user about the highest usage threshold their package has crossed.
+func (n NotificationService) NotifyUsage(ctx context.Context, user model.UserEntity, pack model.PackageEntity, usage int) {
+	limit := pack.TrafficLimit
+	if pack.HasFairUse() {
+		limit = pack.FairUseCap
+	}
+
+	if pack.ID == 0 || limit <= 0 || (pack.HasUnlimitedTraffic() && !pack.HasFairUse()) {
+		return
+	}
+
+	totalUsage := pack.DownloadTrafficUsage + pack.UploadTrafficUsage + usage
+	percent := totalUsage * 100 / limit
+
+	thresholds := slices.Clone(n.cfg.UsageThresholds)
+	slices.Sort(thresholds)
+
+	var crossed int
+	for _, threshold := range thresholds {
+		if percent >= threshold {
+			crossed = threshold
+		}
+	}
+
+	if crossed == 0 {
+		return
+	}
+
+	key := locale.MsgUsageThreshold
+	data := tg.TemplateData{
+		"Percent": crossed,
+		"Usage":   util.ToHumanReadableBytes(totalUsage),
+		"Limit":   util.ToHumanReadableBytes(limit),
+	}
+
+	if crossed >= 100 {
+		key = locale.MsgTrafficRunOut
+	}
+
+	if pack.HasFairUse() && crossed >= 100 {
+		key = locale.MsgFairUseCapReached
+		data = tg.TemplateData{"ThrottleRate": util.ToHumanReadableBytes(pack.ThrottleRate)}
+	}
+
+	n.notify(ctx, user, model.NotificationKindUsage, fmt.Sprintf("%d:%d", pack.ID, crossed), key, data)
+}
+
+func (n NotificationService) NotifyPackageActivated(ctx context.Context, user model.UserEntity, pack model.PackageEntity) {
+	data := tg.TemplateData{
+		"TrafficLimit":   formatTrafficLimit(userLanguage(user), pack),
+		"MaxConnections": pack.MaxConnections,
+	}
+
+	n.notify(ctx, user, model.NotificationKindPackageActivated, strconv.Itoa(pack.ID), locale.MsgPackageActivated, data)
+}
+
+func (n NotificationService) NotifyBanned(ctx context.Context, user model.UserEntity) {
+	n.notify(ctx, user, model.NotificationKindBanned, "", locale.MsgBanned, nil)
+}
+
+func (n NotificationService) NotifyUnbanned(ctx context.Context, user model.UserEntity) {
+	n.notify(ctx, user, model.NotificationKindUnbanned, "", locale.MsgUnbanned, nil)
+}
+
+// NotifyExpiringPackages reminds the users whose active package expires within the configured reminder duration.
+func (n NotificationService) NotifyExpiringPackages(ctx context.Context) error {
+	if !n.cfg.Activated {
+		return nil
+	}
+
+	packages, err := n.packageRepo.GetExpiringPackages(ctx, time.Now().Add(n.cfg.ExpiryReminder))
+	if err != nil {
+		return err
+	}
+
+	if len(packages) <= 0 {
+		return nil
+	}
+
+	users, err := n.userRepo.GetUsersByIDs(ctx, util.GetStructsField(packages, func(pack model.PackageEntity) int {
+		return pack.UserID
+	}))
+	if err != nil {
+		return err
+	}
+
+	usersMap := util.MapStructsByUniqueField(users, func(user model.UserEntity) int {
+		return user.ID
+	})
+
+	for _, pack := range packages {
+		user, ok := usersMap[pack.UserID]
+		if !ok {
+			continue
+		}
+
+		n.notify(ctx, user, model.NotificationKindExpiry, strconv.Itoa(pack.ID), locale.MsgPackageExpires,
+			tg.TemplateData{"ExpireAt": pack.ExpireAt.Format(time.DateTime)})
+	}
+
+	return nil
+}
+
+// notify sends the notification to the user's telegram account, notifications with a reference are recorded
+// first, so they won't be sent again.
+func (n NotificationService) notify(ctx context.Context, user model.UserEntity, kind, reference, key string,
+	data tg.TemplateData) {
+	if !n.cfg.Activated || !user.NotificationsEnabled || user.UserType != model.UserTypeTelegram {
+		return
+	}
+
+	chatID, err := strconv.Atoi(user.ExternalID)
+	if err != nil {
+		return
+	}
+
+	msg, err := n.renderer.Render(ctx, userLanguage(user), key, data)
+	if err != nil {
+		n.logger.Error(err.Error())
+
+		return
+	}
+
+	req := model.CreateNotificationRequest{
+		UserID:    user.ID,
+		Kind:      kind,
+		Reference: reference,
+	}
+
+	if reference != "" {
+		created, err := n.repo.CreateNotification(ctx, req)
+		if err != nil {
+			n.logger.Error(err.Error())
+
+			return
+		}
+
+		if !created {
+			return
+		}
+	}
+
+	_, err = n.sender.SendMessage(user.BotID, tg.SendMessageRequest{ChatID: chatID, Text: msg.Text,
+		ParseMode: msg.ParseMode})
+	if err != nil {
+		n.logger.Error(err.Error())
+
+		// remove the record, so the notification will be retried.
+		if reference != "" {
+			if err := n.repo.DeleteNotification(ctx, req); err != nil {
+				n.logger.Error(err.Error())
+			}
+		}
+	}
+}
+
+// userLanguage returns the language the user has chosen in the bot, or the default language.
+func userLanguage(user model.UserEntity) string {
+	if locale.IsSupported(user.Language) {
+		return user.Language
+	}
+
+	return locale.DefaultLanguage
+}
+
+func formatTrafficLimit(lang string, pack model.PackageEntity) string {
+	if pack.HasUnlimitedTraffic() {
+		return locale.T(lang, locale.LabelUnlimited)
+	}
+
+	return util.ToHumanReadableBytes(pack.TrafficLimit)
+}
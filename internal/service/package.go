@@ -1,39 +1,97 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"github.com/alir32a/jupiter/config"
 	"github.com/alir32a/jupiter/internal/errorext"
 	"github.com/alir32a/jupiter/internal/model"
 	"github.com/alir32a/jupiter/pkg/util"
 	clog "github.com/charmbracelet/log"
 	"golang.org/x/exp/maps"
 	"gorm.io/gorm"
+	"math"
+	"slices"
 )
 
 type PackageUserRepository interface {
 	GetUsersByUsernames(ctx context.Context, usernames ...string) ([]model.UserEntity, error)
 	GetUserByUsername(ctx context.Context, username string) (model.UserEntity, error)
 	GetUsersByIDs(ctx context.Context, ids []int) ([]model.UserEntity, error)
+	GetUserByID(ctx context.Context, id int) (model.UserEntity, error)
+	AddWalletBalance(ctx context.Context, id, amount int) error
+	TakeBonusDays(ctx context.Context, id int) (int, error)
 }
 
 type PackageRepository interface {
 	GetUserActiveAndReservedPackages(ctx context.Context, userID int) (model.GetUserPackages, error)
 	GetPackages(ctx context.Context, req model.GetPackagesRequest) (model.GetPackagesResponse, error)
-	CreatePackage(ctx context.Context, req model.CreatePackageRequest) error
+	CreatePackage(ctx context.Context, req model.CreatePackageRequest) (model.PackageEntity, error)
+	GetPackageByID(ctx context.Context, id int) (model.PackageEntity, error)
+	ExtendPackage(ctx context.Context, req model.ExtendPackageRequest) error
+	TransferPackage(ctx context.Context, id, userID int) error
+	CancelPackage(ctx context.Context, id int) (bool, error)
+	ActivatePackage(ctx context.Context, id int) error
+}
+
+type PackageAuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, req model.CreateAuditLogRequest) error
+}
+
+type PackagePlanRepository interface {
+	GetPlanByID(ctx context.Context, id int) (model.PlanEntity, error)
+}
+
+// PackageResellerRepository charges the packages the resellers create to their traffic quota.
+type PackageResellerRepository interface {
+	GetResellerByID(ctx context.Context, id int) (model.AdminEntity, error)
+	ReserveTraffic(ctx context.Context, id, traffic int) (bool, error)
+	ReleaseTraffic(ctx context.Context, id, traffic int) error
+	CreateCommission(ctx context.Context, req model.CreateResellerCommissionRequest) error
+}
+
+type PackageConnectionService interface {
+	DisconnectAllUserConnections(ctx context.Context, username string) error
+}
+
+type PackageTransactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type PackageReferralService interface {
+	RewardFirstPurchase(ctx context.Context, userID int) error
 }
 
 type PackageService struct {
-	repo     PackageRepository
-	userRepo PackageUserRepository
-	logger   *clog.Logger
+	cfg           *config.PackageConfig
+	repo          PackageRepository
+	userRepo      PackageUserRepository
+	planRepo      PackagePlanRepository
+	auditLogRepo  PackageAuditLogRepository
+	resellerRepo  PackageResellerRepository
+	referralSvc   PackageReferralService
+	connectionSvc PackageConnectionService
+	transactor    PackageTransactor
+	logger        *clog.Logger
 }
 
-func NewPackageService(logger *clog.Logger, repo PackageRepository, userRepo PackageUserRepository) *PackageService {
+func NewPackageService(cfg *config.PackageConfig, logger *clog.Logger, repo PackageRepository, userRepo PackageUserRepository,
+	planRepo PackagePlanRepository, auditLogRepo PackageAuditLogRepository, resellerRepo PackageResellerRepository,
+	referralSvc PackageReferralService, connectionSvc PackageConnectionService,
+	transactor PackageTransactor) *PackageService {
 	return &PackageService{
-		logger:   logger,
-		repo:     repo,
-		userRepo: userRepo,
+		cfg:           cfg,
+		logger:        logger,
+		repo:          repo,
+		userRepo:      userRepo,
+		planRepo:      planRepo,
+		auditLogRepo:  auditLogRepo,
+		resellerRepo:  resellerRepo,
+		referralSvc:   referralSvc,
+		connectionSvc: connectionSvc,
+		transactor:    transactor,
 	}
 }
 
@@ -135,10 +193,410 @@ func (p PackageService) CreatePackage(ctx context.Context, req model.CreatePacka
 		return err
 	}
 
+	// resellers can't see the users of the others at all.
+	if req.ResellerID != nil && (user.ResellerID == nil || *user.ResellerID != *req.ResellerID) {
+		return errorext.NewNotFoundError(errorext.ErrUserNotFound)
+	}
+
+	var price int
+
+	if req.PlanID > 0 {
+		plan, err := p.planRepo.GetPlanByID(ctx, req.PlanID)
+		if err != nil {
+			return err
+		}
+
+		if !plan.IsActive {
+			return errorext.NewBadRequestError(errorext.ErrPlanNotActive)
+		}
+
+		if plan.BotID != user.BotID {
+			return errorext.NewBadRequestError(errorext.ErrPlanOfAnotherBot)
+		}
+
+		req = applyPlan(req, plan)
+		price = plan.Price
+	} else {
+		if err := validatePackageLimits(&req); err != nil {
+			return err
+		}
+
+		req.Traffic *= util.GB
+	}
+
 	req.UserID = user.ID
-	req.Traffic *= util.GB
 
-	return p.repo.CreatePackage(ctx, req)
+	if req.ResellerID != nil {
+		return p.createResellerPackage(ctx, req, price)
+	}
+
+	var pack model.PackageEntity
+
+	err = p.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		// the bonus days held for the user are added to their next package that expires.
+		if req.PackageType != model.PackageTypeTrafficOnly && !req.IsTrial {
+			bonusDays, err := p.userRepo.TakeBonusDays(ctx, req.UserID)
+			if err != nil {
+				return err
+			}
+
+			req.ExpirationInDays += bonusDays
+		}
+
+		pack, err = p.repo.CreatePackage(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		_, err = p.activateNextPackage(ctx, req.UserID)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	p.createPackageAuditLog(ctx, req, pack)
+
+	if !req.IsTrial {
+		if err := p.referralSvc.RewardFirstPurchase(ctx, user.ID); err != nil {
+			p.logger.Error(err.Error())
+		}
+	}
+
+	return nil
+}
+
+// createResellerPackage creates the package out of the reseller's traffic quota, the reseller earns their
+// commission on the price of the plan.
+func (p PackageService) createResellerPackage(ctx context.Context, req model.CreatePackageRequest, price int) error {
+	if req.Traffic <= 0 {
+		return errorext.NewBadRequestError(errorext.ErrUnlimitedResellerPackage)
+	}
+
+	reseller, err := p.resellerRepo.GetResellerByID(ctx, *req.ResellerID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := p.resellerRepo.ReserveTraffic(ctx, reseller.ID, req.Traffic)
+	if err != nil {
+		return errorext.NewInternalError(p.logger, err)
+	}
+
+	if !ok {
+		return errorext.NewBadRequestError(errorext.ErrResellerTrafficExceeded)
+	}
+
+	var pack model.PackageEntity
+
+	err = p.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		pack, err = p.repo.CreatePackage(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		_, err = p.activateNextPackage(ctx, req.UserID)
+
+		return err
+	})
+	if err != nil {
+		if err := p.resellerRepo.ReleaseTraffic(ctx, reseller.ID, req.Traffic); err != nil {
+			p.logger.Error(err.Error())
+		}
+
+		return err
+	}
+
+	if commission := price * reseller.CommissionRate / 100; commission > 0 {
+		err := p.resellerRepo.CreateCommission(ctx, model.CreateResellerCommissionRequest{
+			ResellerID: reseller.ID,
+			UserID:     &req.UserID,
+			PlanID:     &req.PlanID,
+			Amount:     commission,
+		})
+		if err != nil {
+			p.logger.Error(err.Error())
+		}
+	}
+
+	p.createPackageAuditLog(ctx, req, pack)
+
+	return nil
+}
+
+func (p PackageService) TopUpPackage(ctx context.Context, req model.TopUpPackageRequest) error {
+	pack, err := p.repo.GetPackageByID(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if !pack.IsActive() {
+		return errorext.NewBadRequestError(errorext.ErrPackageNotActive)
+	}
+
+	if pack.HasUnlimitedTraffic() {
+		return errorext.NewBadRequestError(errorext.ErrPackageHasNoTrafficLimit)
+	}
+
+	traffic := req.Traffic * util.GB
+
+	if err := p.repo.ExtendPackage(ctx, model.ExtendPackageRequest{ID: pack.ID, Traffic: traffic}); err != nil {
+		return err
+	}
+
+	p.createAuditLog(ctx, req.Actor, model.AuditActionTopUpPackage, pack.ID,
+		fmt.Sprintf("traffic: %s", util.ToHumanReadableBytes(traffic)))
+
+	return nil
+}
+
+func (p PackageService) ExtendPackageExpiry(ctx context.Context, req model.ExtendPackageExpiryRequest) error {
+	pack, err := p.repo.GetPackageByID(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if !pack.IsActive() && !pack.IsReserved() {
+		return errorext.NewBadRequestError(errorext.ErrPackageNotActive)
+	}
+
+	if !pack.HasExpiry() {
+		return errorext.NewBadRequestError(errorext.ErrPackageHasNoExpiry)
+	}
+
+	if err := p.repo.ExtendPackage(ctx, model.ExtendPackageRequest{ID: pack.ID, Days: req.Days}); err != nil {
+		return err
+	}
+
+	p.createAuditLog(ctx, req.Actor, model.AuditActionExtendPackage, pack.ID, fmt.Sprintf("days: %d", req.Days))
+
+	return nil
+}
+
+func (p PackageService) TransferPackage(ctx context.Context, req model.TransferPackageRequest) error {
+	pack, err := p.repo.GetPackageByID(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if !pack.IsReserved() || pack.IsTrial || pack.DownloadTrafficUsage+pack.UploadTrafficUsage > 0 {
+		return errorext.NewBadRequestError(errorext.ErrPackageNotReserved)
+	}
+
+	user, err := p.userRepo.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		return err
+	}
+
+	if err := p.repo.TransferPackage(ctx, pack.ID, user.ID); err != nil {
+		return err
+	}
+
+	p.createAuditLog(ctx, req.Actor, model.AuditActionTransferPackage, pack.ID,
+		fmt.Sprintf("from user: %d, to user: %d", pack.UserID, user.ID))
+
+	return nil
+}
+
+// CancelPackage cancels the package and refunds the user, if the package was the user's active package,
+// their next reserved package gets activated, or their sessions are ended if they don't have any.
+func (p PackageService) CancelPackage(ctx context.Context, req model.CancelPackageRequest) error {
+	pack, err := p.repo.GetPackageByID(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if pack.CanceledAt != nil {
+		return errorext.NewBadRequestError(errorext.ErrPackageCanceled)
+	}
+
+	hasPackage := true
+
+	err = p.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		ok, err := p.repo.CancelPackage(ctx, pack.ID)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return errorext.NewBadRequestError(errorext.ErrPackageCanceled)
+		}
+
+		if req.Refund > 0 {
+			if err := p.userRepo.AddWalletBalance(ctx, pack.UserID, req.Refund); err != nil {
+				return err
+			}
+		}
+
+		if pack.IsActive() {
+			hasPackage, err = p.activateNextPackage(ctx, pack.UserID)
+		}
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	p.createAuditLog(ctx, req.Actor, model.AuditActionCancelPackage, pack.ID, fmt.Sprintf("refund: %d", req.Refund))
+
+	if !hasPackage {
+		user, err := p.userRepo.GetUserByID(ctx, pack.UserID)
+		if err != nil {
+			return err
+		}
+
+		if err := p.connectionSvc.DisconnectAllUserConnections(ctx, user.Username); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validatePackageLimits sets the default package type and checks the package has the limits its type requires,
+// fair use packages need both a cap and a throttle rate.
+func validatePackageLimits(req *model.CreatePackageRequest) error {
+	switch req.PackageType {
+	case "":
+		req.PackageType = model.PackageTypeStandard
+		fallthrough
+	case model.PackageTypeStandard:
+		if req.Traffic <= 0 {
+			return errorext.NewBadRequestError(errorext.ErrPackageTrafficRequired)
+		}
+
+		if req.ExpirationInDays <= 0 {
+			return errorext.NewBadRequestError(errorext.ErrPackageExpiryRequired)
+		}
+	case model.PackageTypeUnlimited:
+		if req.ExpirationInDays <= 0 {
+			return errorext.NewBadRequestError(errorext.ErrPackageExpiryRequired)
+		}
+
+		req.Traffic = 0
+	case model.PackageTypeTrafficOnly:
+		if req.Traffic <= 0 {
+			return errorext.NewBadRequestError(errorext.ErrPackageTrafficRequired)
+		}
+
+		// fair use packages never run out of traffic, so they'd be usable forever without an expiry.
+		if req.FairUseCap > 0 || req.ThrottleRate > 0 {
+			return errorext.NewBadRequestError(errorext.ErrFairUseWithoutExpiry)
+		}
+
+		req.ExpirationInDays = 0
+	default:
+		return errorext.NewBadRequestError(errorext.ErrInvalidPackageType)
+	}
+
+	if (req.FairUseCap > 0) != (req.ThrottleRate > 0) {
+		return errorext.NewBadRequestError(errorext.ErrInvalidFairUse)
+	}
+
+	return nil
+}
+
+// applyPlan fills the package limits from the given plan, plans are stored in bytes already.
+func applyPlan(req model.CreatePackageRequest, plan model.PlanEntity) model.CreatePackageRequest {
+	req.PackageType = plan.PackageType
+	req.Traffic = plan.TrafficLimit
+	req.MaxConnections = plan.MaxConnections
+	req.ExpirationInDays = plan.ExpirationInDays
+	req.FairUseCap = plan.FairUseCap
+	req.ThrottleRate = plan.ThrottleRate
+
+	return req
+}
+
+// activateNextPackage activates the user's next reserved package in the consumption order if the user doesn't
+// have an active package, it returns false if the user doesn't have any package left to use.
+func (p PackageService) activateNextPackage(ctx context.Context, userID int) (bool, error) {
+	packages, err := p.repo.GetUserActiveAndReservedPackages(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if packages.ActivePackage.ID != 0 {
+		return true, nil
+	}
+
+	if len(packages.ReservedPackages) <= 0 {
+		return false, nil
+	}
+
+	sortPackagesByConsumptionOrder(packages.ReservedPackages, p.cfg.ConsumptionOrder)
+
+	return true, p.repo.ActivatePackage(ctx, packages.ReservedPackages[0].ID)
+}
+
+// sortPackagesByConsumptionOrder sorts the reserved packages in the order they should be activated and charged.
+func sortPackagesByConsumptionOrder(packages []model.PackageEntity, order string) {
+	slices.SortStableFunc(packages, func(a, b model.PackageEntity) int {
+		switch order {
+		case model.PackageConsumptionOrderSmallestFirst:
+			if c := cmp.Compare(packageTrafficLimit(a), packageTrafficLimit(b)); c != 0 {
+				return c
+			}
+		case model.PackageConsumptionOrderExpiringSoonestFirst:
+			if c := cmp.Compare(packageExpiration(a), packageExpiration(b)); c != 0 {
+				return c
+			}
+		case model.PackageConsumptionOrderTrialLast:
+			if a.IsTrial != b.IsTrial {
+				if b.IsTrial {
+					return -1
+				}
+
+				return 1
+			}
+		}
+
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
+
+func packageTrafficLimit(pack model.PackageEntity) int {
+	if pack.HasUnlimitedTraffic() || pack.HasFairUse() {
+		return math.MaxInt
+	}
+
+	return pack.TrafficLimit
+}
+
+func packageExpiration(pack model.PackageEntity) int {
+	if !pack.HasExpiry() {
+		return math.MaxInt
+	}
+
+	return pack.ExpirationInDays
+}
+
+func (p PackageService) createAuditLog(ctx context.Context, actor, action string, packageID int, details string) {
+	err := p.auditLogRepo.CreateAuditLog(ctx, model.CreateAuditLogRequest{
+		Actor:      actor,
+		Action:     action,
+		Resource:   model.AuditResourcePackage,
+		ResourceID: packageID,
+		Details:    details,
+	})
+	if err != nil {
+		p.logger.Error(err.Error())
+	}
+}
+
+// createPackageAuditLog records the packages the admins and the resellers create for the users.
+func (p PackageService) createPackageAuditLog(ctx context.Context, req model.CreatePackageRequest,
+	pack model.PackageEntity) {
+	if req.Actor == "" {
+		return
+	}
+
+	p.createAuditLog(ctx, req.Actor, model.AuditActionCreatePackage, pack.ID,
+		fmt.Sprintf("user: %d, type: %s, traffic: %s, days: %d, max connections: %d", pack.UserID,
+			pack.PackageType, util.ToHumanReadableBytes(pack.TrafficLimit), pack.ExpirationInDays,
+			pack.MaxConnections))
 }
 
 func (p PackageService) GetUserActiveAndReservedPackages(ctx context.Context, userID int) (model.GetUserPackages, error) {
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/util"
+	clog "github.com/charmbracelet/log"
+)
+
+type PlanRepository interface {
+	CreatePlan(ctx context.Context, req model.CreatePlanRequest) error
+	GetPlans(ctx context.Context, req model.GetPlansRequest) (model.GetPlansResponse, error)
+}
+
+type PlanService struct {
+	repo   PlanRepository
+	logger *clog.Logger
+}
+
+func NewPlanService(repo PlanRepository, logger *clog.Logger) *PlanService {
+	return &PlanService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// CreatePlan creates a new plan, traffic limit and fair use cap are given in GB and
+// throttle rate is given in KB per second.
+func (p PlanService) CreatePlan(ctx context.Context, req model.CreatePlanRequest) error {
+	limits := model.CreatePackageRequest{
+		PackageType:      req.PackageType,
+		Traffic:          req.TrafficLimit,
+		ExpirationInDays: req.ExpirationInDays,
+		FairUseCap:       req.FairUseCap,
+		ThrottleRate:     req.ThrottleRate,
+	}
+
+	if err := validatePackageLimits(&limits); err != nil {
+		return err
+	}
+
+	if req.Price < 0 {
+		return errorext.NewBadRequestError(errorext.ErrInvalidPlanPrice)
+	}
+
+	req.PackageType = limits.PackageType
+	req.TrafficLimit = limits.Traffic * util.GB
+	req.ExpirationInDays = limits.ExpirationInDays
+	req.FairUseCap *= util.GB
+	req.ThrottleRate *= util.KB
+
+	return p.repo.CreatePlan(ctx, req)
+}
+
+func (p PlanService) GetPlans(ctx context.Context, req model.GetPlansRequest) (model.GetPlansResponse, error) {
+	return p.repo.GetPlans(ctx, req)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/ocserv"
+	clog "github.com/charmbracelet/log"
+	"github.com/skip2/go-qrcode"
+)
+
+const qrCodeSize = 512
+
+type ProfileService struct {
+	cfg    *config.VPNConfig
+	logger *clog.Logger
+}
+
+func NewProfileService(cfg *config.VPNConfig, logger *clog.Logger) *ProfileService {
+	return &ProfileService{
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (p ProfileService) GetConnectionProfile(username string) (model.ConnectionProfile, error) {
+	if p.cfg.Host == "" {
+		return model.ConnectionProfile{}, errorext.NewBadRequestError(errorext.ErrVPNHostNotSet)
+	}
+
+	address := ocserv.ServerAddress(p.cfg.Host, p.cfg.Port)
+
+	profileXML, err := ocserv.NewProfileXML(p.cfg.ProfileName, address)
+	if err != nil {
+		return model.ConnectionProfile{}, errorext.NewInternalError(p.logger, err)
+	}
+
+	uri := ocserv.AnyConnectURI(p.cfg.ProfileName, address)
+
+	qrCode, err := qrcode.Encode(uri, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		return model.ConnectionProfile{}, errorext.NewInternalError(p.logger, err)
+	}
+
+	return model.ConnectionProfile{
+		Name:       p.cfg.ProfileName,
+		Address:    address,
+		Command:    ocserv.OpenconnectCommand(address, username, p.cfg.CertPin),
+		URI:        uri,
+		ProfileXML: profileXML,
+		QRCode:     qrCode,
+	}, nil
+}
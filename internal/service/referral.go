@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/util"
+	clog "github.com/charmbracelet/log"
+	"gorm.io/gorm"
+)
+
+type ReferralRepository interface {
+	SaveInvite(ctx context.Context, externalID, referralCode string) error
+	GetInvite(ctx context.Context, externalID string) (model.ReferralInviteEntity, error)
+	CreateReward(ctx context.Context, req model.CreateReferralRewardRequest) (bool, error)
+	GetReferralStats(ctx context.Context, user model.UserEntity) (model.ReferralStats, error)
+}
+
+type ReferralUserRepository interface {
+	GetUserByID(ctx context.Context, id int) (model.UserEntity, error)
+	GetUserByUsername(ctx context.Context, username string) (model.UserEntity, error)
+	GetUserByReferralCode(ctx context.Context, referralCode string) (model.UserEntity, error)
+	AddWalletBalance(ctx context.Context, id, amount int) error
+	AddBonusDays(ctx context.Context, id, days int) error
+}
+
+type ReferralPackageRepository interface {
+	GetUserActiveAndReservedPackages(ctx context.Context, userID int) (model.GetUserPackages, error)
+	ExtendPackage(ctx context.Context, req model.ExtendPackageRequest) error
+	CreatePackage(ctx context.Context, req model.CreatePackageRequest) (model.PackageEntity, error)
+}
+
+type ReferralTransactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type ReferralService struct {
+	cfg         *config.ReferralConfig
+	logger      *clog.Logger
+	repo        ReferralRepository
+	userRepo    ReferralUserRepository
+	packageRepo ReferralPackageRepository
+	transactor  ReferralTransactor
+}
+
+func NewReferralService(cfg *config.ReferralConfig, logger *clog.Logger, repo ReferralRepository,
+	userRepo ReferralUserRepository, packageRepo ReferralPackageRepository,
+	transactor ReferralTransactor) *ReferralService {
+	return &ReferralService{
+		cfg:         cfg,
+		logger:      logger,
+		repo:        repo,
+		userRepo:    userRepo,
+		packageRepo: packageRepo,
+		transactor:  transactor,
+	}
+}
+
+// SaveInvite keeps the referral code a telegram user has started the bot with, so it can be
+// attached to the user when they create their account.
+func (r ReferralService) SaveInvite(ctx context.Context, externalID, referralCode string) error {
+	if !r.cfg.Activated {
+		return errorext.ErrReferralDeactivated
+	}
+
+	referrer, err := r.userRepo.GetUserByReferralCode(ctx, referralCode)
+	if err != nil {
+		return err
+	}
+
+	if referrer.ExternalID == externalID {
+		return errorext.ErrSelfReferral
+	}
+
+	if err := r.repo.SaveInvite(ctx, externalID, referralCode); err != nil {
+		return errorext.NewInternalError(r.logger, err)
+	}
+
+	return nil
+}
+
+func (r ReferralService) GetInviteReferral(ctx context.Context, externalID string) (*string, error) {
+	if !r.cfg.Activated {
+		return nil, nil
+	}
+
+	invite, err := r.repo.GetInvite(ctx, externalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errorext.NewInternalError(r.logger, err)
+	}
+
+	return &invite.ReferralCode, nil
+}
+
+// RewardFirstPurchase rewards both the referrer and the referee of the given user, rewards are granted
+// only once per referee, so calling it on later purchases is a no-op.
+func (r ReferralService) RewardFirstPurchase(ctx context.Context, userID int) error {
+	if !r.cfg.Activated {
+		return nil
+	}
+
+	user, err := r.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Referral == nil {
+		return nil
+	}
+
+	referrer, err := r.userRepo.GetUserByReferralCode(ctx, *user.Referral)
+	if err != nil {
+		return err
+	}
+
+	req := model.CreateReferralRewardRequest{
+		ReferrerID: referrer.ID,
+		RefereeID:  user.ID,
+		ReferrerReward: model.ReferralReward{
+			Traffic: int(r.cfg.ReferrerTrafficBonus * util.GB),
+			Days:    r.cfg.ReferrerDaysBonus,
+			Credit:  r.cfg.ReferrerWalletCredit,
+		},
+		RefereeReward: model.ReferralReward{
+			Traffic: int(r.cfg.RefereeTrafficBonus * util.GB),
+			Days:    r.cfg.RefereeDaysBonus,
+			Credit:  r.cfg.RefereeWalletCredit,
+		},
+	}
+
+	err = r.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		created, err := r.repo.CreateReward(ctx, req)
+		if err != nil || !created {
+			return err
+		}
+
+		if err := r.grantReward(ctx, req.ReferrerID, req.ReferrerReward); err != nil {
+			return err
+		}
+
+		return r.grantReward(ctx, req.RefereeID, req.RefereeReward)
+	})
+	if err != nil {
+		return errorext.NewInternalError(r.logger, err)
+	}
+
+	return nil
+}
+
+// grantReward adds the bonus traffic and days to the user's active package, if the user doesn't have any,
+// the traffic is given as a bonus package and the days are added to their reserved package, or held for
+// their next package if they don't have one either.
+func (r ReferralService) grantReward(ctx context.Context, userID int, reward model.ReferralReward) error {
+	if reward.Credit > 0 {
+		if err := r.userRepo.AddWalletBalance(ctx, userID, reward.Credit); err != nil {
+			return err
+		}
+	}
+
+	if reward.Traffic <= 0 && reward.Days <= 0 {
+		return nil
+	}
+
+	packages, err := r.packageRepo.GetUserActiveAndReservedPackages(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if packages.ActivePackage.ID != 0 {
+		return r.packageRepo.ExtendPackage(ctx, model.ExtendPackageRequest{
+			ID:      packages.ActivePackage.ID,
+			Traffic: reward.Traffic,
+			Days:    reward.Days,
+		})
+	}
+
+	if reward.Traffic <= 0 {
+		if len(packages.ReservedPackages) > 0 {
+			return r.packageRepo.ExtendPackage(ctx, model.ExtendPackageRequest{
+				ID:   packages.ReservedPackages[0].ID,
+				Days: reward.Days,
+			})
+		}
+
+		return r.userRepo.AddBonusDays(ctx, userID, reward.Days)
+	}
+
+	bonusPackage := model.CreatePackageRequest{
+		UserID:           userID,
+		Traffic:          reward.Traffic,
+		MaxConnections:   r.cfg.BonusPackageMaxConnections,
+		ExpirationInDays: r.cfg.BonusPackageExpiration,
+	}
+
+	if reward.Days > 0 {
+		bonusPackage.ExpirationInDays = reward.Days
+	}
+
+	_, err = r.packageRepo.CreatePackage(ctx, bonusPackage)
+
+	return err
+}
+
+func (r ReferralService) GetReferralStats(ctx context.Context, username string) (model.ReferralStats, error) {
+	user, err := r.userRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return model.ReferralStats{}, err
+	}
+
+	return r.repo.GetReferralStats(ctx, user)
+}
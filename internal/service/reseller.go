@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/password"
+	"github.com/alir32a/jupiter/pkg/util"
+	clog "github.com/charmbracelet/log"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// apiTokenSize is the size of the tokens the resellers call the reseller api with.
+const apiTokenSize = 32
+
+type ResellerRepository interface {
+	CreateReseller(ctx context.Context, req model.CreateResellerRequest) (model.AdminEntity, error)
+	GetResellerByID(ctx context.Context, id int) (model.AdminEntity, error)
+	GetResellerByTokenHash(ctx context.Context, hash string) (model.AdminEntity, error)
+	GetResellers(ctx context.Context, req model.GetResellersRequest) (model.GetResellersResponse, error)
+	SetAPITokenHash(ctx context.Context, id int, hash string) error
+	AddQuota(ctx context.Context, id, users, traffic int) error
+	CreateCommission(ctx context.Context, req model.CreateResellerCommissionRequest) error
+	GetCommissions(ctx context.Context, req model.GetResellerCommissionsRequest) (model.GetResellerCommissionsResponse, error)
+	GetCommissionBalance(ctx context.Context, resellerID int) (int, error)
+	LockReseller(ctx context.Context, id int) error
+}
+
+type ResellerAdminRepository interface {
+	GetAdminByUsername(ctx context.Context, username string) (model.AdminEntity, error)
+}
+
+type ResellerBotRepository interface {
+	GetBotByID(ctx context.Context, id int) (model.BotEntity, error)
+}
+
+type ResellerAuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, req model.CreateAuditLogRequest) error
+}
+
+type ResellerUserService interface {
+	CreatePanelUser(ctx context.Context, req model.CreatePanelUserRequest) (model.CreateUserResponse, error)
+	GetAllUsers(ctx context.Context, req model.GetAllUsersRequest) (model.GetAllUsersResponse, error)
+}
+
+type ResellerPackageService interface {
+	CreatePackage(ctx context.Context, req model.CreatePackageRequest) error
+}
+
+type ResellerTransactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ResellerService manages the resellers, resellers create users and packages for their own customers within
+// the quota they've bought and earn a commission on the plans they sell.
+type ResellerService struct {
+	logger       *clog.Logger
+	repo         ResellerRepository
+	adminRepo    ResellerAdminRepository
+	botRepo      ResellerBotRepository
+	auditLogRepo ResellerAuditLogRepository
+	userSvc      ResellerUserService
+	packageSvc   ResellerPackageService
+	transactor   ResellerTransactor
+}
+
+func NewResellerService(logger *clog.Logger, repo ResellerRepository, adminRepo ResellerAdminRepository,
+	botRepo ResellerBotRepository, auditLogRepo ResellerAuditLogRepository, userSvc ResellerUserService,
+	packageSvc ResellerPackageService, transactor ResellerTransactor) *ResellerService {
+	return &ResellerService{
+		logger:       logger,
+		repo:         repo,
+		adminRepo:    adminRepo,
+		botRepo:      botRepo,
+		auditLogRepo: auditLogRepo,
+		userSvc:      userSvc,
+		packageSvc:   packageSvc,
+		transactor:   transactor,
+	}
+}
+
+func (r ResellerService) CreateReseller(ctx context.Context, req model.CreateResellerRequest) (model.AdminEntity, error) {
+	req.Username = strings.TrimSpace(req.Username)
+
+	if !usernamePattern.MatchString(req.Username) {
+		return model.AdminEntity{}, errorext.NewBadRequestError(errorext.ErrInvalidUsername)
+	}
+
+	if err := validatePassword(req.Username, req.Password); err != nil {
+		return model.AdminEntity{}, errorext.NewBadRequestError(err)
+	}
+
+	if req.UserQuota < 0 || req.TrafficQuota < 0 {
+		return model.AdminEntity{}, errorext.NewBadRequestError(errorext.ErrInvalidResellerQuota)
+	}
+
+	if req.CommissionRate < 0 || req.CommissionRate > 100 {
+		return model.AdminEntity{}, errorext.NewBadRequestError(errorext.ErrInvalidCommissionRate)
+	}
+
+	_, err := r.adminRepo.GetAdminByUsername(ctx, req.Username)
+	if err == nil {
+		return model.AdminEntity{}, errorext.NewBadRequestError(errorext.ErrUsernameTaken)
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.AdminEntity{}, errorext.NewInternalError(r.logger, err)
+	}
+
+	req.Password, err = password.HashPassword(req.Password)
+	if err != nil {
+		return model.AdminEntity{}, errorext.NewInternalError(r.logger, err)
+	}
+
+	req.TrafficQuota *= util.GB
+
+	reseller, err := r.repo.CreateReseller(ctx, req)
+	if err != nil {
+		return model.AdminEntity{}, errorext.NewInternalError(r.logger, err)
+	}
+
+	r.createAuditLog(ctx, req.Actor, model.AuditActionCreateReseller, reseller.ID,
+		fmt.Sprintf("username: %s, users: %d, traffic: %s, commission: %d%%", reseller.Username,
+			reseller.UserQuota, util.ToHumanReadableBytes(reseller.TrafficQuota), reseller.CommissionRate))
+
+	return reseller, nil
+}
+
+func (r ResellerService) GetResellers(ctx context.Context, req model.GetResellersRequest) (model.GetResellersResponse, error) {
+	resp, err := r.repo.GetResellers(ctx, req)
+	if err != nil {
+		return model.GetResellersResponse{}, errorext.NewInternalError(r.logger, err)
+	}
+
+	return resp, nil
+}
+
+// AddQuota adds the users and the traffic the reseller has bought to their quota.
+func (r ResellerService) AddQuota(ctx context.Context, req model.AddResellerQuotaRequest) error {
+	if req.Users < 0 || req.Traffic < 0 {
+		return errorext.NewBadRequestError(errorext.ErrInvalidResellerQuota)
+	}
+
+	reseller, err := r.repo.GetResellerByID(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	traffic := req.Traffic * util.GB
+
+	if err := r.repo.AddQuota(ctx, reseller.ID, req.Users, traffic); err != nil {
+		return errorext.NewInternalError(r.logger, err)
+	}
+
+	r.createAuditLog(ctx, req.Actor, model.AuditActionAddResellerQuota, reseller.ID,
+		fmt.Sprintf("users: %d, traffic: %s", req.Users, util.ToHumanReadableBytes(traffic)))
+
+	return nil
+}
+
+// RotateAPIToken issues a new api token for the reseller, the previous token stops working. Only the hash of
+// the token is stored, so it's returned once.
+func (r ResellerService) RotateAPIToken(ctx context.Context, id int, actor string) (string, error) {
+	reseller, err := r.repo.GetResellerByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := password.NewToken(apiTokenSize)
+	if err != nil {
+		return "", errorext.NewInternalError(r.logger, err)
+	}
+
+	if err := r.repo.SetAPITokenHash(ctx, reseller.ID, password.HashToken(token)); err != nil {
+		return "", errorext.NewInternalError(r.logger, err)
+	}
+
+	r.createAuditLog(ctx, actor, model.AuditActionRotateAPIToken, reseller.ID, "")
+
+	return token, nil
+}
+
+// Authenticate returns the active reseller the api token belongs to.
+func (r ResellerService) Authenticate(ctx context.Context, token string) (model.AdminEntity, error) {
+	if token == "" {
+		return model.AdminEntity{}, errorext.NewNotFoundError(errorext.ErrInvalidAPIToken)
+	}
+
+	return r.repo.GetResellerByTokenHash(ctx, password.HashToken(token))
+}
+
+// CreateUser creates a user for the reseller, the user can only be created in the main bot or in the bots
+// of the reseller.
+func (r ResellerService) CreateUser(ctx context.Context, resellerID int,
+	req model.CreatePanelUserRequest) (model.CreateUserResponse, error) {
+	if req.BotID != model.MainBotID {
+		bot, err := r.botRepo.GetBotByID(ctx, req.BotID)
+		if err != nil {
+			return model.CreateUserResponse{}, err
+		}
+
+		if bot.AdminID != resellerID {
+			return model.CreateUserResponse{}, errorext.NewNotFoundError(errorext.ErrBotNotFound)
+		}
+	}
+
+	req.ResellerID = &resellerID
+
+	return r.userSvc.CreatePanelUser(ctx, req)
+}
+
+func (r ResellerService) GetUsers(ctx context.Context, resellerID int,
+	req model.GetAllUsersRequest) (model.GetAllUsersResponse, error) {
+	req.ResellerID = &resellerID
+
+	return r.userSvc.GetAllUsers(ctx, req)
+}
+
+func (r ResellerService) CreatePackage(ctx context.Context, resellerID int, req model.CreatePackageRequest) error {
+	req.ResellerID = &resellerID
+	req.IsTrial = false
+
+	return r.packageSvc.CreatePackage(ctx, req)
+}
+
+func (r ResellerService) GetCommissions(ctx context.Context,
+	req model.GetResellerCommissionsRequest) (model.GetResellerCommissionsResponse, error) {
+	resp, err := r.repo.GetCommissions(ctx, req)
+	if err != nil {
+		return model.GetResellerCommissionsResponse{}, errorext.NewInternalError(r.logger, err)
+	}
+
+	return resp, nil
+}
+
+// CreatePayout records the commission paid to the reseller, it can't be more than their balance.
+func (r ResellerService) CreatePayout(ctx context.Context, req model.CreatePayoutRequest) error {
+	reseller, err := r.repo.GetResellerByID(ctx, req.ResellerID)
+	if err != nil {
+		return err
+	}
+
+	if req.Amount <= 0 {
+		return errorext.NewBadRequestError(errorext.ErrInvalidPayoutAmount)
+	}
+
+	// the reseller is locked while the payout is checked against their balance, so concurrent payouts
+	// can't overdraw it.
+	err = r.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := r.repo.LockReseller(ctx, reseller.ID); err != nil {
+			return errorext.NewInternalError(r.logger, err)
+		}
+
+		balance, err := r.repo.GetCommissionBalance(ctx, reseller.ID)
+		if err != nil {
+			return errorext.NewInternalError(r.logger, err)
+		}
+
+		if req.Amount > balance {
+			return errorext.NewBadRequestError(errorext.ErrInvalidPayoutAmount)
+		}
+
+		err = r.repo.CreateCommission(ctx, model.CreateResellerCommissionRequest{
+			ResellerID: reseller.ID,
+			Amount:     -req.Amount,
+			Note:       req.Note,
+		})
+		if err != nil {
+			return errorext.NewInternalError(r.logger, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.createAuditLog(ctx, req.Actor, model.AuditActionPayout, reseller.ID, fmt.Sprintf("amount: %d", req.Amount))
+
+	return nil
+}
+
+func (r ResellerService) createAuditLog(ctx context.Context, actor, action string, resellerID int, details string) {
+	err := r.auditLogRepo.CreateAuditLog(ctx, model.CreateAuditLogRequest{
+		Actor:      actor,
+		Action:     action,
+		Resource:   model.AuditResourceReseller,
+		ResourceID: resellerID,
+		Details:    details,
+	})
+	if err != nil {
+		r.logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/alir32a/jupiter/config"
+	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
+	"github.com/alir32a/jupiter/internal/model"
+	"github.com/alir32a/jupiter/pkg/tg"
+	clog "github.com/charmbracelet/log"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var supportTicketStatuses = []string{
+	model.SupportTicketStatusOpen,
+	model.SupportTicketStatusAnswered,
+	model.SupportTicketStatusClosed,
+}
+
+type SupportRepository interface {
+	CreateUserMessage(ctx context.Context, req model.CreateSupportMessageRequest) (model.CreateSupportMessageResponse, error)
+	CreateAdminMessage(ctx context.Context, req model.ReplySupportTicketRequest) (model.SupportMessageEntity, error)
+	SetForwardedMessage(ctx context.Context, messageID, forwardedMessageID int) error
+	GetTicketByID(ctx context.Context, id int) (model.SupportTicketEntity, error)
+	GetTicketByForwardedMessage(ctx context.Context, forwardedMessageID int) (model.SupportTicketEntity, error)
+	GetTickets(ctx context.Context, req model.GetSupportTicketsRequest) (model.GetSupportTicketsResponse, error)
+	GetTicketMessages(ctx context.Context, ticketID int) ([]model.SupportMessageEntity, error)
+	CloseTicket(ctx context.Context, id int) error
+}
+
+type SupportUserRepository interface {
+	GetUserByID(ctx context.Context, id int) (model.UserEntity, error)
+}
+
+type SupportSender interface {
+	SendMessage(botID int, req tg.SendMessageRequest) (tg.Message, error)
+}
+
+type SupportRenderer interface {
+	Render(ctx context.Context, lang, key string, data tg.TemplateData) (model.RenderedMessage, error)
+}
+
+// SupportService keeps the conversations between the users and the admins, the users' messages are copied
+// to the support chat of the main bot if there is one, and the admins' replies are sent back to the users through
+// the bot the ticket was opened in.
+type SupportService struct {
+	cfg      *config.MainBotConfig
+	logger   *clog.Logger
+	repo     SupportRepository
+	userRepo SupportUserRepository
+	sender   SupportSender
+	renderer SupportRenderer
+}
+
+func NewSupportService(cfg *config.MainBotConfig, logger *clog.Logger, repo SupportRepository,
+	userRepo SupportUserRepository, sender SupportSender, renderer SupportRenderer) *SupportService {
+	return &SupportService{
+		cfg:      cfg,
+		logger:   logger,
+		repo:     repo,
+		userRepo: userRepo,
+		sender:   sender,
+		renderer: renderer,
+	}
+}
+
+// CreateTicket adds the user's message to their ticket, a ticket is opened if the user doesn't have one.
+func (s SupportService) CreateTicket(ctx context.Context, req model.CreateSupportMessageRequest) (model.SupportTicketEntity, error) {
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		return model.SupportTicketEntity{}, errorext.NewBadRequestError(errorext.ErrSupportMessageRequired)
+	}
+
+	resp, err := s.repo.CreateUserMessage(ctx, req)
+	if err != nil {
+		return model.SupportTicketEntity{}, errorext.NewInternalError(s.logger, err)
+	}
+
+	// the ticket is in the panel anyway, so the user isn't bothered if the support chat can't be reached.
+	s.forward(ctx, resp, req.From)
+
+	return resp.Ticket, nil
+}
+
+// Reply sends the admin's reply to the user and marks the ticket as answered.
+func (s SupportService) Reply(ctx context.Context, req model.ReplySupportTicketRequest) error {
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		return errorext.NewBadRequestError(errorext.ErrSupportMessageRequired)
+	}
+
+	ticket, err := s.repo.GetTicketByID(ctx, req.TicketID)
+	if err != nil {
+		return err
+	}
+
+	if ticket.Status == model.SupportTicketStatusClosed {
+		return errorext.NewBadRequestError(errorext.ErrTicketClosed)
+	}
+
+	if _, err := s.repo.CreateAdminMessage(ctx, req); err != nil {
+		return errorext.NewInternalError(s.logger, err)
+	}
+
+	err = s.send(ctx, ticket, locale.MsgSupportReply, tg.TemplateData{"ID": ticket.ID, "Text": req.Text})
+	if err != nil {
+		return errorext.NewInternalError(s.logger, err)
+	}
+
+	return nil
+}
+
+// ReplyToForwarded replies to the ticket of a message that was copied to the support chat.
+func (s SupportService) ReplyToForwarded(ctx context.Context, forwardedMessageID int, adminUsername, text string) (int, error) {
+	ticket, err := s.repo.GetTicketByForwardedMessage(ctx, forwardedMessageID)
+	if err != nil {
+		return 0, err
+	}
+
+	return ticket.ID, s.Reply(ctx, model.ReplySupportTicketRequest{
+		TicketID:      ticket.ID,
+		AdminUsername: adminUsername,
+		Text:          text,
+	})
+}
+
+// CloseTicket closes the ticket and lets the user know, the user's next message opens a new ticket.
+func (s SupportService) CloseTicket(ctx context.Context, id int) error {
+	ticket, err := s.repo.GetTicketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if ticket.Status == model.SupportTicketStatusClosed {
+		return errorext.NewBadRequestError(errorext.ErrTicketClosed)
+	}
+
+	if err := s.repo.CloseTicket(ctx, id); err != nil {
+		return errorext.NewInternalError(s.logger, err)
+	}
+
+	if err := s.send(ctx, ticket, locale.MsgTicketClosed, tg.TemplateData{"ID": ticket.ID}); err != nil {
+		s.logger.Error(err.Error())
+	}
+
+	return nil
+}
+
+func (s SupportService) GetTickets(ctx context.Context, req model.GetSupportTicketsRequest) (model.GetSupportTicketsResponse, error) {
+	if req.Status != "" && !slices.Contains(supportTicketStatuses, req.Status) {
+		return model.GetSupportTicketsResponse{}, errorext.NewBadRequestError(errorext.ErrInvalidTicketStatus)
+	}
+
+	resp, err := s.repo.GetTickets(ctx, req)
+	if err != nil {
+		return model.GetSupportTicketsResponse{}, errorext.NewInternalError(s.logger, err)
+	}
+
+	return resp, nil
+}
+
+// GetTicket returns the ticket along with all of its messages.
+func (s SupportService) GetTicket(ctx context.Context, id int) (model.GetSupportTicketResponse, error) {
+	ticket, err := s.repo.GetTicketByID(ctx, id)
+	if err != nil {
+		return model.GetSupportTicketResponse{}, err
+	}
+
+	messages, err := s.repo.GetTicketMessages(ctx, id)
+	if err != nil {
+		return model.GetSupportTicketResponse{}, errorext.NewInternalError(s.logger, err)
+	}
+
+	return model.GetSupportTicketResponse{Ticket: ticket, Messages: messages}, nil
+}
+
+// forward copies the user's message to the support chat as plain text, admins answer by replying to the copy.
+func (s SupportService) forward(ctx context.Context, resp model.CreateSupportMessageResponse, from string) {
+	if s.cfg.SupportChatID == 0 {
+		return
+	}
+
+	text := fmt.Sprintf("🎫 ticket #%d from %s\n\n%s\n\nreply to this message to answer, or /close %d",
+		resp.Ticket.ID, from, resp.Message.Text, resp.Ticket.ID)
+
+	sent, err := s.sender.SendMessage(model.MainBotID, tg.SendMessageRequest{ChatID: s.cfg.SupportChatID, Text: text})
+	if err != nil {
+		s.logger.Error(err.Error(), "ticket_id", resp.Ticket.ID)
+
+		return
+	}
+
+	if err := s.repo.SetForwardedMessage(ctx, resp.Message.ID, sent.MessageID); err != nil {
+		s.logger.Error(err.Error(), "ticket_id", resp.Ticket.ID)
+	}
+}
+
+// send sends the message to the chat the ticket was opened from, in the language of the ticket's user.
+func (s SupportService) send(ctx context.Context, ticket model.SupportTicketEntity, key string, data tg.TemplateData) error {
+	chatID, err := strconv.Atoi(ticket.ChatID)
+	if err != nil {
+		return err
+	}
+
+	lang := locale.DefaultLanguage
+	if ticket.UserID != nil {
+		if user, err := s.userRepo.GetUserByID(ctx, *ticket.UserID); err == nil {
+			lang = userLanguage(user)
+		}
+	}
+
+	msg, err := s.renderer.Render(ctx, lang, key, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sender.SendMessage(ticket.BotID, tg.SendMessageRequest{ChatID: chatID, Text: msg.Text,
+		ParseMode: msg.ParseMode})
+
+	return err
+}
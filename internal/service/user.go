@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/alir32a/jupiter/config"
 	"github.com/alir32a/jupiter/internal/errorext"
+	"github.com/alir32a/jupiter/internal/locale"
 	"github.com/alir32a/jupiter/internal/model"
 	"github.com/alir32a/jupiter/pkg/ocserv"
 	"github.com/alir32a/jupiter/pkg/password"
@@ -12,6 +13,9 @@ import (
 	clog "github.com/charmbracelet/log"
 	"github.com/rs/xid"
 	"gorm.io/gorm"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type UserRepository interface {
@@ -23,10 +27,33 @@ type UserRepository interface {
 	GetUsersStat(ctx context.Context) (model.GetUsersStatResponse, error)
 	GetAllUsers(ctx context.Context, req model.GetAllUsersRequest) (model.GetAllUsersResponse, error)
 	GetUserByID(ctx context.Context, id int) (model.UserEntity, error)
+	GetUserByIdentity(ctx context.Context, provider, externalID string) (model.UserEntity, error)
+	GetUserIdentities(ctx context.Context, userID int) ([]model.UserIdentityEntity, error)
+	CreateLinkCode(ctx context.Context, req model.CreateLinkCodeRequest) error
+	LinkIdentity(ctx context.Context, req model.LinkIdentityRequest) (model.UserEntity, error)
+	DeleteExpiredLinkCodes(ctx context.Context) error
+	GetActiveAccount(ctx context.Context, provider, externalID string) (model.UserEntity, error)
+	SetActiveAccount(ctx context.Context, provider, externalID string, userID int) error
+	GetOwnedAccounts(ctx context.Context, ownerID int) ([]model.UserEntity, error)
+	SetNotificationsEnabled(ctx context.Context, id int, enabled bool) error
+	SetLanguage(ctx context.Context, id int, language string) error
+	ClaimTrial(ctx context.Context, id int) (bool, error)
+	AddCaptchaFailure(ctx context.Context, id, maxFailures int) error
 }
 
 type UserPackageRepository interface {
-	CreatePackage(ctx context.Context, req model.CreatePackageRequest) error
+	CreatePackage(ctx context.Context, req model.CreatePackageRequest) (model.PackageEntity, error)
+}
+
+// UserResellerRepository keeps the number of users the resellers have created within their quota.
+type UserResellerRepository interface {
+	ReserveUser(ctx context.Context, id int) (bool, error)
+	ReleaseUser(ctx context.Context, id int) error
+}
+
+type UserNotifier interface {
+	NotifyBanned(ctx context.Context, user model.UserEntity)
+	NotifyUnbanned(ctx context.Context, user model.UserEntity)
 }
 
 type UserService struct {
@@ -35,24 +62,60 @@ type UserService struct {
 	ocservClient *ocserv.Client
 	repo         UserRepository
 	packageRepo  UserPackageRepository
+	resellerRepo UserResellerRepository
+	notifier     UserNotifier
 }
 
 func NewUserService(cfg *config.Config, logger *clog.Logger, ocservClient *ocserv.Client, repo UserRepository,
-	packageRepo UserPackageRepository) *UserService {
+	packageRepo UserPackageRepository, resellerRepo UserResellerRepository, notifier UserNotifier) *UserService {
 	return &UserService{
 		cfg:          cfg,
 		logger:       logger,
 		ocservClient: ocservClient,
 		repo:         repo,
 		packageRepo:  packageRepo,
+		resellerRepo: resellerRepo,
+		notifier:     notifier,
 	}
 }
 
 func (u UserService) CreateUser(ctx context.Context, req model.CreateUserRequest) (model.CreateUserResponse, error) {
+	if req.ExternalID != "" && req.OwnerID == nil {
+		if _, err := u.repo.GetUserByIdentity(ctx, req.UserType, req.ExternalID); err == nil {
+			return model.CreateUserResponse{}, errorext.NewBadRequestError(errorext.ErrUserAlreadyExists)
+		}
+	}
+
+	if _, err := u.repo.GetUserByUsername(ctx, req.Username); err == nil {
+		return model.CreateUserResponse{}, errorext.NewBadRequestError(errorext.ErrUsernameTaken)
+	}
+
+	if req.ResellerID != nil {
+		ok, err := u.resellerRepo.ReserveUser(ctx, *req.ResellerID)
+		if err != nil {
+			return model.CreateUserResponse{}, errorext.NewInternalError(u.logger, err)
+		}
+
+		if !ok {
+			return model.CreateUserResponse{}, errorext.NewBadRequestError(errorext.ErrResellerUserQuotaExceeded)
+		}
+	}
+
+	// owned accounts don't get a trial, otherwise a single telegram account could take any number of them,
+	// the customers of the resellers get their packages from the reseller.
+	trial := u.cfg.TrialPackage.Activated && req.OwnerID == nil && req.ResellerID == nil
+	req.DeferTrial = trial && req.DeferTrial
+
 	req.ReferralCode = xid.New().String()
 
 	user, err := u.repo.CreateUser(ctx, req)
 	if err != nil {
+		if req.ResellerID != nil {
+			if err := u.resellerRepo.ReleaseUser(ctx, *req.ResellerID); err != nil {
+				u.logger.Error(err.Error())
+			}
+		}
+
 		return model.CreateUserResponse{}, errorext.NewInternalError(u.logger, err)
 	}
 
@@ -62,15 +125,8 @@ func (u UserService) CreateUser(ctx context.Context, req model.CreateUserRequest
 		return model.CreateUserResponse{}, errorext.NewInternalError(u.logger, err)
 	}
 
-	if u.cfg.TrialPackage.Activated {
-		err := u.packageRepo.CreatePackage(ctx, model.CreatePackageRequest{
-			UserID:           user.ID,
-			Traffic:          int(u.cfg.TrialPackage.TrafficLimit * util.GB),
-			MaxConnections:   u.cfg.TrialPackage.MaxConnections,
-			IsTrial:          true,
-			ExpirationInDays: u.cfg.TrialPackage.ExpirationInDays,
-		})
-		if err != nil {
+	if trial && !req.DeferTrial {
+		if err := u.createTrialPackage(ctx, user.ID); err != nil {
 			u.logger.Error(err.Error())
 		}
 	}
@@ -81,6 +137,46 @@ func (u UserService) CreateUser(ctx context.Context, req model.CreateUserRequest
 	}, nil
 }
 
+// GrantTrial gives the deferred trial package to the user once they've passed the trial gate.
+func (u UserService) GrantTrial(ctx context.Context, id int) error {
+	claimed, err := u.repo.ClaimTrial(ctx, id)
+	if err != nil {
+		return errorext.NewInternalError(u.logger, err)
+	}
+
+	if !claimed {
+		return errorext.NewBadRequestError(errorext.ErrTrialUnavailable)
+	}
+
+	if err := u.createTrialPackage(ctx, id); err != nil {
+		return errorext.NewInternalError(u.logger, err)
+	}
+
+	return nil
+}
+
+// AddCaptchaFailure counts a wrong captcha answer of the user, it returns the user, whose trial is no longer
+// pending once they've run out of attempts.
+func (u UserService) AddCaptchaFailure(ctx context.Context, id int) (model.UserEntity, error) {
+	if err := u.repo.AddCaptchaFailure(ctx, id, u.cfg.TrialPackage.CaptchaAttempts); err != nil {
+		return model.UserEntity{}, errorext.NewInternalError(u.logger, err)
+	}
+
+	return u.repo.GetUserByID(ctx, id)
+}
+
+func (u UserService) createTrialPackage(ctx context.Context, userID int) error {
+	_, err := u.packageRepo.CreatePackage(ctx, model.CreatePackageRequest{
+		UserID:           userID,
+		Traffic:          int(u.cfg.TrialPackage.TrafficLimit * util.GB),
+		MaxConnections:   u.cfg.TrialPackage.MaxConnections,
+		IsTrial:          true,
+		ExpirationInDays: u.cfg.TrialPackage.ExpirationInDays,
+	})
+
+	return err
+}
+
 func (u UserService) ChangePassword(ctx context.Context, username string) (string, error) {
 	_, err := u.repo.GetUsersByUsernames(ctx, username)
 	if err != nil {
@@ -100,6 +196,24 @@ func (u UserService) ChangePassword(ctx context.Context, username string) (strin
 	return pass, nil
 }
 
+// SetPassword sets the password of the active account, the password must pass the strength policy.
+func (u UserService) SetPassword(ctx context.Context, externalID, pass string) error {
+	user, err := u.GetActiveAccount(ctx, externalID)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePassword(user.Username, pass); err != nil {
+		return errorext.NewBadRequestError(err)
+	}
+
+	if err := u.ocservClient.ChangePassword(ctx, user.Username, pass); err != nil {
+		return errorext.NewInternalError(u.logger, err)
+	}
+
+	return nil
+}
+
 func (u UserService) BanUser(ctx context.Context, userID int) error {
 	user, err := u.repo.GetUserByID(ctx, userID)
 	if err != nil {
@@ -110,7 +224,13 @@ func (u UserService) BanUser(ctx context.Context, userID int) error {
 		return err
 	}
 
-	return u.repo.BanUser(ctx, userID)
+	if err := u.repo.BanUser(ctx, userID); err != nil {
+		return err
+	}
+
+	u.notifier.NotifyBanned(ctx, user)
+
+	return nil
 }
 
 func (u UserService) UnbanUser(ctx context.Context, userID int) error {
@@ -123,7 +243,40 @@ func (u UserService) UnbanUser(ctx context.Context, userID int) error {
 		return err
 	}
 
-	return u.repo.UnbanUser(ctx, userID)
+	if err := u.repo.UnbanUser(ctx, userID); err != nil {
+		return err
+	}
+
+	u.notifier.NotifyUnbanned(ctx, user)
+
+	return nil
+}
+
+func (u UserService) SetNotificationsEnabled(ctx context.Context, externalID string, enabled bool) error {
+	user, err := u.GetUserByExternalID(ctx, externalID)
+	if err != nil {
+		return err
+	}
+
+	return u.repo.SetNotificationsEnabled(ctx, user.ID, enabled)
+}
+
+func (u UserService) SetLanguage(ctx context.Context, externalID, language string) error {
+	if !locale.IsSupported(language) {
+		return errorext.NewBadRequestError(errorext.ErrUnsupportedLanguage)
+	}
+
+	user, err := u.GetUserByExternalID(ctx, externalID)
+	if err != nil {
+		return err
+	}
+
+	return u.repo.SetLanguage(ctx, user.ID, language)
+}
+
+// GetUserByExternalID returns the user the telegram account is linked to.
+func (u UserService) GetUserByExternalID(ctx context.Context, externalID string) (model.UserEntity, error) {
+	return u.repo.GetUserByIdentity(ctx, model.IdentityProviderTelegram, externalID)
 }
 
 func (u UserService) GetUserByUsername(ctx context.Context, username string) (model.UserEntity, error) {
@@ -137,3 +290,23 @@ func (u UserService) GetUsersStat(ctx context.Context) (model.GetUsersStatRespon
 func (u UserService) GetAllUsers(ctx context.Context, req model.GetAllUsersRequest) (model.GetAllUsersResponse, error) {
 	return u.repo.GetAllUsers(ctx, req)
 }
+
+func validatePassword(username, pass string) error {
+	if strings.ContainsFunc(pass, unicode.IsSpace) {
+		return errorext.ErrPasswordHasWhitespace
+	}
+
+	if utf8.RuneCountInString(pass) < password.MinLength {
+		return errorext.ErrPasswordTooShort
+	}
+
+	if password.CharacterClasses(pass) < 3 {
+		return errorext.ErrPasswordTooWeak
+	}
+
+	if username != "" && strings.Contains(strings.ToLower(pass), strings.ToLower(username)) {
+		return errorext.ErrPasswordContainsUsername
+	}
+
+	return nil
+}
@@ -3,33 +3,41 @@ package ocserv
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 )
 
+const (
+	rxRateKey = "rx-data-per-sec"
+	txRateKey = "tx-data-per-sec"
+)
+
+var ErrConfigPerUserNotSet = errors.New("ocserv config-per-user directory is not set")
+
 type Client struct {
 	passwordFilepath string
+	configPerUserDir string
 }
 
+// CreateUser sets the password of the user, ocpasswd asks for the password twice, so it's written to its
+// stdin twice.
 func (c Client) CreateUser(ctx context.Context, username, password string) error {
 	cmd := exec.CommandContext(ctx, "ocpasswd", "-c", c.passwordFilepath, username)
-	if cmd.Err != nil {
-		return cmd.Err
-	}
+	cmd.Stdin = strings.NewReader(password + "\n" + password + "\n")
 
-	_, err := cmd.Stdout.Write([]byte(password))
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return run(cmd)
 }
 
 func (c Client) DisconnectUser(ctx context.Context, username string) error {
-	return exec.CommandContext(ctx, "occtl", "disconnect", "user", username).Err
+	return run(exec.CommandContext(ctx, "occtl", "disconnect", "user", username))
 }
 
 func (c Client) DisconnectID(ctx context.Context, id string) error {
-	return exec.CommandContext(ctx, "occtl", "disconnect", "id", id).Err
+	return run(exec.CommandContext(ctx, "occtl", "disconnect", "id", id))
 }
 
 func (c Client) GetConnections(ctx context.Context) ([]ConnectionEntity, error) {
@@ -53,11 +61,11 @@ func (c Client) GetConnections(ctx context.Context) ([]ConnectionEntity, error)
 }
 
 func (c Client) ShutdownServer(ctx context.Context) error {
-	return exec.CommandContext(ctx, "occtl", "stop", "now").Err
+	return run(exec.CommandContext(ctx, "occtl", "stop", "now"))
 }
 
 func (c Client) ChangePassword(ctx context.Context, username, password string) error {
-	err := exec.CommandContext(ctx, "ocpasswd", "-c", c.passwordFilepath, "--delete", username).Err
+	err := run(exec.CommandContext(ctx, "ocpasswd", "-c", c.passwordFilepath, "--delete", username))
 	if err != nil {
 		return err
 	}
@@ -66,17 +74,92 @@ func (c Client) ChangePassword(ctx context.Context, username, password string) e
 }
 
 func (c Client) LockUser(ctx context.Context, username string) error {
-	return exec.CommandContext(ctx, "ocpasswd", "-c", c.passwordFilepath, "-l", username).Err
+	return run(exec.CommandContext(ctx, "ocpasswd", "-c", c.passwordFilepath, "-l", username))
 }
 
 func (c Client) UnlockUser(ctx context.Context, username string) error {
-	return exec.CommandContext(ctx, "ocpasswd", "-c", c.passwordFilepath, "-u", username).Err
+	return run(exec.CommandContext(ctx, "ocpasswd", "-c", c.passwordFilepath, "-u", username))
+}
+
+// ThrottleUser limits the user's bandwidth to the given rate (in bytes per second) using ocserv's
+// config-per-user, the user gets disconnected once, so the client reconnects with the new config.
+func (c Client) ThrottleUser(ctx context.Context, username string, rate int) error {
+	if err := c.setUserRate(username, rate); err != nil {
+		return err
+	}
+
+	return c.DisconnectUser(ctx, username)
+}
+
+// UnthrottleUser removes the user's bandwidth limit, and disconnects the user, so the client reconnects
+// without the limit.
+func (c Client) UnthrottleUser(ctx context.Context, username string) error {
+	if err := c.setUserRate(username, 0); err != nil {
+		return err
+	}
+
+	return c.DisconnectUser(ctx, username)
+}
+
+// setUserRate replaces the rate lines of the user's config-per-user file, the rest of the config is kept
+// as it is. A zero rate removes the limit, and the file is removed if nothing else is left in it.
+func (c Client) setUserRate(username string, rate int) error {
+	if c.configPerUserDir == "" {
+		return ErrConfigPerUserNotSet
+	}
+
+	path := filepath.Join(c.configPerUserDir, filepath.Base(username))
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		key, _, _ := strings.Cut(line, "=")
+
+		switch strings.TrimSpace(key) {
+		case "", rxRateKey, txRateKey:
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if rate > 0 {
+		lines = append(lines, fmt.Sprintf("%s = %d", rxRateKey, rate), fmt.Sprintf("%s = %d", txRateKey, rate))
+	}
+
+	if len(lines) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
 }
 
-func NewClient(passwordFilepath string) *Client {
-	return &Client{passwordFilepath: passwordFilepath}
+// run runs the command, occtl and ocpasswd print the reason they've failed, so it's added to the error.
+func run(cmd *exec.Cmd) error {
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", filepath.Base(cmd.Path), err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+func NewClient(passwordFilepath, configPerUserDir string) *Client {
+	return &Client{
+		passwordFilepath: passwordFilepath,
+		configPerUserDir: configPerUserDir,
+	}
 }
 
+// CheckInstallation only looks the binaries up, running them isn't needed to know they're installed.
 func CheckInstallation(ctx context.Context) error {
 	err := exec.CommandContext(ctx, "ocserv").Err
 	if err != nil {
@@ -0,0 +1,70 @@
+package ocserv
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPort = 443
+
+	profileNamespace = "http://schemas.xmlsoap.org/encoding/"
+)
+
+// Profile is the AnyConnect client profile, OpenConnect clients can import it as well.
+type Profile struct {
+	XMLName    xml.Name    `xml:"AnyConnectProfile"`
+	Xmlns      string      `xml:"xmlns,attr"`
+	ServerList []HostEntry `xml:"ServerList>HostEntry"`
+}
+
+type HostEntry struct {
+	HostName    string `xml:"HostName"`
+	HostAddress string `xml:"HostAddress"`
+}
+
+// ServerAddress returns the address clients connect to, the port is left out if it's the default https port.
+func ServerAddress(host string, port int) string {
+	if port == 0 || port == defaultPort {
+		return host
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func NewProfileXML(name, address string) ([]byte, error) {
+	data, err := xml.MarshalIndent(Profile{
+		Xmlns:      profileNamespace,
+		ServerList: []HostEntry{{HostName: name, HostAddress: address}},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// OpenconnectCommand returns the command line that connects the user to the server, certPin is the
+// sha256 pin of the server certificate and is only needed for self-signed certificates.
+func OpenconnectCommand(address, username, certPin string) string {
+	args := []string{"sudo", "openconnect", "--user=" + username}
+
+	if certPin != "" {
+		args = append(args, "--servercert="+certPin)
+	}
+
+	return strings.Join(append(args, address), " ")
+}
+
+// AnyConnectURI returns the uri that adds the server to the AnyConnect mobile apps when it's opened.
+func AnyConnectURI(name, address string) string {
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("host", address)
+
+	return fmt.Sprintf("anyconnect://create/?%s", query.Encode())
+}
@@ -1,6 +1,9 @@
 package password
 
-import "math/rand/v2"
+import (
+	crand "crypto/rand"
+	"math/rand/v2"
+)
 
 var charSet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
@@ -15,3 +18,20 @@ func NewRandomPassword(length int) string {
 
 	return password
 }
+
+// codeCharSet leaves out the characters that are easily mistaken for each other, like 0 and O.
+var codeCharSet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// NewCode returns a random code that's easy to type, unlike NewRandomPassword it's safe to be used as a secret.
+func NewCode(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+
+	for i := range b {
+		b[i] = codeCharSet[int(b[i])%len(codeCharSet)]
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,77 @@
+package password
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// Seal encrypts the secret with a key derived from the token, the sealed secret can only be opened
+// by the token holder.
+func Seal(token, secret string) (string, error) {
+	gcm, err := newTokenCipher(token)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func Open(token, sealed string) (string, error) {
+	gcm, err := newTokenCipher(token)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("sealed secret is too short")
+	}
+
+	secret, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
+
+// NewToken returns a random url safe token of the given size in bytes.
+func NewToken(size int) (string, error) {
+	token := make([]byte, size)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// HashToken returns the hash tokens are looked up with, it's different from the key secrets are sealed with.
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte("lookup:" + token))
+
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func newTokenCipher(token string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte("seal:" + token))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
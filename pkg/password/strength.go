@@ -0,0 +1,26 @@
+package password
+
+import "unicode"
+
+// MinLength is the minimum length of the passwords users choose themselves.
+const MinLength = 8
+
+// CharacterClasses returns how many of lowercase letters, uppercase letters, digits and symbols the password has.
+func CharacterClasses(password string) int {
+	var lower, upper, digit, symbol int
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
@@ -4,13 +4,21 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"github.com/alir32a/jupiter/internal/errorext"
-	"github.com/charmbracelet/log"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 )
 
-const DefaultTimeoutInSecond = 5
+const (
+	DefaultTimeoutInSecond = 5
+
+	// SecretTokenHeader is the header telegram sends the webhook secret token in.
+	SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+	minPollingBackoff = time.Second
+	maxPollingBackoff = time.Minute
+)
 
 type Bot struct {
 	Token          string
@@ -18,27 +26,58 @@ type Bot struct {
 	lastFetchedID  int
 	baseUrl        string
 	client         *http.Client
+	outbox         *Outbox
+	done           chan struct{}
+	stopOnce       *sync.Once
 }
 
 func NewBot(token string) *Bot {
 	return &Bot{
-		Token:   token,
-		baseUrl: fmt.Sprintf("https://api.telegram.org/bot%s", token),
-		client:  &http.Client{},
+		Token:    token,
+		baseUrl:  fmt.Sprintf("https://api.telegram.org/bot%s", token),
+		client:   &http.Client{},
+		outbox:   NewOutbox(DefaultGlobalRate, DefaultChatRate, DefaultChatBurst),
+		done:     make(chan struct{}),
+		stopOnce: &sync.Once{},
 	}
 }
 
+// Metrics returns the metrics of the requests sent to the chats.
+func (b *Bot) Metrics() OutboxMetrics {
+	return b.outbox.Metrics()
+}
+
+// Run long polls the updates and passes them to the handler until the bot is stopped, failed polls are
+// retried with an exponential backoff.
 func (b *Bot) Run(handler func([]Update) error) {
+	backoff := minPollingBackoff
+
 	for {
+		select {
+		case <-b.done:
+			return
+		default:
+		}
+
 		updates, err := b.GetUpdates()
 		if err != nil {
 			if b.FailureHandler != nil {
 				b.FailureHandler(err)
 			}
 
+			select {
+			case <-b.done:
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(backoff*2, maxPollingBackoff)
+
 			continue
 		}
 
+		backoff = minPollingBackoff
+
 		setMessageTypes(updates)
 
 		if len(updates) > 0 {
@@ -51,174 +90,195 @@ func (b *Bot) Run(handler func([]Update) error) {
 	}
 }
 
-func (b *Bot) GetUpdates() ([]Update, error) {
-	resp, err := b.client.Get(getUpdatesUrl(b.baseUrl, b.lastFetchedID+1, DefaultTimeoutInSecond))
-	if err != nil {
-		return nil, err
-	}
+// Stop makes Run return once the current poll is over, the bot can still send requests.
+func (b *Bot) Stop() {
+	b.stopOnce.Do(func() {
+		close(b.done)
+	})
+}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+func (b *Bot) GetUpdates() ([]Update, error) {
+	return request[[]Update](b, "getUpdates", GetUpdatesRequest{
+		Offset:  b.lastFetchedID + 1,
+		Timeout: DefaultTimeoutInSecond,
+	})
+}
 
-	var result GetUpdatesResult
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, err
-	}
+// GetMe returns the bot's own user.
+func (b *Bot) GetMe() (From, error) {
+	return request[From](b, "getMe", nil)
+}
 
-	if !result.OK {
-		return nil, fmt.Errorf("got none OK status: %s", data)
-	}
+// SetWebhook makes telegram push the updates to the given url, the secret token is sent back in
+// the SecretTokenHeader of every request, so the receiver can make sure the request comes from telegram.
+func (b *Bot) SetWebhook(url, secretToken string) error {
+	_, err := request[bool](b, "setWebhook", SetWebhookRequest{
+		Url:         url,
+		SecretToken: secretToken,
+	})
 
-	return result.Updates, nil
+	return err
 }
 
-func (b *Bot) SendMessage(req SendMessageRequest) ([]Message, error) {
-	data, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
-	}
+// DeleteWebhook removes the webhook, telegram doesn't return any updates from getUpdates while a webhook is set.
+func (b *Bot) DeleteWebhook() error {
+	_, err := request[bool](b, "deleteWebhook", nil)
 
-	reader := bytes.NewReader(data)
+	return err
+}
 
-	resp, err := b.client.Post(fmt.Sprintf("%s/sendMessage", b.baseUrl), "application/json", reader)
-	if err != nil {
-		return nil, err
+// ParseUpdate parses an update pushed to the webhook.
+func ParseUpdate(data []byte) (Update, error) {
+	var update Update
+	if err := json.Unmarshal(data, &update); err != nil {
+		return Update{}, err
 	}
-	defer resp.Body.Close()
 
-	data, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	update.Message.Type = parseMessageType(update.Message.Entities)
 
-	var result SendMessageResponse
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, err
-	}
+	return update, nil
+}
 
-	if !result.OK {
-		return nil, fmt.Errorf("got none OK status: %s", data)
-	}
+func (b *Bot) SendMessage(req SendMessageRequest) (Message, error) {
+	return request[Message](b, "sendMessage", req)
+}
 
-	return result.Messages, nil
+func (b *Bot) EditMessageText(req EditMessageTextRequest) (Message, error) {
+	return request[Message](b, "editMessageText", req)
 }
 
-func (b *Bot) DeleteMessage(chatID, msgID int) error {
-	resp, err := b.client.Post(
-		fmt.Sprintf("%s/deleteMessage?chat_id=%d&message_id=%d", b.baseUrl, chatID, msgID),
-		"application/json", nil)
-	if err != nil {
-		return err
+// EditMessageReplyMarkup replaces the inline keyboard of the message, a nil keyboard removes it.
+func (b *Bot) EditMessageReplyMarkup(req EditMessageReplyMarkupRequest) (Message, error) {
+	if req.ReplyMarkup == nil {
+		req.ReplyMarkup = &InlineKeyboard{InlineKeyboard: [][]InlineKeyboardButton{}}
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer resp.Body.Close()
+	return request[Message](b, "editMessageReplyMarkup", req)
+}
 
-	var result DeleteMessageResponse
-	if err := json.Unmarshal(data, &result); err != nil {
-		return err
-	}
+func (b *Bot) DeleteMessage(chatID, msgID int) error {
+	_, err := request[bool](b, "deleteMessage", DeleteMessageRequest{
+		ChatID:    chatID,
+		MessageID: msgID,
+	})
 
-	if !result.OK || !result.Result {
-		return fmt.Errorf("got none OK status: %s", data)
-	}
+	return err
+}
 
-	return nil
+// GetChatMember returns the membership of the user in the chat, chatID is either the id of the chat or
+// the username of a channel (e.g. @jupiter).
+func (b *Bot) GetChatMember(chatID string, userID int) (ChatMember, error) {
+	return request[ChatMember](b, "getChatMember", GetChatMemberRequest{
+		ChatID: chatID,
+		UserID: userID,
+	})
 }
 
 func (b *Bot) SetCommands(commands ...BotCommand) error {
-	data, err := json.Marshal(sendBotCommands{
+	_, err := request[bool](b, "setMyCommands", sendBotCommands{
 		Commands: commands,
 		Scope:    commandScope{Type: "all_private_chats"},
 	})
-	if err != nil {
-		return err
-	}
 
-	reader := bytes.NewReader(data)
+	return err
+}
 
-	resp, err := b.client.Post(
-		fmt.Sprintf("%s/setMyCommands", b.baseUrl),
-		"application/json", reader)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+func (b *Bot) GetCommands() ([]BotCommand, error) {
+	return request[[]BotCommand](b, "getMyCommands", nil)
+}
 
-	var result sendBotCommands
-	if err := json.Unmarshal(data, &result); err != nil {
-		return err
-	}
+func (b *Bot) AnswerCallbackQuery(callbackQueryID, text string) error {
+	_, err := request[bool](b, "answerCallbackQuery", AnswerCallbackQueryRequest{
+		CallbackQueryID: callbackQueryID,
+		Text:            text,
+	})
 
-	if len(result.Commands) <= 0 {
-		return errorext.New("set command result is empty")
-	}
+	return err
+}
 
-	return nil
+// ShowAlert answers the callback query with an alert, which unlike messages isn't kept in the chat.
+func (b *Bot) ShowAlert(callbackQueryID, text string) error {
+	_, err := request[bool](b, "answerCallbackQuery", AnswerCallbackQueryRequest{
+		CallbackQueryID: callbackQueryID,
+		Text:            text,
+		ShowAlert:       true,
+	})
+
+	return err
 }
 
-func (b *Bot) GetCommands() ([]BotCommand, error) {
-	resp, err := b.client.Get(fmt.Sprintf("%s/getMyCommands", b.baseUrl))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// request calls the method with the params encoded as json, nil params send no body. Requests sent to
+// a chat go through the outbox.
+func request[T any](b *Bot, method string, params any) (T, error) {
+	var data []byte
+	if params != nil {
+		var err error
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+		data, err = json.Marshal(params)
+		if err != nil {
+			var zero T
 
-	var commands GetBotCommands
-	if err := json.Unmarshal(data, &commands); err != nil {
-		return nil, err
+			return zero, err
+		}
 	}
 
-	if !commands.OK {
-		return nil, fmt.Errorf("got none OK status: %s", data)
+	if req, ok := params.(chatRequest); ok {
+		return send[T](b, req.chat(), method, "application/json", data)
 	}
 
-	return commands.Commands, nil
+	return call[T](b, method, "application/json", bytes.NewReader(data))
 }
 
-func (b *Bot) AnswerCallbackQuery(callbackQueryID, text string) error {
-	data, err := json.Marshal(AnswerCallbackQueryRequest{
-		CallbackQueryID: callbackQueryID,
-		Text:            text,
-	})
-	if err != nil {
+// send calls the method through the outbox, the body is sent again if the request is retried.
+func send[T any](b *Bot, chatID int, method, contentType string, data []byte) (T, error) {
+	var result T
+
+	err := b.outbox.Do(chatID, func() error {
+		var err error
+
+		result, err = call[T](b, method, contentType, bytes.NewReader(data))
+
 		return err
-	}
+	})
 
-	reader := bytes.NewReader(data)
+	return result, err
+}
+
+// call posts the body to the method and decodes the result, failures are returned as *APIError.
+func call[T any](b *Bot, method, contentType string, body io.Reader) (T, error) {
+	var result apiResponse[T]
 
-	resp, err := b.client.Post(
-		fmt.Sprintf("%s/answerCallbackQuery", b.baseUrl),
-		"application/json", reader)
+	resp, err := b.client.Post(fmt.Sprintf("%s/%s", b.baseUrl, method), contentType, body)
 	if err != nil {
-		return err
+		return result.Result, err
 	}
 	defer resp.Body.Close()
 
-	var result AnswerCallbackQueryResponse
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return result.Result, err
+	}
+
 	if err := json.Unmarshal(data, &result); err != nil {
-		return err
+		return result.Result, fmt.Errorf("telegram %s: %w: %s", method, err, data)
 	}
 
 	if !result.OK {
-		return errorext.New("got none ok status from answerCallbackQuery")
+		apiErr := &APIError{
+			Method:      method,
+			Code:        result.ErrorCode,
+			Description: result.Description,
+		}
+
+		if result.Parameters != nil {
+			apiErr.RetryAfter = time.Duration(result.Parameters.RetryAfter) * time.Second
+			apiErr.MigrateToChatID = result.Parameters.MigrateToChatID
+		}
+
+		return result.Result, apiErr
 	}
 
-	return nil
+	return result.Result, nil
 }
 
 func setMessageTypes(updates []Update) {
@@ -244,7 +304,3 @@ func getMessages(updates []Update) []Message {
 
 	return result
 }
-
-func getUpdatesUrl(baseUrl string, offset, timeout int) string {
-	return fmt.Sprintf("%s/getUpdates?offset=%d&timeout=%d", baseUrl, offset, timeout)
-}
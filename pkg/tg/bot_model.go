@@ -12,11 +12,32 @@ const (
 
 	ParseModeMarkdown = "MarkdownV2"
 	ParseModeHTML     = "HTML"
+
+	ChatMemberStatusCreator       = "creator"
+	ChatMemberStatusAdministrator = "administrator"
+	ChatMemberStatusMember        = "member"
+	ChatMemberStatusRestricted    = "restricted"
+	ChatMemberStatusLeft          = "left"
+	ChatMemberStatusKicked        = "kicked"
 )
 
-type GetUpdatesResult struct {
-	OK      bool     `json:"ok"`
-	Updates []Update `json:"result"`
+// apiResponse is the envelope of every response, Result is only set if OK is true.
+type apiResponse[T any] struct {
+	OK          bool                `json:"ok"`
+	Result      T                   `json:"result"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+type ResponseParameters struct {
+	MigrateToChatID int `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int `json:"retry_after,omitempty"`
+}
+
+type GetUpdatesRequest struct {
+	Offset  int `json:"offset"`
+	Timeout int `json:"timeout"`
 }
 
 type Update struct {
@@ -25,6 +46,32 @@ type Update struct {
 	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
 }
 
+// ChatID returns the id of the chat the update belongs to.
+func (u Update) ChatID() int {
+	if u.CallbackQuery != nil {
+		if u.CallbackQuery.Message.Chat.ID != 0 {
+			return u.CallbackQuery.Message.Chat.ID
+		}
+
+		return u.CallbackQuery.From.ID
+	}
+
+	if u.Message.Chat.ID != 0 {
+		return u.Message.Chat.ID
+	}
+
+	return u.Message.From.ID
+}
+
+// Sender returns the user who has sent the update.
+func (u Update) Sender() From {
+	if u.CallbackQuery != nil {
+		return u.CallbackQuery.From
+	}
+
+	return u.Message.From
+}
+
 type CallbackQuery struct {
 	ID           string  `json:"id"`
 	From         From    `json:"from"`
@@ -40,7 +87,9 @@ type Message struct {
 	Date      int      `json:"date"`
 	Text      string   `json:"text"`
 	Entities  []Entity `json:"entities"`
-	Type      string   `json:"-"`
+	// ReplyToMessage is the message this one replies to, it doesn't have its own reply.
+	ReplyToMessage *Message `json:"reply_to_message,omitempty"`
+	Type           string   `json:"-"`
 }
 
 type From struct {
@@ -84,14 +133,97 @@ type SendMessageRequest struct {
 	ReplyMarkup         *InlineKeyboard `json:"reply_markup,omitempty"`
 }
 
-type SendMessageResponse struct {
-	OK       bool      `json:"ok"`
-	Messages []Message `json:"result"`
+// chatRequest is a request sent to a chat, which is subject to the rate limits of the chat.
+type chatRequest interface {
+	chat() int
+}
+
+func (r SendMessageRequest) chat() int {
+	return r.ChatID
+}
+
+type EditMessageTextRequest struct {
+	ChatID      int             `json:"chat_id"`
+	MessageID   int             `json:"message_id"`
+	Text        string          `json:"text"`
+	ParseMode   string          `json:"parse_mode,omitempty"`
+	ReplyMarkup *InlineKeyboard `json:"reply_markup,omitempty"`
+}
+
+func (r EditMessageTextRequest) chat() int {
+	return r.ChatID
+}
+
+type EditMessageReplyMarkupRequest struct {
+	ChatID      int             `json:"chat_id"`
+	MessageID   int             `json:"message_id"`
+	ReplyMarkup *InlineKeyboard `json:"reply_markup,omitempty"`
 }
 
-type DeleteMessageResponse struct {
-	OK     bool `json:"ok,omitempty"`
-	Result bool `json:"result,omitempty"`
+func (r EditMessageReplyMarkupRequest) chat() int {
+	return r.ChatID
+}
+
+type DeleteMessageRequest struct {
+	ChatID    int `json:"chat_id"`
+	MessageID int `json:"message_id"`
+}
+
+func (r DeleteMessageRequest) chat() int {
+	return r.ChatID
+}
+
+type GetChatMemberRequest struct {
+	ChatID string `json:"chat_id"`
+	UserID int    `json:"user_id"`
+}
+
+type ChatMember struct {
+	Status string `json:"status"`
+	User   From   `json:"user"`
+	// IsMember is only set for restricted members.
+	IsMember bool `json:"is_member,omitempty"`
+}
+
+// InChat reports whether the user is currently a member of the chat.
+func (c ChatMember) InChat() bool {
+	switch c.Status {
+	case ChatMemberStatusCreator, ChatMemberStatusAdministrator, ChatMemberStatusMember:
+		return true
+	case ChatMemberStatusRestricted:
+		return c.IsMember
+	default:
+		return false
+	}
+}
+
+// InputFile is a file uploaded to telegram, Name is the name the file is shown with.
+type InputFile struct {
+	Name string
+	Data []byte
+}
+
+type SendPhotoRequest struct {
+	ChatID         int
+	Photo          InputFile
+	Caption        string
+	ParseMode      string
+	ProtectContent bool
+	ReplyMarkup    *InlineKeyboard
+}
+
+type SendDocumentRequest struct {
+	ChatID         int
+	Document       InputFile
+	Caption        string
+	ParseMode      string
+	ProtectContent bool
+	ReplyMarkup    *InlineKeyboard
+}
+
+type SetWebhookRequest struct {
+	Url         string `json:"url"`
+	SecretToken string `json:"secret_token,omitempty"`
 }
 
 type BotCommand struct {
@@ -108,23 +240,26 @@ type sendBotCommands struct {
 	Scope    commandScope `json:"scope"`
 }
 
-type GetBotCommands struct {
-	OK       bool         `json:"ok"`
-	Commands []BotCommand `json:"result"`
-}
-
 type AnswerCallbackQueryRequest struct {
 	CallbackQueryID string `json:"callback_query_id"`
 	Text            string `json:"text"`
 	ShowAlert       bool   `json:"show_alert,omitempty"`
 }
 
-type AnswerCallbackQueryResponse struct {
-	OK     bool           `json:"ok"`
-	Result map[string]any `json:"result,omitempty"`
-}
-
 func NewInlineKeyboard(buttons ...InlineKeyboardButton) *InlineKeyboard {
 	return &InlineKeyboard{
 		InlineKeyboard: append([][]InlineKeyboardButton{}, append([]InlineKeyboardButton{}, buttons...))}
 }
+
+// NewInlineKeyboardRows returns a keyboard with a row for each of the rows, empty rows are left out.
+func NewInlineKeyboardRows(rows ...[]InlineKeyboardButton) *InlineKeyboard {
+	keyboard := &InlineKeyboard{InlineKeyboard: make([][]InlineKeyboardButton, 0, len(rows))}
+
+	for _, row := range rows {
+		if len(row) > 0 {
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
+		}
+	}
+
+	return keyboard
+}
@@ -0,0 +1,407 @@
+package tg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAPI is a fake telegram bot api, it records the last request of each method and responds with the
+// configured response.
+type fakeAPI struct {
+	t         *testing.T
+	mu        sync.Mutex
+	responses map[string]string
+	headers   map[string]http.Header
+	bodies    map[string][]byte
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *Bot) {
+	api := &fakeAPI{
+		t:         t,
+		responses: make(map[string]string),
+		headers:   make(map[string]http.Header),
+		bodies:    make(map[string][]byte),
+	}
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	bot := NewBot("token")
+	bot.baseUrl = server.URL + "/bottoken"
+
+	return api, bot
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/bottoken/")
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	f.mu.Lock()
+	f.headers[method] = r.Header.Clone()
+	f.bodies[method] = body
+	resp, ok := f.responses[method]
+	f.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		resp = `{"ok":false,"error_code":404,"description":"Not Found"}`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(resp))
+}
+
+func (f *fakeAPI) body(method string) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.bodies[method]
+}
+
+func (f *fakeAPI) header(method string) http.Header {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.headers[method]
+}
+
+func (f *fakeAPI) decodeBody(method string, v any) {
+	f.t.Helper()
+
+	if err := json.Unmarshal(f.body(method), v); err != nil {
+		f.t.Fatalf("decoding %s request: %v", method, err)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["sendMessage"] = `{"ok":true,"result":{"message_id":42,"chat":{"id":7},"text":"hi"}}`
+
+	msg, err := bot.SendMessage(SendMessageRequest{ChatID: 7, Text: "hi", ParseMode: ParseModeHTML})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.MessageID != 42 || msg.Chat.ID != 7 {
+		t.Errorf("got message %+v", msg)
+	}
+
+	var req SendMessageRequest
+	api.decodeBody("sendMessage", &req)
+
+	if req.ChatID != 7 || req.Text != "hi" || req.ParseMode != ParseModeHTML {
+		t.Errorf("sent request %+v", req)
+	}
+
+	if contentType := api.header("sendMessage").Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("sent content type %s", contentType)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       string
+		wantCode       int
+		wantRetryAfter time.Duration
+		wantForbidden  bool
+	}{
+		{
+			name:           "flood",
+			response:       `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 3","parameters":{"retry_after":3}}`,
+			wantCode:       http.StatusTooManyRequests,
+			wantRetryAfter: 3 * time.Second,
+		},
+		{
+			name:          "blocked",
+			response:      `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`,
+			wantCode:      http.StatusForbidden,
+			wantForbidden: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, bot := newFakeAPI(t)
+			api.responses["sendMessage"] = tt.response
+			bot.outbox.MaxRetries = 0
+
+			_, err := bot.SendMessage(SendMessageRequest{ChatID: 7, Text: "hi"})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("got error %v, want *APIError", err)
+			}
+
+			if apiErr.Method != "sendMessage" || apiErr.Code != tt.wantCode {
+				t.Errorf("got error %+v", apiErr)
+			}
+
+			retryAfter, ok := RetryAfter(err)
+			if retryAfter != tt.wantRetryAfter || ok != (tt.wantRetryAfter > 0) {
+				t.Errorf("got retry after %s %v, want %s", retryAfter, ok, tt.wantRetryAfter)
+			}
+
+			if IsForbidden(err) != tt.wantForbidden {
+				t.Errorf("got forbidden %v, want %v", IsForbidden(err), tt.wantForbidden)
+			}
+		})
+	}
+}
+
+func TestEditMessageText(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["editMessageText"] = `{"ok":true,"result":{"message_id":42,"chat":{"id":7},"text":"edited"}}`
+
+	msg, err := bot.EditMessageText(EditMessageTextRequest{
+		ChatID:      7,
+		MessageID:   42,
+		Text:        "edited",
+		ReplyMarkup: NewInlineKeyboard(InlineKeyboardButton{Text: "back", CallbackData: "b"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Text != "edited" {
+		t.Errorf("got message %+v", msg)
+	}
+
+	var req EditMessageTextRequest
+	api.decodeBody("editMessageText", &req)
+
+	if req.MessageID != 42 || req.ReplyMarkup == nil || req.ReplyMarkup.InlineKeyboard[0][0].CallbackData != "b" {
+		t.Errorf("sent request %+v", req)
+	}
+}
+
+func TestEditMessageReplyMarkupRemovesKeyboard(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["editMessageReplyMarkup"] = `{"ok":true,"result":{"message_id":42,"chat":{"id":7}}}`
+
+	if _, err := bot.EditMessageReplyMarkup(EditMessageReplyMarkupRequest{ChatID: 7, MessageID: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := string(api.body("editMessageReplyMarkup")); !strings.Contains(body, `"reply_markup":{"inline_keyboard":[]}`) {
+		t.Errorf("sent body %s", body)
+	}
+}
+
+func TestSendDocument(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["sendDocument"] = `{"ok":true,"result":{"message_id":43,"chat":{"id":7}}}`
+
+	msg, err := bot.SendDocument(SendDocumentRequest{
+		ChatID:   7,
+		Document: InputFile{Name: "profile.xml", Data: []byte("<profile/>")},
+		Caption:  "profile",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.MessageID != 43 {
+		t.Errorf("got message %+v", msg)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/sendDocument", bytes.NewReader(api.body("sendDocument")))
+	req.Header = api.header("sendDocument")
+
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+
+	if req.FormValue("chat_id") != "7" || req.FormValue("caption") != "profile" {
+		t.Errorf("sent fields %v", req.MultipartForm.Value)
+	}
+
+	file, header, err := req.FormFile("document")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Filename != "profile.xml" || string(data) != "<profile/>" {
+		t.Errorf("sent file %s with %s", header.Filename, data)
+	}
+}
+
+func TestGetMe(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["getMe"] = `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Jupiter","username":"jupiter_bot"}}`
+
+	me, err := bot.GetMe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !me.IsBot || me.Username != "jupiter_bot" {
+		t.Errorf("got user %+v", me)
+	}
+}
+
+func TestGetChatMember(t *testing.T) {
+	tests := []struct {
+		response string
+		want     bool
+	}{
+		{response: `{"status":"member","user":{"id":5}}`, want: true},
+		{response: `{"status":"creator","user":{"id":5}}`, want: true},
+		{response: `{"status":"restricted","user":{"id":5},"is_member":true}`, want: true},
+		{response: `{"status":"restricted","user":{"id":5},"is_member":false}`, want: false},
+		{response: `{"status":"left","user":{"id":5}}`, want: false},
+		{response: `{"status":"kicked","user":{"id":5}}`, want: false},
+	}
+
+	for _, tt := range tests {
+		api, bot := newFakeAPI(t)
+		api.responses["getChatMember"] = `{"ok":true,"result":` + tt.response + `}`
+
+		member, err := bot.GetChatMember("@jupiter", 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if member.InChat() != tt.want {
+			t.Errorf("%s: got in chat %v, want %v", tt.response, member.InChat(), tt.want)
+		}
+
+		var req GetChatMemberRequest
+		api.decodeBody("getChatMember", &req)
+
+		if req.ChatID != "@jupiter" || req.UserID != 5 {
+			t.Errorf("sent request %+v", req)
+		}
+	}
+}
+
+func TestSetCommands(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["setMyCommands"] = `{"ok":true,"result":true}`
+
+	if err := bot.SetCommands(BotCommand{Command: "/start", Description: "start"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var req sendBotCommands
+	api.decodeBody("setMyCommands", &req)
+
+	if len(req.Commands) != 1 || req.Commands[0].Command != "/start" || req.Scope.Type != "all_private_chats" {
+		t.Errorf("sent request %+v", req)
+	}
+}
+
+func TestAnswerCallbackQuery(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["answerCallbackQuery"] = `{"ok":true,"result":true}`
+
+	if err := bot.ShowAlert("query-id", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	var req AnswerCallbackQueryRequest
+	api.decodeBody("answerCallbackQuery", &req)
+
+	if req.CallbackQueryID != "query-id" || req.Text != "secret" || !req.ShowAlert {
+		t.Errorf("sent request %+v", req)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["setWebhook"] = `{"ok":true,"result":true}`
+
+	if err := bot.SetWebhook("https://example.com/hook", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	var req SetWebhookRequest
+	api.decodeBody("setWebhook", &req)
+
+	if req.Url != "https://example.com/hook" || req.SecretToken != "secret" {
+		t.Errorf("sent request %+v", req)
+	}
+
+	if err := bot.DeleteWebhook(); err == nil {
+		t.Error("got no error for an unknown method")
+	}
+}
+
+func TestDeleteMessage(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["deleteMessage"] = `{"ok":false,"error_code":400,"description":"Bad Request: message to delete not found"}`
+
+	err := bot.DeleteMessage(7, 42)
+	if err == nil {
+		t.Fatal("got no error")
+	}
+
+	var req DeleteMessageRequest
+	api.decodeBody("deleteMessage", &req)
+
+	if req.ChatID != 7 || req.MessageID != 42 {
+		t.Errorf("sent request %+v", req)
+	}
+}
+
+func TestGetUpdates(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["getUpdates"] = `{"ok":true,"result":[{"update_id":10,"message":{"message_id":1,"text":"/start",` +
+		`"entities":[{"offset":0,"length":6,"type":"bot_command"}]}}]}`
+
+	bot.lastFetchedID = 9
+
+	updates, err := bot.GetUpdates()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(updates) != 1 || updates[0].UpdateID != 10 {
+		t.Fatalf("got updates %+v", updates)
+	}
+
+	var req GetUpdatesRequest
+	api.decodeBody("getUpdates", &req)
+
+	if req.Offset != 10 {
+		t.Errorf("sent offset %d, want 10", req.Offset)
+	}
+}
+
+func TestRunStops(t *testing.T) {
+	api, bot := newFakeAPI(t)
+	api.responses["getUpdates"] = `{"ok":true,"result":[]}`
+
+	done := make(chan struct{})
+	go func() {
+		bot.Run(func([]Update) error { return nil })
+		close(done)
+	}()
+
+	bot.Stop()
+	bot.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run didn't return after Stop")
+	}
+}
@@ -0,0 +1,61 @@
+package tg
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Bots keeps the bots run by the same process by their ids, so the requests about a chat can be sent
+// through the bot the chat belongs to.
+type Bots struct {
+	mu   sync.RWMutex
+	bots map[int]*Bot
+}
+
+func NewBots() *Bots {
+	return &Bots{bots: make(map[int]*Bot)}
+}
+
+func (b *Bots) Add(id int, bot *Bot) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bots[id] = bot
+}
+
+func (b *Bots) Remove(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.bots, id)
+}
+
+func (b *Bots) Get(id int) (*Bot, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bot, ok := b.bots[id]
+	if !ok {
+		return nil, fmt.Errorf("bot %d is not running", id)
+	}
+
+	return bot, nil
+}
+
+func (b *Bots) SendMessage(botID int, req SendMessageRequest) (Message, error) {
+	bot, err := b.Get(botID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return bot.SendMessage(req)
+}
+
+func (b *Bots) DeleteMessage(botID, chatID, msgID int) error {
+	bot, err := b.Get(botID)
+	if err != nil {
+		return err
+	}
+
+	return bot.DeleteMessage(chatID, msgID)
+}
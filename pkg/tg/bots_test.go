@@ -0,0 +1,34 @@
+package tg
+
+import "testing"
+
+func TestBotsRoutesByID(t *testing.T) {
+	mainAPI, mainBot := newFakeAPI(t)
+	mainAPI.responses["sendMessage"] = `{"ok":true,"result":{"message_id":1,"chat":{"id":7}}}`
+
+	resellerAPI, resellerBot := newFakeAPI(t)
+	resellerAPI.responses["sendMessage"] = `{"ok":true,"result":{"message_id":2,"chat":{"id":7}}}`
+
+	bots := NewBots()
+	bots.Add(0, mainBot)
+	bots.Add(3, resellerBot)
+
+	msg, err := bots.SendMessage(3, SendMessageRequest{ChatID: 7, Text: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.MessageID != 2 {
+		t.Errorf("message was sent through the wrong bot, got %+v", msg)
+	}
+
+	if mainAPI.body("sendMessage") != nil {
+		t.Error("main bot sent a message for another bot")
+	}
+
+	bots.Remove(3)
+
+	if _, err := bots.SendMessage(3, SendMessageRequest{ChatID: 7, Text: "hi"}); err == nil {
+		t.Error("removed bot is still used")
+	}
+}
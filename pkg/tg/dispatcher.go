@@ -0,0 +1,121 @@
+package tg
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+const (
+	DefaultDispatcherWorkers   = 8
+	DefaultDispatcherQueueSize = 64
+)
+
+type HandlerFunc func(update Update) error
+
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Dispatcher handles the updates concurrently using a pool of workers, updates of the same chat always go
+// to the same worker, so they're handled in the order they were received.
+type Dispatcher struct {
+	ErrorHandler func(update Update, err error)
+	handler      HandlerFunc
+	queues       []chan Update
+	startOnce    sync.Once
+	wg           sync.WaitGroup
+	// mu guards stopped, so updates aren't queued to the closed queues.
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func NewDispatcher(workers int, handler HandlerFunc, middlewares ...Middleware) *Dispatcher {
+	if workers <= 0 {
+		workers = DefaultDispatcherWorkers
+	}
+
+	// the first middleware is the outermost one.
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	queues := make([]chan Update, workers)
+	for i := range queues {
+		queues[i] = make(chan Update, DefaultDispatcherQueueSize)
+	}
+
+	return &Dispatcher{
+		handler: handler,
+		queues:  queues,
+	}
+}
+
+// Start starts the workers, calling it more than once is a no-op.
+func (d *Dispatcher) Start() {
+	d.startOnce.Do(func() {
+		for _, queue := range d.queues {
+			d.wg.Add(1)
+
+			go d.work(queue)
+		}
+	})
+}
+
+// Stop stops accepting updates and waits for the queued ones to be handled, calling it more than once is
+// a no-op.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+
+		return
+	}
+
+	d.stopped = true
+
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+// Dispatch queues the updates, it blocks only when the worker of a chat is too far behind. updates
+// dispatched after Stop are dropped.
+func (d *Dispatcher) Dispatch(updates ...Update) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.stopped {
+		return
+	}
+
+	for _, update := range updates {
+		chatID := update.ChatID()
+		if chatID < 0 {
+			chatID = -chatID
+		}
+
+		d.queues[chatID%len(d.queues)] <- update
+	}
+}
+
+func (d *Dispatcher) work(queue chan Update) {
+	defer d.wg.Done()
+
+	for update := range queue {
+		if err := d.handle(update); err != nil && d.ErrorHandler != nil {
+			d.ErrorHandler(update, err)
+		}
+	}
+}
+
+func (d *Dispatcher) handle(update Update) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while handling update %d: %v\n%s", update.UpdateID, r, debug.Stack())
+		}
+	}()
+
+	return d.handler(update)
+}
@@ -0,0 +1,142 @@
+package tg
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newChatUpdate(id, chatID int) Update {
+	return Update{UpdateID: id, Message: Message{Chat: Chat{ID: chatID}}}
+}
+
+func TestDispatcherKeepsChatOrder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		handled []int
+	)
+
+	dispatcher := NewDispatcher(4, func(update Update) error {
+		mu.Lock()
+		handled = append(handled, update.UpdateID)
+		mu.Unlock()
+
+		return nil
+	})
+	dispatcher.Start()
+
+	var want []int
+	for i := 1; i <= 100; i++ {
+		dispatcher.Dispatch(newChatUpdate(i, 7))
+		want = append(want, i)
+	}
+
+	dispatcher.Stop()
+
+	if !slices.Equal(handled, want) {
+		t.Errorf("got updates handled in %v, want %v", handled, want)
+	}
+}
+
+func TestDispatcherHandlesChatsConcurrently(t *testing.T) {
+	release := make(chan struct{})
+
+	var blocked atomic.Bool
+
+	// the update of chat 1 waits for the update of chat 2, which can only be handled by another worker.
+	dispatcher := NewDispatcher(2, func(update Update) error {
+		if update.ChatID() == 2 {
+			close(release)
+
+			return nil
+		}
+
+		select {
+		case <-release:
+		case <-time.After(time.Second):
+			blocked.Store(true)
+		}
+
+		return nil
+	})
+	dispatcher.Start()
+
+	dispatcher.Dispatch(newChatUpdate(1, 1), newChatUpdate(2, 2))
+	dispatcher.Stop()
+
+	if blocked.Load() {
+		t.Error("the update of chat 2 waited for the update of chat 1")
+	}
+}
+
+func TestDispatcherRecoversFromPanic(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		handled []int
+		errs    []error
+	)
+
+	dispatcher := NewDispatcher(1, func(update Update) error {
+		if update.UpdateID == 1 {
+			panic("boom")
+		}
+
+		mu.Lock()
+		handled = append(handled, update.UpdateID)
+		mu.Unlock()
+
+		return errors.New("failed")
+	})
+	dispatcher.ErrorHandler = func(update Update, err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+	dispatcher.Start()
+
+	dispatcher.Dispatch(newChatUpdate(1, 7), newChatUpdate(2, 7))
+	dispatcher.Stop()
+
+	if !slices.Equal(handled, []int{2}) {
+		t.Errorf("got updates %v handled after the panic, want [2]", handled)
+	}
+
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), "panic while handling update 1: boom") ||
+		errs[1].Error() != "failed" {
+		t.Errorf("got errors %v", errs)
+	}
+}
+
+func TestDispatcherStopDrainsQueue(t *testing.T) {
+	var handled atomic.Int32
+
+	dispatcher := NewDispatcher(2, func(update Update) error {
+		time.Sleep(10 * time.Millisecond)
+		handled.Add(1)
+
+		return nil
+	})
+	dispatcher.Start()
+
+	for i := 1; i <= 10; i++ {
+		dispatcher.Dispatch(newChatUpdate(i, i))
+	}
+
+	dispatcher.Stop()
+
+	if got := handled.Load(); got != 10 {
+		t.Errorf("got %d updates handled when stopped, want 10", got)
+	}
+
+	// updates dispatched after stop are dropped, and stopping again is a no-op.
+	dispatcher.Dispatch(newChatUpdate(11, 11))
+	dispatcher.Stop()
+
+	if got := handled.Load(); got != 10 {
+		t.Errorf("got %d updates handled after stop, want 10", got)
+	}
+}
@@ -0,0 +1,55 @@
+package tg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError is the error telegram responds with when a method fails.
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	// RetryAfter is how long to wait before the request can be repeated, it's set when the bot is
+	// flooding telegram with requests.
+	RetryAfter time.Duration
+	// MigrateToChatID is the new id of a group that has been migrated to a supergroup.
+	MigrateToChatID int
+}
+
+func (e *APIError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("telegram %s: %d %s (retry after %s)", e.Method, e.Code, e.Description, e.RetryAfter)
+	}
+
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+// RetryAfter reports how long to wait before retrying the request that failed with err, if telegram has
+// asked for it.
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+
+	return 0, false
+}
+
+// IsForbidden reports whether the bot isn't allowed to message the chat, e.g. the user has blocked the bot.
+func IsForbidden(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden
+}
+
+// IsNotModified reports whether an edit failed because the message already has the same content.
+func IsNotModified(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest &&
+		strings.Contains(apiErr.Description, "message is not modified")
+}
@@ -0,0 +1,33 @@
+package tg
+
+import (
+	"html"
+	"strings"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+	">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// EscapeMarkdown escapes the characters that are reserved in MarkdownV2.
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// EscapeHTML escapes the characters that are reserved in telegram's HTML parse mode.
+func EscapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// Escape escapes the text for the parse mode, text without a parse mode is returned as it is.
+func Escape(parseMode, s string) string {
+	switch parseMode {
+	case ParseModeMarkdown:
+		return EscapeMarkdown(s)
+	case ParseModeHTML:
+		return EscapeHTML(s)
+	default:
+		return s
+	}
+}
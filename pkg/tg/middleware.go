@@ -0,0 +1,72 @@
+package tg
+
+import (
+	"errors"
+	"github.com/charmbracelet/log"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("update is not authorized")
+	ErrRateLimited  = errors.New("too many updates, try again later")
+)
+
+// LoggingMiddleware logs every update with the time it took to handle it.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(update Update) error {
+			start := time.Now()
+
+			err := next(update)
+
+			logger.Debug("handled update", "update_id", update.UpdateID, "chat_id", update.ChatID(),
+				"took", time.Since(start), "err", err)
+
+			return err
+		}
+	}
+}
+
+// RateLimitMiddleware allows at most limit updates per chat in each window, the rest are rejected
+// with ErrRateLimited.
+func RateLimitMiddleware(limit int, window time.Duration) Middleware {
+	var (
+		mu          sync.Mutex
+		counts      = make(map[int]int)
+		windowStart = time.Now()
+	)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(update Update) error {
+			mu.Lock()
+			if time.Since(windowStart) >= window {
+				clear(counts)
+				windowStart = time.Now()
+			}
+
+			counts[update.ChatID()]++
+			count := counts[update.ChatID()]
+			mu.Unlock()
+
+			if count > limit {
+				return ErrRateLimited
+			}
+
+			return next(update)
+		}
+	}
+}
+
+// AuthMiddleware rejects the updates the authorize func doesn't allow with ErrUnauthorized.
+func AuthMiddleware(authorize func(update Update) bool) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(update Update) error {
+			if !authorize(update) {
+				return ErrUnauthorized
+			}
+
+			return next(update)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package tg
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultGlobalRate is telegram's limit of messages per second to all chats.
+	DefaultGlobalRate = 30
+	// DefaultChatRate is telegram's limit of messages per second to a single chat, short bursts are allowed.
+	DefaultChatRate  = 1
+	DefaultChatBurst = 3
+	// DefaultMaxRetries is how many times a request is retried after telegram responds with retry_after.
+	DefaultMaxRetries = 3
+
+	// maxIdleChats is how many chat buckets are kept before the idle ones are dropped.
+	maxIdleChats = 10000
+)
+
+// OutboxMetrics is a snapshot of the requests that have gone through the outbox.
+type OutboxMetrics struct {
+	Sent        int64
+	Failed      int64
+	Retried     int64
+	RateLimited int64
+	// Pending is the number of requests waiting for their turn.
+	Pending int64
+	// Waited is the total time the requests have waited for their turn.
+	Waited time.Duration
+}
+
+// Outbox throttles the outgoing requests with a token bucket per chat and a global one, so the bot stays
+// within telegram's limits, requests that still get rate limited are retried after the time telegram asks for.
+type Outbox struct {
+	MaxRetries int
+
+	mu        sync.Mutex
+	global    *tokenBucket
+	chats     map[int]*tokenBucket
+	chatRate  float64
+	chatBurst float64
+
+	sent        atomic.Int64
+	failed      atomic.Int64
+	retried     atomic.Int64
+	rateLimited atomic.Int64
+	pending     atomic.Int64
+	waited      atomic.Int64
+}
+
+func NewOutbox(globalRate, chatRate float64, chatBurst int) *Outbox {
+	return &Outbox{
+		MaxRetries: DefaultMaxRetries,
+		global:     newTokenBucket(globalRate, globalRate),
+		chats:      make(map[int]*tokenBucket),
+		chatRate:   chatRate,
+		chatBurst:  float64(chatBurst),
+	}
+}
+
+// Do waits for the turn of the chat and sends the request, a chatID of 0 is only limited by the global bucket.
+func (o *Outbox) Do(chatID int, send func() error) error {
+	o.pending.Add(1)
+	defer o.pending.Add(-1)
+
+	for attempt := 0; ; attempt++ {
+		if wait := o.reserve(chatID, time.Now()); wait > 0 {
+			o.waited.Add(int64(wait))
+
+			time.Sleep(wait)
+		}
+
+		err := send()
+
+		retryAfter, ok := RetryAfter(err)
+		if !ok {
+			if err != nil {
+				o.failed.Add(1)
+			} else {
+				o.sent.Add(1)
+			}
+
+			return err
+		}
+
+		o.rateLimited.Add(1)
+		o.pause(chatID, time.Now().Add(retryAfter))
+
+		if attempt >= o.MaxRetries {
+			o.failed.Add(1)
+
+			return err
+		}
+
+		o.retried.Add(1)
+	}
+}
+
+func (o *Outbox) Metrics() OutboxMetrics {
+	return OutboxMetrics{
+		Sent:        o.sent.Load(),
+		Failed:      o.failed.Load(),
+		Retried:     o.retried.Load(),
+		RateLimited: o.rateLimited.Load(),
+		Pending:     o.pending.Load(),
+		Waited:      time.Duration(o.waited.Load()),
+	}
+}
+
+// reserve takes a token from the buckets of the chat and returns how long to wait until it's available.
+func (o *Outbox) reserve(chatID int, now time.Time) time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	wait := o.global.reserve(now)
+
+	if chatID != 0 {
+		wait = max(wait, o.chat(chatID, now).reserve(now))
+	}
+
+	return wait
+}
+
+// pause stops the requests to the chat until the given time, or all the requests if there's no chat.
+func (o *Outbox) pause(chatID int, until time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if chatID == 0 {
+		o.global.pause(until)
+
+		return
+	}
+
+	o.chat(chatID, time.Now()).pause(until)
+}
+
+func (o *Outbox) chat(chatID int, now time.Time) *tokenBucket {
+	bucket, ok := o.chats[chatID]
+	if ok {
+		return bucket
+	}
+
+	if len(o.chats) >= maxIdleChats {
+		for id, b := range o.chats {
+			if b.idle(now) {
+				delete(o.chats, id)
+			}
+		}
+	}
+
+	bucket = newTokenBucket(o.chatRate, o.chatBurst)
+	o.chats[chatID] = bucket
+
+	return bucket
+}
+
+// tokenBucket refills rate tokens per second up to burst, tokens go negative when they're reserved ahead
+// of time, so concurrent requests are spread out instead of all waiting for the same token.
+type tokenBucket struct {
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  max(burst, 1),
+		tokens: max(burst, 1),
+	}
+}
+
+func (t *tokenBucket) reserve(now time.Time) time.Duration {
+	t.refill(now)
+	t.tokens--
+
+	var wait time.Duration
+	if t.tokens < 0 {
+		wait = time.Duration(-t.tokens / t.rate * float64(time.Second))
+	}
+
+	return max(wait, t.pausedUntil.Sub(now))
+}
+
+func (t *tokenBucket) pause(until time.Time) {
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+func (t *tokenBucket) refill(now time.Time) {
+	if !t.last.IsZero() {
+		t.tokens = min(t.burst, t.tokens+now.Sub(t.last).Seconds()*t.rate)
+	}
+
+	t.last = now
+}
+
+func (t *tokenBucket) idle(now time.Time) bool {
+	t.refill(now)
+
+	return t.tokens >= t.burst && now.After(t.pausedUntil)
+}
@@ -0,0 +1,100 @@
+package tg
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(1, 2)
+
+	for i, want := range []time.Duration{0, 0, time.Second, 2 * time.Second} {
+		if got := bucket.reserve(now); got != want {
+			t.Errorf("reservation %d: got wait %s, want %s", i, got, want)
+		}
+	}
+
+	// three seconds refill three tokens, two of them pay back the reservations ahead of time.
+	if got := bucket.reserve(now.Add(3 * time.Second)); got != 0 {
+		t.Errorf("got wait %s after refill, want 0", got)
+	}
+
+	bucket.pause(now.Add(10 * time.Second))
+
+	if got := bucket.reserve(now.Add(5 * time.Second)); got != 5*time.Second {
+		t.Errorf("got wait %s while paused, want 5s", got)
+	}
+}
+
+func TestOutboxChatLimits(t *testing.T) {
+	now := time.Now()
+	outbox := NewOutbox(30, 1, 1)
+
+	if wait := outbox.reserve(1, now); wait != 0 {
+		t.Errorf("got wait %s for the first message, want 0", wait)
+	}
+
+	if wait := outbox.reserve(1, now); wait != time.Second {
+		t.Errorf("got wait %s for the second message to the chat, want 1s", wait)
+	}
+
+	if wait := outbox.reserve(2, now); wait != 0 {
+		t.Errorf("got wait %s for another chat, want 0", wait)
+	}
+}
+
+func TestOutboxRetriesAfterRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":1}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":7}}}`))
+	}))
+	defer server.Close()
+
+	bot := NewBot("token")
+	bot.baseUrl = server.URL
+
+	start := time.Now()
+
+	if _, err := bot.SendMessage(SendMessageRequest{ChatID: 7, Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least 1s", elapsed)
+	}
+
+	metrics := bot.Metrics()
+	if calls.Load() != 2 || metrics.Sent != 1 || metrics.Retried != 1 || metrics.RateLimited != 1 {
+		t.Errorf("got %d calls and metrics %+v", calls.Load(), metrics)
+	}
+}
+
+func TestOutboxGivesUp(t *testing.T) {
+	outbox := NewOutbox(DefaultGlobalRate, DefaultChatRate, DefaultChatBurst)
+	outbox.MaxRetries = 0
+
+	rateLimited := &APIError{Method: "sendMessage", Code: http.StatusTooManyRequests, RetryAfter: time.Second}
+
+	err := outbox.Do(7, func() error {
+		return rateLimited
+	})
+	if !errors.Is(err, rateLimited) {
+		t.Fatalf("got error %v", err)
+	}
+
+	if metrics := outbox.Metrics(); metrics.Failed != 1 || metrics.Retried != 0 {
+		t.Errorf("got metrics %+v", metrics)
+	}
+}
@@ -0,0 +1,57 @@
+package tg
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the data templates are executed with, lists are passed as []TemplateData.
+type TemplateData map[string]any
+
+// RenderTemplate executes the text template, every value of the data is escaped for the parse mode
+// before execution, so only the template itself can contain markup.
+func RenderTemplate(body, parseMode string, data TemplateData) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, escapeTemplateData(parseMode, data)); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func escapeTemplateData(parseMode string, data TemplateData) TemplateData {
+	result := make(TemplateData, len(data))
+
+	for key, value := range data {
+		result[key] = escapeTemplateValue(parseMode, value)
+	}
+
+	return result
+}
+
+func escapeTemplateValue(parseMode string, value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case bool:
+		// kept as it is, so it can be used in conditions.
+		return v
+	case TemplateData:
+		return escapeTemplateData(parseMode, v)
+	case []TemplateData:
+		result := make([]TemplateData, 0, len(v))
+		for _, item := range v {
+			result = append(result, escapeTemplateData(parseMode, item))
+		}
+
+		return result
+	default:
+		return Escape(parseMode, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,82 @@
+package tg
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"strconv"
+)
+
+func (b *Bot) SendPhoto(req SendPhotoRequest) (Message, error) {
+	fields, err := mediaFields(req.ChatID, req.Caption, req.ParseMode, req.ProtectContent, req.ReplyMarkup)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return b.upload(req.ChatID, "sendPhoto", fields, "photo", req.Photo)
+}
+
+func (b *Bot) SendDocument(req SendDocumentRequest) (Message, error) {
+	fields, err := mediaFields(req.ChatID, req.Caption, req.ParseMode, req.ProtectContent, req.ReplyMarkup)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return b.upload(req.ChatID, "sendDocument", fields, "document", req.Document)
+}
+
+// upload sends the file along with the fields as a multipart form, which is how telegram accepts new files.
+func (b *Bot) upload(chatID int, method string, fields map[string]string, fileField string, file InputFile) (Message, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return Message{}, err
+		}
+	}
+
+	part, err := writer.CreateFormFile(fileField, file.Name)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if _, err := part.Write(file.Data); err != nil {
+		return Message{}, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return Message{}, err
+	}
+
+	return send[Message](b, chatID, method, writer.FormDataContentType(), body.Bytes())
+}
+
+func mediaFields(chatID int, caption, parseMode string, protectContent bool, keyboard *InlineKeyboard) (map[string]string, error) {
+	fields := map[string]string{
+		"chat_id": strconv.Itoa(chatID),
+	}
+
+	if caption != "" {
+		fields["caption"] = caption
+	}
+
+	if parseMode != "" {
+		fields["parse_mode"] = parseMode
+	}
+
+	if protectContent {
+		fields["protect_content"] = "true"
+	}
+
+	if keyboard != nil {
+		markup, err := json.Marshal(keyboard)
+		if err != nil {
+			return nil, err
+		}
+
+		fields["reply_markup"] = string(markup)
+	}
+
+	return fields, nil
+}
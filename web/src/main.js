@@ -5,6 +5,11 @@ import HomePage from "./components/HomePage.vue";
 import PackagesPage from "./components/PackagesPage.vue";
 import {createRouter, createWebHistory} from "vue-router";
 import UsersPage from "./components/UsersPage.vue";
+import BroadcastsPage from "./components/BroadcastsPage.vue";
+import TicketsPage from "./components/TicketsPage.vue";
+import BotsPage from "./components/BotsPage.vue";
+import ResellersPage from "./components/ResellersPage.vue";
+import MessageTemplatesPage from "./components/MessageTemplatesPage.vue";
 import OcservPage from "./components/OcservPage.vue";
 import ChangePasswordPage from "./components/ChangePasswordPage.vue";
 import LogOutPage from "./components/LogOutPage.vue";
@@ -25,7 +30,12 @@ const routes = [
             { path: "", component: HomePage },
             { path: "packages", component: PackagesPage },
             { path: "users", component: UsersPage },
+            { path: "broadcasts", component: BroadcastsPage },
+            { path: "tickets", component: TicketsPage },
+            { path: "bots", component: BotsPage },
+            { path: "resellers", component: ResellersPage },
             { path: "ocserv", component: OcservPage },
+            { path: "message-templates", component: MessageTemplatesPage },
             { path: "change-password", component: ChangePasswordPage },
             { path: "logout", component: LogOutPage },
         ]